@@ -9,6 +9,7 @@ package assets
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 // Constant and non modifiable errors.
@@ -24,7 +25,35 @@ var (
 	InvalidFirebaseProjectID    = errors.New(`Provided firebase-project-id is invalid(or empty)`)
 	FirebaseServiceKeyNotExists = errors.New(`Firebase service key file doesn't exists at given path`)
 	InvalidFirebaseCollection   = errors.New(`Provided firebase-collection-id is invalid`)
+	InvalidS3Bucket             = errors.New(`Provided s3-bucket is invalid(or empty)`)
+	S3CredentialsNotExists      = errors.New(`S3 credentials file doesn't exists at given path`)
+	S3BucketNotExists           = errors.New(`S3 bucket doesn't exists, or isn't accessible via provided credentials`)
+	InvalidWebDAVURL            = errors.New(`Provided webdav-url is invalid(or empty)`)
+	InvalidSFTPSettings         = errors.New(`Provided sftp-host or sftp-path is invalid(or empty)`)
+	SSHKeyNotExists             = errors.New(`SSH private key file doesn't exists at given path`)
+	SSHAgentNotAvailable        = errors.New(`SSH agent isn't available, provide sftp-key-file or run ssh-agent`)
+	InvalidHTTPURL              = errors.New(`Provided http-url is invalid(or empty)`)
+	InvalidHTTPToken            = errors.New(`Provided http-token isn't accepted by notya server`)
 	InvalidPathForAct           = errors.New(`Generated or provided path is invalid for this action`)
+	GitNotInstalled             = errors.New(`Git is not installed, or couldn't be found in $PATH`)
+	CommitNotAvailable          = errors.New(`Atomic commit is not available for this service`)
+	TagsNotIndexed              = errors.New(`Tags of notes aren't indexed at the service yet`)
+	EmptySearchQuery            = errors.New(`Search query is empty, please provide a text to search`)
+	IndexNotAvailable           = errors.New(`Search index is available only for local and sqlite services`)
+	TrashNotAvailable           = errors.New(`Trash is not available for this service`)
+	HistoryNotAvailable         = errors.New(`Version history is not available for this service`)
+	CannotDecrypt               = errors.New(`Cannot decrypt the body, passphrase is wrong or body is corrupted`)
+	WrongPassphrase             = errors.New(`Wrong passphrase, it doesn't match the passphrase of encryption`)
+	PassphraseMismatch          = errors.New(`Passphrases don't match`)
+	EmptyPassphrase             = errors.New(`Passphrase is empty`)
+	InvalidEncryption           = errors.New(`Invalid encryption, use one of: all, remote`)
+	CannotMoveVault             = errors.New(`Folders cannot be moved into, out of or between vaults`)
+	InputRequired               = errors.New(`Input is required, but prompts are disabled by --no-input (or --json)`)
+	EditorRequired              = errors.New(`Editor cannot be opened, since prompts are disabled by --no-input (or --json)`)
+	EmptyInput                  = errors.New(`Input is empty`)
+	NoBodyInput                 = errors.New(`Nothing to write, provide it via --body, --file or stdin`)
+	TitleRequired               = errors.New(`Title of note is required, when its body is piped`)
+	TemplateWithBody            = errors.New(`Template cannot be used together with a provided body`)
 )
 
 // NotExists returns a formatted error message as data-not-exists error.
@@ -51,3 +80,82 @@ func CannotDoSth(act, doc string, err error) error {
 		fmt.Sprintf("Cannot %v %v | %v", act, doc, err.Error()),
 	)
 }
+
+// RolledBack generates an error message for failed action, which's changes were rolled back.
+func RolledBack(act, service string, restored int) error {
+	return errors.New(
+		fmt.Sprintf("Cannot %v to %v, rolled back to its snapshot of %v nodes", act, service, restored),
+	)
+}
+
+// InvalidSortField returns a formatted error message for unknown sort fields.
+func InvalidSortField(field string) error {
+	return errors.New(
+		fmt.Sprintf("Cannot sort by %q, use one of: title, created, modified, size", field),
+	)
+}
+
+// InvalidAge returns a formatted error message for unparsable ages, like: "7d", "12h".
+func InvalidAge(age string) error {
+	return errors.New(
+		fmt.Sprintf("Invalid age %q, use a number with unit, like: 30m, 12h, 7d, 2w", age),
+	)
+}
+
+// NotInTrash returns a formatted error message for titles, that couldn't be found at trash.
+func NotInTrash(title string) error {
+	return errors.New(
+		fmt.Sprintf("There is no %q at trash", title),
+	)
+}
+
+// InvalidRevision returns a formatted error message for unparsable revisions, like: "latest".
+func InvalidRevision(rev string) error {
+	return errors.New(
+		fmt.Sprintf("Invalid revision %q, use a revision number from history, like: 3 or r3", rev),
+	)
+}
+
+// VersionNotExists returns a formatted error message for missing revisions of note.
+// Zero [rev] means that note has no versions at all.
+func VersionNotExists(title string, rev int) error {
+	if rev == 0 {
+		return errors.New(fmt.Sprintf("%v has no earlier versions", title))
+	}
+
+	return errors.New(fmt.Sprintf("%v has no revision r%v", title, rev))
+}
+
+// UnknownService returns a formatted error message for unknown service types.
+func UnknownService(service string, available []string) error {
+	return errors.New(
+		fmt.Sprintf("Unknown service %q, use one of: %v", service, strings.Join(available, ", ")),
+	)
+}
+
+// VaultLocked returns a formatted error message for nodes of locked vault folders.
+func VaultLocked(folder string) error {
+	return errors.New(
+		fmt.Sprintf("Vault %q is locked, unlock it via: notya vault unlock %v", folder, folder),
+	)
+}
+
+// InsecureVaultSessions returns a formatted error message for directories of vault sessions,
+// which are owned or accessible by other users.
+func InsecureVaultSessions(dir string) error {
+	return errors.New(
+		fmt.Sprintf("Vault sessions cannot be kept at %q, it must be owned and accessible only by you", dir),
+	)
+}
+
+// NotVault returns a formatted error message for folders, that aren't vaults.
+func NotVault(folder string) error {
+	return errors.New(fmt.Sprintf("There is no vault at %q", folder))
+}
+
+// VaultOverlaps returns a formatted error message for vault folders, which overlap with an existing vault.
+func VaultOverlaps(folder, vault string) error {
+	return errors.New(
+		fmt.Sprintf("Cannot create a vault at %q, it overlaps with vault %q", folder, vault),
+	)
+}
@@ -93,3 +93,94 @@ func TestCannotDoSth(t *testing.T) {
 	}
 
 }
+
+func TestRolledBack(t *testing.T) {
+	tests := []struct {
+		act, service string
+		restored     int
+		expected     error
+	}{
+		{
+			act: "migrate", service: "FIREBASE", restored: 3,
+			expected: errors.New("Cannot migrate to FIREBASE, rolled back to its snapshot of 3 nodes"),
+		},
+	}
+
+	for _, td := range tests {
+		got := assets.RolledBack(td.act, td.service, td.restored)
+		if got.Error() != td.expected.Error() {
+			t.Errorf("Sum of RolledBack was different: Want: %v, Got: %v", td.expected, got)
+		}
+	}
+}
+
+func TestNotInTrash(t *testing.T) {
+	expected := `There is no "todo.md" at trash`
+	if got := assets.NotInTrash("todo.md"); got.Error() != expected {
+		t.Errorf("Sum of NotInTrash was different: Want: %v, Got: %v", expected, got)
+	}
+}
+
+func TestVersionErrors(t *testing.T) {
+	tests := []struct {
+		got      error
+		expected string
+	}{
+		{got: assets.InvalidRevision("latest"), expected: `Invalid revision "latest", use a revision number from history, like: 3 or r3`},
+		{got: assets.VersionNotExists("todo.md", 0), expected: "todo.md has no earlier versions"},
+		{got: assets.VersionNotExists("todo.md", 3), expected: "todo.md has no revision r3"},
+	}
+
+	for _, td := range tests {
+		if td.got.Error() != td.expected {
+			t.Errorf("Sum of version error was different: Want: %v, Got: %v", td.expected, td.got)
+		}
+	}
+}
+
+func TestUnknownService(t *testing.T) {
+	expected := `Unknown service "dropbox", use one of: LOCAL, FIREBASE`
+	if got := assets.UnknownService("dropbox", []string{"LOCAL", "FIREBASE"}); got.Error() != expected {
+		t.Errorf("Sum of UnknownService was different: Want: %v, Got: %v", expected, got)
+	}
+}
+
+func TestInvalidSortField(t *testing.T) {
+	expected := `Cannot sort by "color", use one of: title, created, modified, size`
+	if got := assets.InvalidSortField("color"); got.Error() != expected {
+		t.Errorf("Sum of InvalidSortField was different: Want: %v, Got: %v", expected, got)
+	}
+}
+
+func TestInvalidAge(t *testing.T) {
+	expected := `Invalid age "soon", use a number with unit, like: 30m, 12h, 7d, 2w`
+	if got := assets.InvalidAge("soon"); got.Error() != expected {
+		t.Errorf("Sum of InvalidAge was different: Want: %v, Got: %v", expected, got)
+	}
+}
+
+func TestVaultErrors(t *testing.T) {
+	tests := []struct {
+		got      error
+		expected string
+	}{
+		{
+			got:      assets.VaultLocked("secrets/"),
+			expected: `Vault "secrets/" is locked, unlock it via: notya vault unlock secrets/`,
+		},
+		{
+			got:      assets.NotVault("ideas/"),
+			expected: `There is no vault at "ideas/"`,
+		},
+		{
+			got:      assets.VaultOverlaps("secrets/keys/", "secrets/"),
+			expected: `Cannot create a vault at "secrets/keys/", it overlaps with vault "secrets/"`,
+		},
+	}
+
+	for _, td := range tests {
+		if td.got.Error() != td.expected {
+			t.Errorf("Sum of vault error was different: Want: %v, Got: %v", td.expected, td.got)
+		}
+	}
+}
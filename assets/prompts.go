@@ -47,6 +47,14 @@ var MkdirPromptQuestion = []*survey.Question{
 	},
 }
 
+// SearchPromptQuestion is a question list for search command.
+var SearchPromptQuestion = []*survey.Question{
+	{
+		Prompt:   &survey.Input{Message: "Search"},
+		Validate: survey.MinLength(1),
+	},
+}
+
 // OpenViaEditorPromt is a confirm prompt for editor editing.
 var OpenViaEditorPromt = &survey.Confirm{
 	Message: "Wanna open with editor?",
@@ -59,6 +67,23 @@ func NewNamePrompt(d string) *survey.Input {
 	return &survey.Input{Message: "New name: ", Default: d}
 }
 
+// PassphrasePrompt is a password prompt for passphrases of encryption and vaults.
+// [env] is the environment variable, that could provide the passphrase instead.
+func PassphrasePrompt(msg, env string) *survey.Password {
+	return &survey.Password{
+		Message: msg,
+		Help:    fmt.Sprintf("Could be provided via %v environment variable too.", env),
+	}
+}
+
+// TemplateVarPrompt is a input prompt for variables of note templates.
+func TemplateVarPrompt(name string) *survey.Input {
+	return &survey.Input{
+		Message: name,
+		Help:    fmt.Sprintf("Value of {{.%v}} at the template. Could be provided via --var %v=value too.", name, name),
+	}
+}
+
 // MoveNotesPrompt is a confirm prompt for setting's move-note functionality.
 var MoveNotesPrompt = &survey.Confirm{
 	Message: "Move notes",
@@ -95,3 +120,132 @@ var FirebaseRemoteConnectPromptQuestion = []*survey.Question{
 		Validate: survey.MinLength(1),
 	},
 }
+
+// S3RemoteConnectPromptQuestion is a question list that fills up
+// required values for S3 compatible object storage connection.
+// Used in Remote command's connect subcommand.
+var S3RemoteConnectPromptQuestion = []*survey.Question{
+	{
+		Name: "s3_endpoint",
+		Prompt: &survey.Input{
+			Message: "S3 Endpoint",
+			Help:    "The endpoint of object storage, like: s3.amazonaws.com, or http://localhost:9000 for a local MinIO.",
+			Default: "s3.amazonaws.com",
+		},
+		Validate: survey.MinLength(1),
+	},
+	{
+		Name: "s3_bucket",
+		Prompt: &survey.Input{
+			Message: "S3 Bucket",
+			Help:    "A name of an existing bucket for notes.",
+		},
+		Validate: survey.MinLength(3),
+	},
+	{
+		Name: "s3_region",
+		Prompt: &survey.Input{
+			Message: "S3 Region",
+			Help:    "The region of bucket, like: eu-central-1. Could be left empty for MinIO.",
+		},
+	},
+	{
+		Name: "s3_credentials_file",
+		Prompt: &survey.Input{
+			Message: "S3 Credentials File",
+			Help:    "The AWS shared credentials file path, like: /Users/john-doe/.aws/credentials. If it's empty, credentials are taken from environment variables.",
+		},
+	},
+	{
+		Name: "s3_prefix",
+		Prompt: &survey.Input{
+			Message: "S3 Prefix",
+			Help:    "A key prefix of notes at bucket. Name of application is used, if it's empty.",
+		},
+	},
+}
+
+// WebDAVRemoteConnectPromptQuestion is a question list that fills up
+// required values for WebDAV server connection.
+// Used in Remote command's connect subcommand.
+var WebDAVRemoteConnectPromptQuestion = []*survey.Question{
+	{
+		Name: "webdav_url",
+		Prompt: &survey.Input{
+			Message: "WebDAV URL",
+			Help:    "The URL of collection for notes, like: https://cloud.example.com/remote.php/dav/files/john-doe/notya/.",
+		},
+		Validate: survey.MinLength(8),
+	},
+	{
+		Name: "webdav_username",
+		Prompt: &survey.Input{
+			Message: "WebDAV Username",
+			Help:    "The username of WebDAV server. Could be left empty for servers without authentication.",
+		},
+	},
+	{
+		Name: "webdav_password",
+		Prompt: &survey.Password{
+			Message: "WebDAV Password",
+			Help:    "The password (or app password) of WebDAV server. If it's empty, password is taken from NOTYA_WEBDAV_PASSWORD environment variable.",
+		},
+	},
+}
+
+// SFTPRemoteConnectPromptQuestion is a question list that fills up
+// required values for SFTP (SSH) server connection.
+// Used in Remote command's connect subcommand.
+var SFTPRemoteConnectPromptQuestion = []*survey.Question{
+	{
+		Name: "sftp_host",
+		Prompt: &survey.Input{
+			Message: "SSH Host",
+			Help:    "The host of SSH server, like: dev.example.com or dev.example.com:2222. Host key has to be at ~/.ssh/known_hosts.",
+		},
+		Validate: survey.MinLength(1),
+	},
+	{
+		Name: "sftp_user",
+		Prompt: &survey.Input{
+			Message: "SSH User",
+			Help:    "The user of SSH server. If it's empty, current user of machine is used.",
+		},
+	},
+	{
+		Name: "sftp_key_file",
+		Prompt: &survey.Input{
+			Message: "SSH Private Key File",
+			Help:    "The full path of private key file, like: /User/john-doe/.ssh/id_ed25519. If it's empty, keys of ssh-agent are used.",
+		},
+	},
+	{
+		Name: "sftp_path",
+		Prompt: &survey.Input{
+			Message: "Remote Notes Path",
+			Help:    "The remote directory of notes, like: /home/john-doe/notya.",
+		},
+		Validate: survey.MinLength(1),
+	},
+}
+
+// HTTPRemoteConnectPromptQuestion is a question list that fills up
+// required values for notya server connection.
+// Used in Remote command's connect subcommand.
+var HTTPRemoteConnectPromptQuestion = []*survey.Question{
+	{
+		Name: "http_url",
+		Prompt: &survey.Input{
+			Message: "Notya Server URL",
+			Help:    "The base URL of notya server (started via `notya serve`), like: https://notes.example.com.",
+		},
+		Validate: survey.MinLength(8),
+	},
+	{
+		Name: "http_token",
+		Prompt: &survey.Password{
+			Message: "Notya Server Token",
+			Help:    "The access token of notya server. If it's empty, token is taken from NOTYA_HTTP_TOKEN environment variable.",
+		},
+	},
+}
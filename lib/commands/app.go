@@ -8,6 +8,7 @@ package commands
 
 import (
 	"os"
+	"strings"
 
 	"github.com/insolite-dev/notya/assets"
 	"github.com/insolite-dev/notya/lib/models"
@@ -18,28 +19,67 @@ import (
 
 var (
 	// Main spin animator of application.
-	loading = pkg.Spinner()
+	loading = &pkg.Loader{Spinner: pkg.Spinner()}
 
 	// stdargs is the global std arguments-state of application.
 	stdargs models.StdArgs = models.StdArgs{Stdin: os.Stdin, Stdout: os.Stdout, Stderr: os.Stderr}
+
+	// flushIndexes writes search indexes, that were updated by the command (see [services.BatchIndexes]).
+	flushIndexes func() error
 )
 
 var (
 	service      services.ServiceRepo // default/active service of all commands.
 	localService services.ServiceRepo // default/main service.
 	fireService  services.ServiceRepo // firebase integrated service.
+	gitService   services.ServiceRepo // git versioned local service.
+	sqlService   services.ServiceRepo // sqlite database file service.
+	s3Service    services.ServiceRepo // S3 compatible object storage service.
+	davService   services.ServiceRepo // WebDAV server service.
+	sftpService  services.ServiceRepo // SFTP (SSH) server service.
+	httpService  services.ServiceRepo // notya server service.
 )
 
 // serviceFromType returns type appropriate service instance.
 func serviceFromType(t string, enable bool) services.ServiceRepo {
 	switch t {
 	case services.LOCAL.ToStr():
-		return localService
+		return secured(localService, false)
 	case services.FIRE.ToStr():
 		if enable {
 			setupFirebaseService()
 		}
-		return fireService
+		return secured(fireService, true)
+	case services.GIT.ToStr():
+		if enable {
+			setupGitService()
+		}
+		return secured(gitService, false)
+	case services.SQLITE.ToStr():
+		if enable {
+			setupSQLiteService()
+		}
+		return secured(sqlService, false)
+	case services.S3.ToStr():
+		if enable {
+			setupS3Service()
+		}
+		return secured(s3Service, true)
+	case services.WEBDAV.ToStr():
+		if enable {
+			setupWebDAVService()
+		}
+		return secured(davService, true)
+	case services.SFTP.ToStr():
+		if enable {
+			setupSFTPService()
+		}
+		return secured(sftpService, true)
+	case services.HTTP.ToStr():
+		if enable {
+			setupHTTPService()
+		}
+		return secured(httpService, true)
 	}
 
 	return service
@@ -48,6 +88,28 @@ func serviceFromType(t string, enable bool) services.ServiceRepo {
 // Decides whether use firebase service as main service or not.
 var firebaseF bool
 
+// Decides whether use git service as main service or not.
+var gitF bool
+
+// Decides whether use sqlite service as main service or not.
+var sqliteF bool
+
+// Decides whether use S3 service as main service or not.
+var s3F bool
+
+// Decides whether use WebDAV service as main service or not.
+var webdavF bool
+
+// Decides whether use SFTP service as main service or not.
+var sftpF bool
+
+// Decides whether use HTTP service as main service or not.
+var httpF bool
+
+// Decides whether only print the change plan of push, fetch and migrate,
+// without changing anything, or not.
+var dryRun bool
+
 // appCommand is the root command of application and genesis of all sub-commands.
 var appCommand = &cobra.Command{
 	Use:     "notya",
@@ -56,6 +118,15 @@ var appCommand = &cobra.Command{
 		assets.MinimalisticBanner,
 		assets.ShortSlog,
 	),
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		setupOutput(strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" "))
+
+		// Search index is written once, at the end of command.
+		flushIndexes = services.BatchIndexes()
+
+		setupLocalService()
+		service = localService
+	},
 }
 
 // initCommands initializes all sub-commands of application.
@@ -64,6 +135,38 @@ func initCommands() {
 		&firebaseF, "firebase", "f", false,
 		"Run commands base on firebase service",
 	)
+	appCommand.PersistentFlags().BoolVarP(
+		&gitF, "git", "g", false,
+		"Run commands base on git service (commits each change of notes)",
+	)
+	appCommand.PersistentFlags().BoolVar(
+		&sqliteF, "sqlite", false,
+		"Run commands base on sqlite service (keeps notes at a single database file)",
+	)
+	appCommand.PersistentFlags().BoolVar(
+		&s3F, "s3", false,
+		"Run commands base on S3 compatible object storage service",
+	)
+	appCommand.PersistentFlags().BoolVar(
+		&webdavF, "webdav", false,
+		"Run commands base on WebDAV service (Nextcloud, ownCloud and etc.)",
+	)
+	appCommand.PersistentFlags().BoolVar(
+		&sftpF, "sftp", false,
+		"Run commands base on SFTP service (keeps notes at a directory of remote host, over SSH)",
+	)
+	appCommand.PersistentFlags().BoolVar(
+		&httpF, "http", false,
+		"Run commands base on HTTP service (works on notes of another notya instance, started via `notya serve`)",
+	)
+	appCommand.PersistentFlags().BoolVar(
+		&jsonF, "json", false,
+		"Print one structured JSON result, without prompting (implies --no-input)",
+	)
+	appCommand.PersistentFlags().BoolVar(
+		&noInputF, "no-input", false,
+		"Fail with a non-zero exit code, instead of prompting",
+	)
 
 	initSetupCommand()
 	initSettingsCommand()
@@ -72,13 +175,26 @@ func initCommands() {
 	initRemoveCommand()
 	initViewCommand()
 	initEditCommand()
+	initAppendCommand()
+	initTemplateCommand()
+	initJournalCommand()
 	initRenameCommand()
 	initListCommand()
+	initTagsCommand()
+	initLinksCommand()
+	initSearchCommand()
+	initIndexCommand()
 	initCopyCommand()
 	initFetchCommand()
 	initPushCommand()
+	initSyncCommand()
+	initServeCommand()
 	initMigrateCommand()
 	initCutCommand()
+	initTrashCommand()
+	initHistoryCommand()
+	initEncryptCommand()
+	initVaultCommand()
 	initRemoteCommand()
 }
 
@@ -87,32 +203,109 @@ func initCommands() {
 //
 // Usually used in [cmd/app.go].
 func ExecuteApp() {
-	loading.Start()
-
 	initCommands()
 
-	setupLocalService()
-	service = localService
-
-	_ = appCommand.Execute()
+	err := appCommand.Execute()
+	finish(err)
 }
 
 // determineService checks user input service after execution main command.
 // if user has provided a custom service for specific command-execution, it updates
 // the [service] value with that custom-service[fireService ... etc].
 func determineService() {
+	if gitF {
+		setupGitService()
+		service = secured(gitService, false)
+		return
+	}
+
+	if sqliteF {
+		setupSQLiteService()
+		service = secured(sqlService, false)
+		return
+	}
+
+	if s3F {
+		setupS3Service()
+		service = secured(s3Service, true)
+		return
+	}
+
+	if webdavF {
+		setupWebDAVService()
+		service = secured(davService, true)
+		return
+	}
+
+	if sftpF {
+		setupSFTPService()
+		service = secured(sftpService, true)
+		return
+	}
+
+	if httpF {
+		setupHTTPService()
+		service = secured(httpService, true)
+		return
+	}
+
 	if !firebaseF {
+		service = secured(localService, false)
 		return
 	}
 
 	setupFirebaseService()
-	service = fireService
+	service = secured(fireService, true)
 
 	//
 	// TODO: implement other services.
 	//
 }
 
+// selectService takes the service to work with from arguments, or asks for service selection if it's not provided.
+// Current service is not available to select.
+func selectService(args []string) (string, error) {
+	available := []string{}
+	for _, s := range services.Services {
+		if service.Type() != s {
+			available = append(available, s)
+		}
+	}
+
+	if len(args) == 0 {
+		var selected string
+		ask(assets.ChooseRemotePrompt(available), &selected)
+
+		return selected, nil
+	}
+
+	for _, s := range available {
+		if strings.EqualFold(s, args[0]) {
+			return s, nil
+		}
+	}
+
+	return "", assets.UnknownService(args[0], available)
+}
+
+// initPlanFlags adds dry-run related flags to given [cmd].
+func initPlanFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVarP(
+		&dryRun, "dry-run", "n", false,
+		"Print the plan of changes, without changing anything",
+	)
+}
+
+// printPlan logs the given plan of changes, as a colored list (or keeps it at result at JSON mode).
+func printPlan(plan models.Plan, err error) {
+	if err != nil {
+		alert(pkg.ErrorL, err.Error())
+		return
+	}
+
+	printData(plan, len(plan.Changes), func() { pkg.PrintPlan(plan) })
+}
+
 // setupLocalService initializes the local service.
 // makes it able at [localService] instance.
 func setupLocalService() {
@@ -124,7 +317,8 @@ func setupLocalService() {
 	loading.Stop()
 
 	if err != nil {
-		pkg.Alert(pkg.ErrorL, err.Error())
+		alert(pkg.ErrorL, err.Error())
+		finish(nil)
 		os.Exit(1)
 	}
 }
@@ -140,7 +334,110 @@ func setupFirebaseService() {
 	loading.Stop()
 
 	if err != nil {
-		pkg.Alert(pkg.ErrorL, err.Error())
+		alert(pkg.ErrorL, err.Error())
+		finish(nil)
+		os.Exit(1)
+	}
+}
+
+// setupGitService initializes the git service.
+// makes it able at [gitService] instance.
+func setupGitService() {
+	loading.Start()
+
+	gitService = services.NewGitService(stdargs)
+	err := gitService.Init(nil)
+
+	loading.Stop()
+
+	if err != nil {
+		alert(pkg.ErrorL, err.Error())
+		finish(nil)
+		os.Exit(1)
+	}
+}
+
+// setupSQLiteService initializes the sqlite service.
+// makes it able at [sqlService] instance.
+func setupSQLiteService() {
+	loading.Start()
+
+	sqlService = services.NewSQLiteService(stdargs, localService)
+	err := sqlService.Init(nil)
+
+	loading.Stop()
+
+	if err != nil {
+		alert(pkg.ErrorL, err.Error())
+		finish(nil)
+		os.Exit(1)
+	}
+}
+
+// setupS3Service initializes the S3 service.
+// makes it able at [s3Service] instance.
+func setupS3Service() {
+	loading.Start()
+
+	s3Service = services.NewS3Service(stdargs, localService)
+	err := s3Service.Init(nil)
+
+	loading.Stop()
+
+	if err != nil {
+		alert(pkg.ErrorL, err.Error())
+		finish(nil)
+		os.Exit(1)
+	}
+}
+
+// setupWebDAVService initializes the WebDAV service.
+// makes it able at [davService] instance.
+func setupWebDAVService() {
+	loading.Start()
+
+	davService = services.NewWebDAVService(stdargs, localService)
+	err := davService.Init(nil)
+
+	loading.Stop()
+
+	if err != nil {
+		alert(pkg.ErrorL, err.Error())
+		finish(nil)
+		os.Exit(1)
+	}
+}
+
+// setupSFTPService initializes the SFTP service.
+// makes it able at [sftpService] instance.
+func setupSFTPService() {
+	loading.Start()
+
+	sftpService = services.NewSFTPService(stdargs, localService)
+	err := sftpService.Init(nil)
+
+	loading.Stop()
+
+	if err != nil {
+		alert(pkg.ErrorL, err.Error())
+		finish(nil)
+		os.Exit(1)
+	}
+}
+
+// setupHTTPService initializes the HTTP service.
+// makes it able at [httpService] instance.
+func setupHTTPService() {
+	loading.Start()
+
+	httpService = services.NewHTTPService(stdargs, localService)
+	err := httpService.Init(nil)
+
+	loading.Stop()
+
+	if err != nil {
+		alert(pkg.ErrorL, err.Error())
+		finish(nil)
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,148 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package commands
+
+import (
+	"io"
+	"os"
+
+	"github.com/insolite-dev/notya/assets"
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/pkg"
+	"github.com/spf13/cobra"
+)
+
+// appendCommand is a command model which used to add text to the end of notes.
+var appendCommand = &cobra.Command{
+	Use:   "append",
+	Short: "Append text to the end of note, from stdin, --body or --file",
+	Run: func(cmd *cobra.Command, args []string) {
+		runWriteCommand(args, "append", models.AppendBody)
+	},
+}
+
+// prependCommand is a command model which used to add text to the beginning of notes.
+var prependCommand = &cobra.Command{
+	Use:   "prepend",
+	Short: "Prepend text to the beginning of note, from stdin, --body or --file",
+	Run: func(cmd *cobra.Command, args []string) {
+		runWriteCommand(args, "prepend", models.PrependBody)
+	},
+}
+
+var (
+	providedBody     string // value of body flag.
+	providedBodyFile string // value of file flag.
+)
+
+// initAppendCommand adds append and prepend commands to main application command.
+func initAppendCommand() {
+	initBodyFlags(appendCommand)
+	initBodyFlags(prependCommand)
+
+	appCommand.AddCommand(appendCommand)
+	appCommand.AddCommand(prependCommand)
+}
+
+// initBodyFlags adds body input related flags to given [cmd].
+func initBodyFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(
+		&providedBody, "body", "b", "",
+		"Text to write to note",
+	)
+	cmd.Flags().StringVar(
+		&providedBodyFile, "file", "",
+		"Path of file, which's content is written to note",
+	)
+}
+
+// readBodyInput takes the body to write from flags, or from piped stdin.
+// Returned body is nil, if it isn't provided at all.
+// [piped] reports whether stdin was consumed by the body.
+func readBodyInput() (body *string, piped bool, err error) {
+	switch {
+	case len(providedBody) > 0:
+		return &providedBody, false, nil
+	case len(providedBodyFile) > 0:
+		data, err := os.ReadFile(providedBodyFile)
+		if err != nil {
+			return nil, false, err
+		}
+
+		res := string(data)
+		return &res, false, nil
+	case pkg.IsPiped(stdargs.Stdin):
+		data, err := io.ReadAll(stdargs.Stdin)
+		if err != nil {
+			return nil, true, err
+		}
+
+		res := string(data)
+		return &res, true, nil
+	}
+
+	return nil, false, nil
+}
+
+// runWriteCommand writes the provided body to the note, via [write],
+// e.g: appends or prepends it to the note's current body.
+func runWriteCommand(args []string, act string, write func(body, text string) string) {
+	determineService()
+
+	text, piped, err := readBodyInput()
+	if err != nil {
+		alert(pkg.ErrorL, err.Error())
+		return
+	}
+
+	if text == nil {
+		alert(pkg.ErrorL, assets.NoBodyInput.Error())
+		return
+	}
+
+	var title string
+	if len(args) > 0 { // Take note title from arguments, if it's provided.
+		title = args[0]
+	} else if piped { // Stdin is taken by the body, so note cannot be asked.
+		alert(pkg.ErrorL, assets.TitleRequired.Error())
+		return
+	} else {
+		loading.Start()
+		_, noteNames, err := service.GetAll("", "file", models.NotyaIgnoreFiles)
+		loading.Stop()
+		if err != nil {
+			alert(pkg.ErrorL, err.Error())
+			return
+		}
+
+		ask(assets.ChooseNodePrompt("note", act, noteNames), &title)
+	}
+
+	if len(title) == 0 {
+		abort()
+		return
+	}
+
+	loading.Start()
+	defer loading.Stop()
+
+	note, err := service.View(models.Note{Title: title})
+	if err != nil {
+		alert(pkg.ErrorL, err.Error())
+		return
+	}
+
+	note.Body = write(note.Body, *text)
+
+	edited, err := service.Edit(*note)
+	if err != nil {
+		alert(pkg.ErrorL, err.Error())
+		return
+	}
+
+	addNodes(edited.ToNode())
+}
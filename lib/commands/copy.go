@@ -7,9 +7,6 @@
 package commands
 
 import (
-	"os"
-
-	"github.com/AlecAivazis/survey/v2"
 	"github.com/insolite-dev/notya/assets"
 	"github.com/insolite-dev/notya/lib/models"
 	"github.com/insolite-dev/notya/pkg"
@@ -43,13 +40,13 @@ func runCopyCommand(cmd *cobra.Command, args []string) {
 	_, nodeNames, err := service.GetAll("", "file", models.NotyaIgnoreFiles)
 	loading.Stop()
 	if err != nil {
-		pkg.Alert(pkg.ErrorL, err.Error())
+		alert(pkg.ErrorL, err.Error())
 		return
 	}
 
 	// Ask for node selection.
 	var selected string
-	survey.AskOne(
+	ask(
 		assets.ChooseNodePrompt("note", "copy", nodeNames),
 		&selected,
 	)
@@ -59,15 +56,17 @@ func runCopyCommand(cmd *cobra.Command, args []string) {
 
 func copyAndFinish(note models.Note) {
 	if len(note.Title) == 0 {
-		os.Exit(-1)
+		abort()
 		return
 	}
 
 	loading.Start()
 	if err := service.Copy(note); err != nil {
 		loading.Stop()
-		pkg.Alert(pkg.ErrorL, err.Error())
+		alert(pkg.ErrorL, err.Error())
 		return
 	}
 	loading.Stop()
+
+	addNodes(note.ToNode())
 }
@@ -7,9 +7,6 @@
 package commands
 
 import (
-	"os"
-
-	"github.com/AlecAivazis/survey/v2"
 	"github.com/insolite-dev/notya/assets"
 	"github.com/insolite-dev/notya/lib/models"
 	"github.com/insolite-dev/notya/pkg"
@@ -33,6 +30,8 @@ func initCreateCommand() {
 		&providedFolderName, "folder", "d", "",
 		"Make a directory via create command",
 	)
+	initBodyFlags(createCommand)
+	initTemplateFlags(createCommand)
 
 	appCommand.AddCommand(createCommand)
 }
@@ -47,6 +46,12 @@ func runCreateCommand(cmd *cobra.Command, args []string) {
 		return
 	}
 
+	body, piped, err := readBodyInput()
+	if err != nil {
+		alert(pkg.ErrorL, err.Error())
+		return
+	}
+
 	// Take new note's title from arguments, if it's provided.
 	if len(args) > 0 {
 		title := args[0]
@@ -59,41 +64,76 @@ func runCreateCommand(cmd *cobra.Command, args []string) {
 			return
 		}
 
-		createAndFinish(title)
+		createAndFinish(title, body)
+		return
+	}
+
+	// Stdin is taken by the body, so title cannot be asked.
+	if piped {
+		alert(pkg.ErrorL, assets.TitleRequired.Error())
 		return
 	}
 
 	// Ask for title of new note.
 	var title string
-	survey.Ask(assets.CreatePromptQuestion, &title)
+	askAll(assets.CreatePromptQuestion, &title)
 
-	createAndFinish(title)
+	createAndFinish(title, body)
 }
 
 // createAndFinish asks to edit note and finishes creating loop.
-func createAndFinish(title string) {
+// If [body] is provided, note is created with it, and editor isn't offered.
+// Otherwise, note is created from the provided template (if there is).
+func createAndFinish(title string, body *string) {
 	if len(title) == 0 {
-		os.Exit(-1)
+		abort()
 		return
 	}
 
+	note := models.Note{Title: title}
+	if body != nil {
+		note.Body = *body
+	}
+
+	if len(providedTemplate) > 0 {
+		if body != nil {
+			alert(pkg.ErrorL, assets.TemplateWithBody.Error())
+			return
+		}
+
+		rendered, err := renderTemplate(providedTemplate, title)
+		if err != nil {
+			alert(pkg.ErrorL, err.Error())
+			return
+		}
+
+		note.Body = rendered
+	}
+
 	loading.Start()
-	note, err := service.Create(models.Note{Title: title})
+	created, err := service.Create(note)
 	loading.Stop()
 
 	if err != nil {
-		pkg.Alert(pkg.ErrorL, err.Error())
+		alert(pkg.ErrorL, err.Error())
+		return
+	}
+
+	addNodes(created.ToNode())
+
+	// Editor isn't offered at non-interactive mode, or for a written body.
+	if !interactive() || body != nil {
 		return
 	}
 
 	// Ask for, open or not created note with editor.
 	var openNote bool
-	survey.AskOne(assets.OpenViaEditorPromt, &openNote)
+	ask(assets.OpenViaEditorPromt, &openNote)
 
 	if openNote {
 		// Open created note-file to edit it.
-		if err := service.Open(note.ToNode()); err != nil {
-			pkg.Alert(pkg.ErrorL, err.Error())
+		if err := service.Open(created.ToNode()); err != nil {
+			alert(pkg.ErrorL, err.Error())
 			return
 		}
 	}
@@ -7,11 +7,9 @@
 package commands
 
 import (
-	"os"
-
-	"github.com/AlecAivazis/survey/v2"
 	"github.com/insolite-dev/notya/assets"
 	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/lib/services"
 	"github.com/insolite-dev/notya/pkg"
 	"github.com/spf13/cobra"
 )
@@ -22,7 +20,14 @@ var cutCommand = &cobra.Command{
 	Run:   runCutCommand,
 }
 
+var cutPermanent bool
+
 func initCutCommand() {
+	cutCommand.Flags().BoolVarP(
+		&cutPermanent, "permanent", "p", false,
+		"Cut permanently, without moving to trash",
+	)
+
 	appCommand.AddCommand(cutCommand)
 }
 
@@ -40,13 +45,13 @@ func runCutCommand(cmd *cobra.Command, args []string) {
 	_, nodeNames, err := service.GetAll("", "file", models.NotyaIgnoreFiles)
 	loading.Stop()
 	if err != nil {
-		pkg.Alert(pkg.ErrorL, err.Error())
+		alert(pkg.ErrorL, err.Error())
 		return
 	}
 
 	// Ask for node selection.
 	var selected string
-	survey.AskOne(
+	ask(
 		assets.ChooseNodePrompt("note", "cut", nodeNames),
 		&selected,
 	)
@@ -56,15 +61,33 @@ func runCutCommand(cmd *cobra.Command, args []string) {
 
 func cutAndFinish(note models.Note) {
 	if len(note.Title) == 0 {
-		os.Exit(-1)
+		abort()
 		return
 	}
 
 	loading.Start()
-	if _, err := service.Cut(note); err != nil {
+	cut := service.Cut
+	if !cutPermanent {
+		cut = func(n models.Note) (*models.Note, error) {
+			note, err := services.CutToTrash(service, n)
+			if err != assets.TrashNotAvailable {
+				return note, err
+			}
+
+			loading.Stop()
+			warnPermanent()
+
+			loading.Start()
+			return service.Cut(n)
+		}
+	}
+
+	if _, err := cut(note); err != nil {
 		loading.Stop()
-		pkg.Alert(pkg.ErrorL, err.Error())
+		alert(pkg.ErrorL, err.Error())
 		return
 	}
 	loading.Stop()
+
+	addNodes(note.ToNode())
 }
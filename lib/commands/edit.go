@@ -7,9 +7,6 @@
 package commands
 
 import (
-	"os"
-
-	"github.com/AlecAivazis/survey/v2"
 	"github.com/insolite-dev/notya/assets"
 	"github.com/insolite-dev/notya/lib/models"
 	"github.com/insolite-dev/notya/pkg"
@@ -32,6 +29,7 @@ func initEditCommand() {
 // runEditCommand runs appropriate service commands to edit/overwrite note data.
 func runEditCommand(cmd *cobra.Command, args []string) {
 	determineService()
+	requireEditor()
 
 	// Take note title from arguments. If it's provided.
 	if len(args) > 0 {
@@ -44,13 +42,13 @@ func runEditCommand(cmd *cobra.Command, args []string) {
 	_, nodeNames, err := service.GetAll("", "file", models.NotyaIgnoreFiles)
 	loading.Stop()
 	if err != nil {
-		pkg.Alert(pkg.ErrorL, err.Error())
+		alert(pkg.ErrorL, err.Error())
 		return
 	}
 
 	// Ask for note selection.
 	var selected string
-	survey.AskOne(
+	ask(
 		assets.ChooseNodePrompt("note", "edit", nodeNames),
 		&selected,
 	)
@@ -61,11 +59,11 @@ func runEditCommand(cmd *cobra.Command, args []string) {
 
 func editAndFinish(note models.Node) {
 	if len(note.Title) == 0 {
-		os.Exit(-1)
+		abort()
 		return
 	}
 
 	if err := service.Open(note); err != nil {
-		pkg.Alert(pkg.ErrorL, err.Error())
+		alert(pkg.ErrorL, err.Error())
 	}
 }
@@ -0,0 +1,195 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/insolite-dev/notya/assets"
+	"github.com/insolite-dev/notya/lib/services"
+	"github.com/insolite-dev/notya/pkg"
+	"github.com/spf13/cobra"
+)
+
+// Environment variables, that provide passphrases without prompting.
+const (
+	PassphraseEnv      = "NOTYA_PASSPHRASE"       // passphrase of encryption.
+	VaultPassphraseEnv = "NOTYA_VAULT_PASSPHRASE" // passphrase of vault folder.
+)
+
+// encryptCommand is a command model that used to enable encryption, and encrypt existing notes.
+var encryptCommand = &cobra.Command{
+	Use:   "encrypt",
+	Short: "Enable client-side encryption of notes, and encrypt existing notes",
+	Run:   runEncryptCommand,
+}
+
+var (
+	encryptAll  bool
+	encryptMode string
+)
+
+// encryptionCipher is the unlocked cipher of encryption, cached for current execution.
+var encryptionCipher *pkg.Cipher
+
+// initEncryptCommand adds [encryptCommand] to the [appCommand].
+func initEncryptCommand() {
+	encryptCommand.Flags().BoolVarP(
+		&encryptAll, "all", "a", false,
+		"Encrypt all existing notes of current service (including their history and trash)",
+	)
+	encryptCommand.Flags().StringVar(
+		&encryptMode, "mode", "",
+		"Services that keep encrypted notes: all or remote (default: all)",
+	)
+
+	appCommand.AddCommand(encryptCommand)
+}
+
+// runEncryptCommand enables encryption at settings (if it isn't enabled yet, or mode is changed),
+// and encrypts existing notes of current service, if [encryptAll] is enabled.
+func runEncryptCommand(cmd *cobra.Command, args []string) {
+	settings := localService.StateConfig()
+
+	if len(settings.Encryption) == 0 || (len(encryptMode) > 0 && encryptMode != settings.Encryption) {
+		mode := encryptMode
+		if len(mode) == 0 {
+			mode = "all"
+		}
+
+		passphrase, err := askPassphrase(PassphraseEnv, len(settings.EncryptionSalt) == 0)
+		if err != nil {
+			alert(pkg.ErrorL, err.Error())
+			return
+		}
+
+		updated, c, err := services.EnableEncryption(settings, mode, passphrase)
+		if err != nil {
+			alert(pkg.ErrorL, err.Error())
+			return
+		}
+
+		if err := localService.WriteSettings(updated); err != nil {
+			alert(pkg.ErrorL, err.Error())
+			return
+		}
+
+		// Reload state of local service, from updated settings.
+		if err := localService.Init(nil); err != nil {
+			alert(pkg.ErrorL, err.Error())
+			return
+		}
+
+		encryptionCipher = c
+		alert(pkg.SuccessL, fmt.Sprintf("Enabled encryption of %v services", mode))
+	}
+
+	if !encryptAll {
+		return
+	}
+
+	determineService()
+
+	// Encryption is wrapped by vaults, if there's any vault folder.
+	target := service
+	if vaulted, ok := target.(*services.VaultService); ok {
+		target = vaulted.ServiceRepo
+	}
+
+	encrypted, ok := target.(*services.EncryptedService)
+	if !ok {
+		alert(pkg.InfoL, fmt.Sprintf("Notes of %v aren't encrypted, with encryption of %v services", service.Type(), localService.StateConfig().Encryption))
+		return
+	}
+
+	loading.Start()
+	encryptedNodes, errs := encrypted.EncryptAll()
+	loading.Stop()
+
+	addNodes(encryptedNodes...)
+	printErrors("encrypt", errs)
+	alert(pkg.SuccessL, fmt.Sprintf("Encrypted %v notes", len(encryptedNodes)))
+}
+
+// secured wraps [s] by encryption and vault folders, if they're enabled for it.
+// [remote] decides whether [s] is a remote service or not.
+func secured(s services.ServiceRepo, remote bool) services.ServiceRepo {
+	return withVaults(withEncryption(s, remote))
+}
+
+// withEncryption wraps [s] by encrypted service, if encryption is enabled for it.
+// [remote] decides whether [s] is a remote service or not.
+func withEncryption(s services.ServiceRepo, remote bool) services.ServiceRepo {
+	if localService == nil {
+		return s
+	}
+
+	if settings := localService.StateConfig(); !settings.Encrypts(remote) {
+		return s
+	}
+
+	return services.NewEncryptedService(s, stdargs, unlockEncryption)
+}
+
+// unlockEncryption unlocks the cipher of encryption once, by asking for passphrase.
+func unlockEncryption() (*pkg.Cipher, error) {
+	if encryptionCipher != nil {
+		return encryptionCipher, nil
+	}
+
+	passphrase, err := askPassphrase(PassphraseEnv, false)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := services.UnlockEncryption(localService.StateConfig(), passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	encryptionCipher = c
+	return c, nil
+}
+
+// askPassphrase takes passphrase from [env] environment variable, or asks for it.
+// If [confirm] is enabled, passphrase is asked twice (used on setting up a new passphrase).
+func askPassphrase(env string, confirm bool) (string, error) {
+	if passphrase := os.Getenv(env); len(passphrase) > 0 {
+		return passphrase, nil
+	}
+
+	if !interactive() {
+		return "", assets.InputRequired
+	}
+
+	// Prompt can't be shown together with loading spinner.
+	loading.Stop()
+
+	var passphrase string
+	if err := survey.AskOne(assets.PassphrasePrompt("Passphrase:", env), &passphrase); err != nil {
+		return "", err
+	}
+
+	if len(passphrase) == 0 {
+		return "", assets.EmptyPassphrase
+	}
+
+	if confirm {
+		var again string
+		if err := survey.AskOne(assets.PassphrasePrompt("Confirm passphrase:", env), &again); err != nil {
+			return "", err
+		}
+
+		if again != passphrase {
+			return "", assets.PassphraseMismatch
+		}
+	}
+
+	return passphrase, nil
+}
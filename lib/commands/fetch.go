@@ -8,66 +8,81 @@ package commands
 
 import (
 	"fmt"
-	"os"
 
-	"github.com/AlecAivazis/survey/v2"
 	"github.com/fatih/color"
-	"github.com/insolite-dev/notya/assets"
+	"github.com/insolite-dev/notya/lib/models"
 	"github.com/insolite-dev/notya/lib/services"
 	"github.com/insolite-dev/notya/pkg"
 	"github.com/spf13/cobra"
 )
 
 var fetchCommand = &cobra.Command{
-	Use:     "fetch",
+	Use:     "fetch [service]",
+	Args:    cobra.MaximumNArgs(1),
 	Aliases: []string{"pull"},
 	Short:   "Fetch creates a clone of each node from [Y] service to [X] service",
 	Run:     runFetchCommand,
 }
 
+// pruneNodes is the value of prune flag, of fetch and push commands.
+var pruneNodes bool
+
 func initFetchCommand() {
+	fetchCommand.Flags().BoolVarP(
+		&pruneNodes, "prune", "p", false,
+		"Remove nodes that were removed from [Y] service",
+	)
+
+	initPlanFlags(fetchCommand)
+
 	appCommand.AddCommand(fetchCommand)
 }
 
 func runFetchCommand(cmd *cobra.Command, args []string) {
 	determineService()
-	loading.Start()
 
-	availableServices := []string{}
-	// Generate a list of availabe services
-	// by not including current service.
-	for _, s := range services.Services {
-		if service.Type() == s {
-			continue
-		}
-
-		availableServices = append(availableServices, s)
+	selected, err := selectService(args)
+	if err != nil {
+		alert(pkg.ErrorL, err.Error())
+		return
 	}
 
-	loading.Stop()
-
-	// Ask for servie selection.
-	var selected string
-	survey.AskOne(
-		assets.ChooseRemotePrompt(availableServices),
-		&selected,
-	)
 	if len(selected) == 0 {
-		os.Exit(-1)
+		abort()
 		return
 	}
 
 	selectedService := serviceFromType(selected, true)
 
+	if dryRun {
+		loading.Start()
+		plan, err := services.PlanTransfer("fetch", selectedService, service, pruneNodes)
+		loading.Stop()
+
+		printPlan(plan, err)
+		return
+	}
+
 	loading.Start()
+	prunedNodes, pruneErrs := []models.Node{}, []error{}
+	if pruneNodes {
+		prunedNodes, pruneErrs = services.Prune(selectedService, service)
+	}
+
 	fetchedNodes, errs := service.Fetch(selectedService)
+	errs = append(pruneErrs, errs...)
 	loading.Stop()
 
-	if len(fetchedNodes) == 0 && len(errs) == 0 {
-		pkg.Print("Already up to date", color.FgHiGreen)
+	if len(fetchedNodes) == 0 && len(prunedNodes) == 0 && len(errs) == 0 {
+		printMessage("Already up to date", color.FgHiGreen)
 		return
 	}
 
-	pkg.PrintErrors("fetch", errs)
-	pkg.Alert(pkg.SuccessL, fmt.Sprintf("Fetched %v nodes", len(fetchedNodes)))
+	addNodes(fetchedNodes...)
+	printErrors("fetch", errs)
+	alert(pkg.SuccessL, fmt.Sprintf("Fetched %v nodes", len(fetchedNodes)))
+
+	if pruneNodes {
+		alert(pkg.SuccessL, fmt.Sprintf("Pruned %v nodes", len(prunedNodes)))
+	}
 }
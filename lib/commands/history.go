@@ -0,0 +1,195 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/insolite-dev/notya/assets"
+	"github.com/insolite-dev/notya/lib/services"
+	"github.com/insolite-dev/notya/pkg"
+	"github.com/spf13/cobra"
+)
+
+// historyCommand is a command model that used to list earlier versions of a note.
+var historyCommand = &cobra.Command{
+	Use:   "history [note]",
+	Short: "List earlier versions of a note, with their timestamps and sizes",
+	Args:  cobra.MaximumNArgs(1),
+	Run:   runHistoryCommand,
+}
+
+// diffCommand is a command model that used to compare a note with its earlier version,
+// or nodes of current service with nodes of another service (via --against).
+var diffCommand = &cobra.Command{
+	Use:   "diff [note] [rev]",
+	Short: "Show a unified diff of a note, from its version (the latest by default) to its current body",
+	Long: "Show a unified diff of a note, from its version (the latest by default) to its current body.\n" +
+		"With --against, compares nodes of current service with nodes of given service: notya diff --against firebase [path]",
+	Args: cobra.MaximumNArgs(2),
+	Run:  runDiffCommand,
+}
+
+var diffAgainst string
+
+// restoreCommand is a command model that used to restore a note to its earlier version.
+var restoreCommand = &cobra.Command{
+	Use:   "restore <note> <rev>",
+	Short: "Restore a note to its earlier version",
+	Args:  cobra.ExactArgs(2),
+	Run:   runRestoreCommand,
+}
+
+// initHistoryCommand adds [historyCommand], [diffCommand] and [restoreCommand] to the [appCommand].
+func initHistoryCommand() {
+	diffCommand.Flags().StringVar(
+		&diffAgainst, "against", "",
+		"Compare nodes of current service with nodes of given service, like: firebase",
+	)
+
+	appCommand.AddCommand(historyCommand, diffCommand, restoreCommand)
+}
+
+// runHistoryCommand logs versions of the note, from the latest to the earliest.
+func runHistoryCommand(cmd *cobra.Command, args []string) {
+	determineService()
+
+	title := selectNote(args, "list history of")
+	if len(title) == 0 {
+		abort()
+		return
+	}
+
+	loading.Start()
+	history, err := services.History(service, title)
+	loading.Stop()
+
+	if err != nil {
+		alert(pkg.ErrorL, err.Error())
+		return
+	}
+
+	if len(history) == 0 {
+		alert(pkg.InfoL, fmt.Sprintf("%v has no earlier versions", title))
+		return
+	}
+
+	printData(history, len(history), func() { pkg.PrintHistory(history) })
+}
+
+// runDiffCommand logs the unified diff of the note, from its version to its current body.
+func runDiffCommand(cmd *cobra.Command, args []string) {
+	determineService()
+
+	if len(diffAgainst) > 0 {
+		compareAndFinish(args)
+		return
+	}
+
+	title := selectNote(args, "diff")
+	if len(title) == 0 {
+		abort()
+		return
+	}
+
+	rev := 0
+	if len(args) > 1 {
+		r, err := services.ParseRev(args[1])
+		if err != nil {
+			alert(pkg.ErrorL, err.Error())
+			return
+		}
+
+		rev = r
+	}
+
+	loading.Start()
+	diff, err := services.DiffVersion(service, title, rev)
+	loading.Stop()
+
+	if err != nil {
+		alert(pkg.ErrorL, err.Error())
+		return
+	}
+
+	if len(diff) == 0 {
+		alert(pkg.InfoL, "No difference")
+		return
+	}
+
+	printData(diff, 1, func() { pkg.PrintDiff(diff) })
+}
+
+// runRestoreCommand overwrites the note with its version of provided revision.
+func runRestoreCommand(cmd *cobra.Command, args []string) {
+	determineService()
+
+	rev, err := services.ParseRev(args[1])
+	if err != nil {
+		alert(pkg.ErrorL, err.Error())
+		return
+	}
+
+	loading.Start()
+	version, err := services.RestoreVersion(service, args[0], rev)
+	loading.Stop()
+
+	if err != nil {
+		alert(pkg.ErrorL, err.Error())
+		return
+	}
+
+	alert(pkg.SuccessL, fmt.Sprintf("Restored %v to r%v", args[0], version.Rev))
+}
+
+// compareAndFinish logs differences of current service from the [diffAgainst] service,
+// scoped to the path of arguments (if provided).
+func compareAndFinish(args []string) {
+	// Generate a list of available services, by not including current service
+	// and its aliases, that work on the same notes (like git of local service).
+	available := []string{}
+	for _, s := range append(services.Services, services.GIT.ToStr()) {
+		if !services.SameNotes(service.Type(), s) {
+			available = append(available, s)
+		}
+	}
+
+	against := ""
+	for _, s := range available {
+		if strings.EqualFold(s, diffAgainst) {
+			against = s
+		}
+	}
+
+	if len(against) == 0 {
+		alert(pkg.ErrorL, assets.UnknownService(diffAgainst, available).Error())
+		return
+	}
+
+	path := ""
+	if len(args) > 0 {
+		path = args[0]
+	}
+
+	loading.Start()
+	comparison, err := services.Compare(service, serviceFromType(against, true), path)
+	loading.Stop()
+
+	if err != nil {
+		alert(pkg.ErrorL, err.Error())
+		return
+	}
+
+	if len(comparison.Nodes) == 0 {
+		printMessage("Everything up-to-date", color.FgHiGreen)
+		return
+	}
+
+	printData(comparison, len(comparison.Nodes), func() { pkg.PrintComparison(comparison) })
+}
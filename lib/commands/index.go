@@ -0,0 +1,81 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package commands
+
+import (
+	"fmt"
+
+	"github.com/insolite-dev/notya/assets"
+	"github.com/insolite-dev/notya/lib/services"
+	"github.com/insolite-dev/notya/pkg"
+	"github.com/spf13/cobra"
+)
+
+// indexCommand is a command model that used to manage the search index.
+var indexCommand = &cobra.Command{
+	Use:   "index",
+	Short: "Manage the search index of local notes",
+	Run:   runIndexCommand,
+}
+
+// rebuildIndexCommand is a command model that used to re-index all notes from scratch.
+var rebuildIndexCommand = &cobra.Command{
+	Use:   "rebuild",
+	Short: "Re-index all local notes from scratch",
+	Run:   runIndexRebuildCommand,
+}
+
+// initIndexCommand adds [indexCommand] to the [appCommand].
+func initIndexCommand() {
+	indexCommand.AddCommand(rebuildIndexCommand)
+
+	appCommand.AddCommand(indexCommand)
+}
+
+// runIndexCommand refreshes the search index, and logs the amount of indexed notes.
+func runIndexCommand(cmd *cobra.Command, args []string) {
+	determineService()
+
+	indexer, ok := service.(services.Indexer)
+	if !ok {
+		alert(pkg.ErrorL, assets.IndexNotAvailable.Error())
+		return
+	}
+
+	loading.Start()
+	index, err := indexer.RefreshIndex()
+	loading.Stop()
+
+	if err != nil {
+		alert(pkg.ErrorL, err.Error())
+		return
+	}
+
+	alert(pkg.InfoL, fmt.Sprintf("Index is up to date, with %v notes", len(index.Docs)))
+}
+
+// runIndexRebuildCommand re-indexes all notes of current service from scratch.
+func runIndexRebuildCommand(cmd *cobra.Command, args []string) {
+	determineService()
+
+	indexer, ok := service.(services.Indexer)
+	if !ok {
+		alert(pkg.ErrorL, assets.IndexNotAvailable.Error())
+		return
+	}
+
+	loading.Start()
+	index, err := indexer.RebuildIndex()
+	loading.Stop()
+
+	if err != nil {
+		alert(pkg.ErrorL, err.Error())
+		return
+	}
+
+	alert(pkg.SuccessL, fmt.Sprintf("Rebuilt index of %v notes", len(index.Docs)))
+}
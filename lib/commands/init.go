@@ -34,10 +34,10 @@ func runInitCommand(cmd *cobra.Command, args []string) {
 	loading.Stop()
 
 	if err != nil {
-		pkg.Alert(pkg.ErrorL, err.Error())
+		alert(pkg.ErrorL, err.Error())
 		return
 	}
 
-	pkg.Alert(pkg.SuccessL, `Application initialized successfully`)
-	pkg.Print(" > [notya -h/help] for help", color.FgBlue)
+	alert(pkg.SuccessL, `Application initialized successfully`)
+	printMessage(" > [notya -h/help] for help", color.FgBlue)
 }
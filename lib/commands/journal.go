@@ -0,0 +1,151 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/lib/services"
+	"github.com/insolite-dev/notya/pkg"
+	"github.com/spf13/cobra"
+)
+
+// todayCommand is a command model that used to open (or create) the journal note of today.
+var todayCommand = &cobra.Command{
+	Use:   "today",
+	Short: "Open or create the journal note of today",
+	Run: func(cmd *cobra.Command, args []string) {
+		runJournalCommand(cmd, nil)
+	},
+}
+
+// journalCommand is a command model that used to open (or create) the journal note of a date.
+var journalCommand = &cobra.Command{
+	Use:   "journal",
+	Short: "Open or create the journal note of date (YYYY-MM-DD), today by default",
+	Run:   runJournalCommand,
+}
+
+var (
+	journalYesterday bool // value of yesterday flag.
+	journalWeek      bool // value of week flag.
+)
+
+// initJournalCommand adds [todayCommand] and [journalCommand] to the [appCommand].
+func initJournalCommand() {
+	for _, cmd := range []*cobra.Command{todayCommand, journalCommand} {
+		cmd.Flags().BoolVar(
+			&journalYesterday, "yesterday", false,
+			"Use the day before of date",
+		)
+		cmd.Flags().BoolVar(
+			&journalWeek, "week", false,
+			"Show journal notes of the whole week of date, concatenated",
+		)
+		initTemplateFlags(cmd)
+
+		appCommand.AddCommand(cmd)
+	}
+}
+
+// runJournalCommand opens the journal note of provided date, creating it (with its folders) if it doesn't exist.
+func runJournalCommand(cmd *cobra.Command, args []string) {
+	determineService()
+
+	var provided string
+	if len(args) > 0 {
+		provided = args[0]
+	}
+
+	date, err := models.ParseJournalDate(provided, time.Now())
+	if err != nil {
+		alert(pkg.ErrorL, err.Error())
+		return
+	}
+
+	if journalYesterday {
+		date = date.AddDate(0, 0, -1)
+	}
+
+	if journalWeek {
+		printJournalWeek(date)
+		return
+	}
+
+	settings := service.StateConfig()
+	title := settings.JournalTitle(date)
+
+	loading.Start()
+	exists, err := service.IsNodeExists(models.Node{Title: title})
+	loading.Stop()
+
+	if err != nil {
+		alert(pkg.ErrorL, err.Error())
+		return
+	}
+
+	note := &models.Note{Title: title}
+	if !exists {
+		template := providedTemplate
+		if len(template) == 0 {
+			template = settings.JournalTemplate
+		}
+
+		var body string
+		if len(template) > 0 {
+			if body, err = renderTemplate(template, title); err != nil {
+				alert(pkg.ErrorL, err.Error())
+				return
+			}
+		}
+
+		loading.Start()
+		note, err = services.CreateJournal(service, title, body)
+		loading.Stop()
+
+		if err != nil {
+			alert(pkg.ErrorL, err.Error())
+			return
+		}
+	}
+
+	addNodes(note.ToNode())
+
+	// Editor isn't opened at non-interactive mode.
+	if !interactive() {
+		return
+	}
+
+	if err := service.Open(note.ToNode()); err != nil {
+		alert(pkg.ErrorL, err.Error())
+	}
+}
+
+// printJournalWeek logs journal notes of the week of [date], concatenated as one note.
+func printJournalWeek(date time.Time) {
+	loading.Start()
+	notes, err := services.JournalWeek(service, date)
+	loading.Stop()
+
+	if err != nil {
+		alert(pkg.ErrorL, err.Error())
+		return
+	}
+
+	week := models.JournalWeek(date)
+	if len(notes) == 0 {
+		alert(pkg.InfoL, fmt.Sprintf("There are no journal notes at the week of %v", week[0].Format(models.JournalDateLayout)))
+		return
+	}
+
+	printNote(models.Note{
+		Title: fmt.Sprintf("Week of %v", week[0].Format(models.JournalDateLayout)),
+		Body:  models.ConcatJournal(notes),
+	})
+}
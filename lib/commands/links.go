@@ -0,0 +1,131 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package commands
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/insolite-dev/notya/assets"
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/lib/services"
+	"github.com/insolite-dev/notya/pkg"
+	"github.com/spf13/cobra"
+)
+
+// linksCommand is a command model that used to list outgoing links of a note.
+var linksCommand = &cobra.Command{
+	Use:   "links",
+	Short: "List [[links]] of a note, or broken links of all notes",
+	Run:   runLinksCommand,
+}
+
+// backlinksCommand is a command model that used to list incoming links of a note.
+var backlinksCommand = &cobra.Command{
+	Use:   "backlinks",
+	Short: "List notes, that link to a note",
+	Run:   runBacklinksCommand,
+}
+
+// brokenLinks is the value of broken flag.
+var brokenLinks bool
+
+// initLinksCommand adds [linksCommand] and [backlinksCommand] to the [appCommand].
+func initLinksCommand() {
+	linksCommand.Flags().BoolVarP(
+		&brokenLinks, "broken", "b", false,
+		"Report links, which's target doesn't exist",
+	)
+
+	appCommand.AddCommand(linksCommand)
+	appCommand.AddCommand(backlinksCommand)
+}
+
+// runLinksCommand logs outgoing links of selected note, or broken links of all notes.
+func runLinksCommand(cmd *cobra.Command, args []string) {
+	determineService()
+
+	if brokenLinks {
+		loading.Start()
+		links, err := services.BrokenLinks(service)
+		loading.Stop()
+
+		if err != nil {
+			alert(pkg.ErrorL, err.Error())
+			return
+		}
+
+		if len(links) == 0 {
+			printMessage("No broken links", color.FgHiGreen)
+			return
+		}
+
+		printData(links, len(links), func() { pkg.PrintLinks(links, false) })
+		alert(pkg.ErrorL, fmt.Sprintf("Found %v broken links", len(links)))
+		return
+	}
+
+	title := selectNote(args, "list links of")
+
+	loading.Start()
+	links, err := services.Links(service, title)
+	loading.Stop()
+
+	printLinks(links, err, false)
+}
+
+// runBacklinksCommand logs incoming links of selected note.
+func runBacklinksCommand(cmd *cobra.Command, args []string) {
+	determineService()
+
+	title := selectNote(args, "list backlinks of")
+
+	loading.Start()
+	links, err := services.Backlinks(service, title)
+	loading.Stop()
+
+	printLinks(links, err, true)
+}
+
+// selectNote takes note title from arguments, or asks for note selection if it's not provided.
+func selectNote(args []string, act string) string {
+	if len(args) > 0 {
+		return args[0]
+	}
+
+	loading.Start()
+	_, noteNames, err := service.GetAll("", "file", models.NotyaIgnoreFiles)
+	loading.Stop()
+
+	if err != nil {
+		alert(pkg.ErrorL, err.Error())
+		return ""
+	}
+
+	var selected string
+	ask(
+		assets.ChooseNodePrompt("note", act, noteNames),
+		&selected,
+	)
+
+	return selected
+}
+
+// printLinks logs result of links or backlinks.
+func printLinks(links []models.Link, err error, incoming bool) {
+	if err != nil {
+		alert(pkg.ErrorL, err.Error())
+		return
+	}
+
+	if len(links) == 0 {
+		printMessage("No links found", color.FgHiYellow)
+		return
+	}
+
+	printData(links, len(links), func() { pkg.PrintLinks(links, incoming) })
+}
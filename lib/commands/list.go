@@ -7,7 +7,10 @@
 package commands
 
 import (
+	"time"
+
 	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/lib/services"
 	"github.com/insolite-dev/notya/pkg"
 	"github.com/spf13/cobra"
 )
@@ -20,8 +23,28 @@ var listCommand = &cobra.Command{
 	Run:     runListCommand,
 }
 
+// Values of list flags.
+var (
+	sortBy string // field to sort nodes by.
+	since  string // age of oldest modification to list.
+	tag    string // tag of front matter, that listed notes should include.
+)
+
 // initListCommand adds listCommand to main application command.
 func initListCommand() {
+	listCommand.Flags().StringVarP(
+		&sortBy, "sort", "s", "",
+		"Sort nodes by title, created, modified or size",
+	)
+	listCommand.Flags().StringVar(
+		&since, "since", "",
+		"List only nodes modified within provided age, like: 12h, 7d, 2w",
+	)
+	listCommand.Flags().StringVarP(
+		&tag, "tag", "t", "",
+		"List only notes tagged with provided tag",
+	)
+
 	appCommand.AddCommand(listCommand)
 }
 
@@ -37,13 +60,44 @@ func runListCommand(cmd *cobra.Command, args []string) {
 	loading.Start()
 
 	// Generate a list of nodes.
-	nodes, _, err := service.GetAll(additional, "", models.NotyaIgnoreFiles)
+	var nodes []models.Node
+	var err error
+	if len(tag) > 0 {
+		nodes, err = services.NodesByTag(service, tag, additional)
+	} else {
+		nodes, _, err = service.GetAll(additional, "", models.NotyaIgnoreFiles)
+	}
 
 	loading.Stop()
 	if err != nil {
-		pkg.Alert(pkg.ErrorL, err.Error())
+		alert(pkg.ErrorL, err.Error())
 		return
 	}
 
-	pkg.PrintNodes(nodes)
+	if len(since) > 0 {
+		age, err := pkg.ParseAge(since)
+		if err != nil {
+			alert(pkg.ErrorL, err.Error())
+			return
+		}
+
+		nodes = pkg.NodesSince(nodes, time.Now().Add(-age))
+	}
+
+	if len(sortBy) > 0 {
+		if err := pkg.SortNodes(nodes, sortBy); err != nil {
+			alert(pkg.ErrorL, err.Error())
+			return
+		}
+	}
+
+	// Filtered or sorted nodes aren't a tree anymore,
+	// so print them with full titles, without indentation.
+	if len(since) > 0 || len(sortBy) > 0 || len(tag) > 0 {
+		for i := range nodes {
+			nodes[i].Pretty = []string{nodes[i].GenPretty(), nodes[i].Title}
+		}
+	}
+
+	printNodes(nodes)
 }
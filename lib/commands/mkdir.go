@@ -7,9 +7,6 @@
 package commands
 
 import (
-	"os"
-
-	"github.com/AlecAivazis/survey/v2"
 	"github.com/insolite-dev/notya/assets"
 	"github.com/insolite-dev/notya/lib/models"
 	"github.com/insolite-dev/notya/pkg"
@@ -38,22 +35,24 @@ func runMkdirCommand(cmd *cobra.Command, args []string) {
 	if len(args) > 0 { // Take folder's title from arguments, if it's provided.
 		title = args[0]
 	} else { // Ask for the title of folder.
-		survey.Ask(assets.MkdirPromptQuestion, &title)
+		askAll(assets.MkdirPromptQuestion, &title)
 	}
 
 	loading.Start()
 
 	if len(title) == 0 {
-		os.Exit(-1)
+		abort()
 		return
 	}
 
 	// Create new directory by given title.
-	_, err := service.Mkdir(models.Folder{Title: title})
+	folder, err := service.Mkdir(models.Folder{Title: title})
 
 	loading.Stop()
 	if err != nil {
-		pkg.Alert(pkg.ErrorL, err.Error())
+		alert(pkg.ErrorL, err.Error())
 		return
 	}
+
+	addNodes(folder.ToNode())
 }
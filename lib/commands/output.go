@@ -0,0 +1,194 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/fatih/color"
+	"github.com/insolite-dev/notya/assets"
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/pkg"
+)
+
+// Decides whether print one structured JSON result of command, instead of colored logs.
+// JSON mode is always non-interactive.
+var jsonF bool
+
+// Decides whether fail instead of prompting, or not.
+var noInputF bool
+
+// result is the structured result of current command, printed at JSON mode.
+var result = models.Result{}
+
+// interactive checks if prompts (and spin animation) are enabled.
+func interactive() bool {
+	return !jsonF && !noInputF
+}
+
+// setupOutput configures logs of application by the output flags,
+// once flags are parsed.
+func setupOutput(command string) {
+	result.Command = command
+	loading.Disabled = !interactive()
+
+	// Stdout is kept only for the JSON result.
+	if jsonF {
+		pkg.LogToStderr()
+	}
+}
+
+// ask asks for the [prompt], and writes the answer to [response].
+// At non-interactive mode, it fails the command instead of prompting.
+func ask(prompt survey.Prompt, response interface{}) {
+	if !interactive() {
+		alert(pkg.ErrorL, assets.InputRequired.Error())
+		finish(nil)
+	}
+
+	survey.AskOne(prompt, response)
+}
+
+// askAll asks for all [questions], and writes the answers to [response].
+// At non-interactive mode, it fails the command instead of prompting.
+func askAll(questions []*survey.Question, response interface{}) {
+	if !interactive() {
+		alert(pkg.ErrorL, assets.InputRequired.Error())
+		finish(nil)
+	}
+
+	survey.Ask(questions, response)
+}
+
+// requireEditor fails the command at non-interactive mode, since editor can't be opened.
+func requireEditor() {
+	if !interactive() {
+		alert(pkg.ErrorL, assets.EditorRequired.Error())
+		finish(nil)
+	}
+}
+
+// abort finishes the command on empty input.
+// Interactive mode exits silently, since it's a cancelled prompt.
+func abort() {
+	if !interactive() {
+		alert(pkg.ErrorL, assets.EmptyInput.Error())
+		finish(nil)
+	}
+
+	os.Exit(-1)
+}
+
+// alert logs message at given level, or keeps it at [result] at JSON mode.
+func alert(l pkg.Level, msg string) {
+	if l == pkg.ErrorL {
+		result.Errors = append(result.Errors, msg)
+	} else {
+		result.Messages = append(result.Messages, msg)
+	}
+
+	if !jsonF {
+		pkg.Alert(l, msg)
+	}
+}
+
+// printMessage logs colored message, or keeps it at [result] at JSON mode.
+func printMessage(msg string, c color.Attribute) {
+	if jsonF {
+		result.Messages = append(result.Messages, strings.TrimSpace(msg))
+		return
+	}
+
+	pkg.Print(msg, c)
+}
+
+// printErrors logs errors of [act], or keeps them at [result] at JSON mode.
+func printErrors(act string, errs []error) {
+	for _, e := range errs {
+		result.Errors = append(result.Errors, fmt.Sprintf("%v: %v", act, e.Error()))
+	}
+
+	if !jsonF {
+		pkg.PrintErrors(act, errs)
+	}
+}
+
+// printNodes logs listed nodes, or keeps them at [result] at JSON mode.
+func printNodes(nodes []models.Node) {
+	if !jsonF {
+		pkg.PrintNodes(nodes)
+		return
+	}
+
+	addNodes(nodes...)
+}
+
+// printNote logs the note, or keeps it at [result] at JSON mode.
+func printNote(note models.Note) {
+	if !jsonF {
+		pkg.PrintNote(note)
+		return
+	}
+
+	addNodes(note.ToNode())
+}
+
+// printData logs [data] via [print], or keeps it at [result] at JSON mode.
+// [count] is the amount of items of [data].
+func printData(data interface{}, count int, print func()) {
+	if !jsonF {
+		print()
+		return
+	}
+
+	result.Data, result.Count = data, count
+}
+
+// addNodes keeps affected nodes at [result].
+// Pretty fields are dropped, since they're only used by colored logs.
+func addNodes(nodes ...models.Node) {
+	for _, n := range nodes {
+		n.Pretty = nil
+		result.Nodes = append(result.Nodes, n)
+	}
+
+	result.Count = len(result.Nodes)
+}
+
+// flushIndex writes the search index batch of command, if it wasn't written yet.
+// Index is just a cache of notes, so its errors are skipped.
+func flushIndex() {
+	if flushIndexes == nil {
+		return
+	}
+
+	_ = flushIndexes()
+	flushIndexes = nil
+}
+
+// finish prints [result] at JSON mode, and exits with a non-zero code,
+// if command has failed at non-interactive mode.
+func finish(err error) {
+	flushIndex()
+
+	if err != nil && !interactive() {
+		result.Errors = append(result.Errors, err.Error())
+	}
+
+	result.OK = len(result.Errors) == 0
+
+	if jsonF {
+		fmt.Fprintln(stdargs.Stdout, result.ToString())
+	}
+
+	if !result.OK && !interactive() {
+		os.Exit(1)
+	}
+}
@@ -8,65 +8,77 @@ package commands
 
 import (
 	"fmt"
-	"os"
 
-	"github.com/AlecAivazis/survey/v2"
 	"github.com/fatih/color"
-	"github.com/insolite-dev/notya/assets"
+	"github.com/insolite-dev/notya/lib/models"
 	"github.com/insolite-dev/notya/lib/services"
 	"github.com/insolite-dev/notya/pkg"
 	"github.com/spf13/cobra"
 )
 
 var pushCommand = &cobra.Command{
-	Use:   "push",
+	Use:   "push [service]",
+	Args:  cobra.MaximumNArgs(1),
 	Short: "Pushes all nodes from [X] service to [Y] service(in case, if nodes doesn't exists in [Y] service)",
 	Run:   runPushCommand,
 }
 
 func initPushCommand() {
+	pushCommand.Flags().BoolVarP(
+		&pruneNodes, "prune", "p", false,
+		"Remove nodes from [Y] service, that were removed from [X] service",
+	)
+
+	initPlanFlags(pushCommand)
+
 	appCommand.AddCommand(pushCommand)
 }
 
 func runPushCommand(cmd *cobra.Command, args []string) {
 	determineService()
-	loading.Start()
-
-	availableServices := []string{}
-	// Generate a list of available services
-	// by not including current service.
-	for _, s := range services.Services {
-		if service.Type() == s {
-			continue
-		}
 
-		availableServices = append(availableServices, s)
+	selected, err := selectService(args)
+	if err != nil {
+		alert(pkg.ErrorL, err.Error())
+		return
 	}
 
-	loading.Stop()
-
-	// Ask for service selection.
-	var selected string
-	survey.AskOne(
-		assets.ChooseRemotePrompt(availableServices),
-		&selected,
-	)
 	if len(selected) == 0 {
-		os.Exit(-1)
+		abort()
 		return
 	}
 
 	selectedService := serviceFromType(selected, true)
 
+	if dryRun {
+		loading.Start()
+		plan, err := services.PlanTransfer("push", service, selectedService, pruneNodes)
+		loading.Stop()
+
+		printPlan(plan, err)
+		return
+	}
+
 	loading.Start()
+	prunedNodes, pruneErrs := []models.Node{}, []error{}
+	if pruneNodes {
+		prunedNodes, pruneErrs = services.Prune(service, selectedService)
+	}
+
 	pushedNodes, errs := service.Push(selectedService)
+	errs = append(pruneErrs, errs...)
 	loading.Stop()
 
-	if len(pushedNodes) == 0 && len(errs) == 0 {
-		pkg.Print("Everything up-to-date", color.FgHiGreen)
+	if len(pushedNodes) == 0 && len(prunedNodes) == 0 && len(errs) == 0 {
+		printMessage("Everything up-to-date", color.FgHiGreen)
 		return
 	}
 
-	pkg.PrintErrors("push", errs)
-	pkg.Alert(pkg.SuccessL, fmt.Sprintf("Pushed %v nodes", len(pushedNodes)))
+	addNodes(pushedNodes...)
+	printErrors("push", errs)
+	alert(pkg.SuccessL, fmt.Sprintf("Pushed %v nodes", len(pushedNodes)))
+
+	if pruneNodes {
+		alert(pkg.SuccessL, fmt.Sprintf("Pruned %v nodes", len(prunedNodes)))
+	}
 }
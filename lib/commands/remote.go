@@ -8,9 +8,7 @@ package commands
 
 import (
 	"fmt"
-	"os"
 
-	"github.com/AlecAivazis/survey/v2"
 	"github.com/fatih/color"
 	"github.com/insolite-dev/notya/assets"
 	"github.com/insolite-dev/notya/lib/models"
@@ -58,18 +56,24 @@ func runRemoteCommand(cmd *cobra.Command, args []string) {
 	enabled, disabled := listAllRemote()
 	loading.Stop()
 
-	if len(enabled) > 0 {
-		pkg.Print("\nConnected Services:", color.FgGreen)
-		pkg.PrintServices(pkg.NOCOLOR, enabled)
-	}
+	remotes := map[string][]string{"connected": enabled, "unreachable": disabled}
+	printData(remotes, len(enabled), func() {
+		if len(enabled) > 0 {
+			printMessage("\nConnected Services:", color.FgGreen)
+			pkg.PrintServices(pkg.NOCOLOR, enabled)
+		}
 
-	if len(disabled) > 0 {
-		pkg.Print("\nUnreachable Services:", color.FgYellow)
-		pkg.PrintServices(pkg.NOCOLOR, disabled)
-	}
+		if len(disabled) > 0 {
+			printMessage("\nUnreachable Services:", color.FgYellow)
+			pkg.PrintServices(pkg.NOCOLOR, disabled)
+		}
+	})
 }
 
 // runRemoteConnectCommand connects to a new remote service connection.
+//
+// Remote services read their connections from local settings, so connections are
+// written to local settings, and their secrets are never written to other remotes.
 func runRemoteConnectCommand(cmd *cobra.Command, args []string) {
 	determineService()
 
@@ -77,18 +81,18 @@ func runRemoteConnectCommand(cmd *cobra.Command, args []string) {
 	loading.Stop()
 
 	if len(disabled) == 0 {
-		pkg.Alert(pkg.InfoL, "All remote service options are currently connected. You cannot establish additional connections at this time.")
+		alert(pkg.InfoL, "All remote service options are currently connected. You cannot establish additional connections at this time.")
 		return
 	}
 
 	// Ask for service selection.
 	var selected string
-	survey.AskOne(
+	ask(
 		assets.ChooseRemotePrompt(disabled),
 		&selected,
 	)
 	if len(selected) == 0 {
-		os.Exit(-1)
+		abort()
 		return
 	}
 
@@ -97,11 +101,11 @@ func runRemoteConnectCommand(cmd *cobra.Command, args []string) {
 		promptResult := models.Settings{}
 
 		// Ask for firebase prompt filling.
-		survey.Ask(assets.FirebaseRemoteConnectPromptQuestion, &promptResult)
+		askAll(assets.FirebaseRemoteConnectPromptQuestion, &promptResult)
 
 		loading.Start()
 
-		s := service.StateConfig()
+		s := localService.StateConfig()
 		updatedS := s.CopyWith(nil, nil, nil, nil, &promptResult.FirebaseProjectID, &promptResult.FirebaseAccountKey, &promptResult.FirebaseCollection)
 
 		// Validate provided firebase connection:
@@ -110,16 +114,122 @@ func runRemoteConnectCommand(cmd *cobra.Command, args []string) {
 		loading.Stop()
 
 		if !isEnabled {
-			pkg.Alert(pkg.ErrorL, "Unable to connect to the specified Firebase project using the provided credentials. Please check your login details and try again.")
+			alert(pkg.ErrorL, "Unable to connect to the specified Firebase project using the provided credentials. Please check your login details and try again.")
 			return
 		}
 
 		loading.Start()
-		service.WriteSettings(updatedS)
+		localService.WriteSettings(updatedS)
+		loading.Stop()
+	case services.S3.ToStr():
+		promptResult := models.Settings{}
+
+		// Ask for S3 prompt filling.
+		askAll(assets.S3RemoteConnectPromptQuestion, &promptResult)
+
+		loading.Start()
+
+		updatedS := localService.StateConfig()
+		updatedS.S3Endpoint = promptResult.S3Endpoint
+		updatedS.S3Bucket = promptResult.S3Bucket
+		updatedS.S3Region = promptResult.S3Region
+		updatedS.S3CredentialsFile = promptResult.S3CredentialsFile
+		updatedS.S3Prefix = promptResult.S3Prefix
+
+		// Validate provided S3 connection:
+		isEnabled := services.IsS3Enabled(updatedS, &localService)
+
+		loading.Stop()
+
+		if !isEnabled {
+			alert(pkg.ErrorL, "Unable to connect to the specified S3 bucket using the provided credentials. Please check your login details and try again.")
+			return
+		}
+
+		loading.Start()
+		localService.WriteSettings(updatedS)
+		loading.Stop()
+	case services.WEBDAV.ToStr():
+		promptResult := models.Settings{}
+
+		// Ask for WebDAV prompt filling.
+		askAll(assets.WebDAVRemoteConnectPromptQuestion, &promptResult)
+
+		loading.Start()
+
+		updatedS := localService.StateConfig()
+		updatedS.WebDAVURL = promptResult.WebDAVURL
+		updatedS.WebDAVUsername = promptResult.WebDAVUsername
+		updatedS.WebDAVPassword = promptResult.WebDAVPassword
+
+		// Validate provided WebDAV connection:
+		isEnabled := services.IsWebDAVEnabled(updatedS, &localService)
+
+		loading.Stop()
+
+		if !isEnabled {
+			alert(pkg.ErrorL, "Unable to connect to the specified WebDAV server using the provided credentials. Please check your login details and try again.")
+			return
+		}
+
+		loading.Start()
+		localService.WriteSettings(updatedS)
+		loading.Stop()
+	case services.SFTP.ToStr():
+		promptResult := models.Settings{}
+
+		// Ask for SFTP prompt filling.
+		askAll(assets.SFTPRemoteConnectPromptQuestion, &promptResult)
+
+		loading.Start()
+
+		updatedS := localService.StateConfig()
+		updatedS.SFTPHost = promptResult.SFTPHost
+		updatedS.SFTPUser = promptResult.SFTPUser
+		updatedS.SFTPKeyFile = promptResult.SFTPKeyFile
+		updatedS.SFTPPath = promptResult.SFTPPath
+
+		// Validate provided SFTP connection:
+		isEnabled := services.IsSFTPEnabled(updatedS, &localService)
+
+		loading.Stop()
+
+		if !isEnabled {
+			alert(pkg.ErrorL, "Unable to connect to the specified SSH server using the provided key. Please check your login details and known hosts, and try again.")
+			return
+		}
+
+		loading.Start()
+		localService.WriteSettings(updatedS)
+		loading.Stop()
+	case services.HTTP.ToStr():
+		promptResult := models.Settings{}
+
+		// Ask for HTTP prompt filling.
+		askAll(assets.HTTPRemoteConnectPromptQuestion, &promptResult)
+
+		loading.Start()
+
+		updatedS := localService.StateConfig()
+		updatedS.HTTPURL = promptResult.HTTPURL
+		updatedS.HTTPToken = promptResult.HTTPToken
+
+		// Validate provided HTTP connection:
+		isEnabled := services.IsHTTPEnabled(updatedS, &localService)
+
+		loading.Stop()
+
+		if !isEnabled {
+			alert(pkg.ErrorL, "Unable to connect to the specified notya server using the provided token. Please check your login details and try again.")
+			return
+		}
+
+		loading.Start()
+		localService.WriteSettings(updatedS)
 		loading.Stop()
 	}
 
-	pkg.Alert(pkg.SuccessL, fmt.Sprintf("Successfully connected to the specified %s project.", selected))
+	alert(pkg.SuccessL, fmt.Sprintf("Successfully connected to the specified %s project.", selected))
 }
 
 // runRemoteDisconnectCommand removes connection from concrete remove service
@@ -131,18 +241,18 @@ func runRemoteDisconnectCommand(cmd *cobra.Command, args []string) {
 	loading.Stop()
 
 	if len(enabled) == 0 {
-		pkg.Alert(pkg.InfoL, "There are no active remote connections to disconnect from")
+		alert(pkg.InfoL, "There are no active remote connections to disconnect from")
 		return
 	}
 
 	// Ask for service selection.
 	var selected string
-	survey.AskOne(
+	ask(
 		assets.ChooseRemotePrompt(enabled),
 		&selected,
 	)
 	if len(selected) == 0 {
-		os.Exit(-1)
+		abort()
 		return
 	}
 
@@ -150,13 +260,29 @@ func runRemoteDisconnectCommand(cmd *cobra.Command, args []string) {
 	switch selected {
 	case services.FIRE.ToStr():
 		empty := ("")
-		s := service.StateConfig()
-		service.WriteSettings(s.CopyWith(nil, nil, nil, nil, &empty, &empty, &empty))
+		s := localService.StateConfig()
+		localService.WriteSettings(s.CopyWith(nil, nil, nil, nil, &empty, &empty, &empty))
+	case services.S3.ToStr():
+		s := localService.StateConfig()
+		s.S3Endpoint, s.S3Bucket, s.S3Region, s.S3CredentialsFile, s.S3Prefix = "", "", "", "", ""
+		localService.WriteSettings(s)
+	case services.WEBDAV.ToStr():
+		s := localService.StateConfig()
+		s.WebDAVURL, s.WebDAVUsername, s.WebDAVPassword = "", "", ""
+		localService.WriteSettings(s)
+	case services.SFTP.ToStr():
+		s := localService.StateConfig()
+		s.SFTPHost, s.SFTPUser, s.SFTPKeyFile, s.SFTPPath, s.SFTPKnownHosts = "", "", "", "", ""
+		localService.WriteSettings(s)
+	case services.HTTP.ToStr():
+		s := localService.StateConfig()
+		s.HTTPURL, s.HTTPToken = "", ""
+		localService.WriteSettings(s)
 	}
 
 	loading.Stop()
 
-	pkg.Alert(pkg.SuccessL, fmt.Sprintf("Successfully disconnected from specified %s service", selected))
+	alert(pkg.SuccessL, fmt.Sprintf("Successfully disconnected from specified %s service", selected))
 }
 
 // Returns a list of all remote services by splitting them by their enabled or disabled level.
@@ -167,7 +293,31 @@ func listAllRemote() ([]string, []string) {
 	for _, s := range services.RemoteServices {
 		switch s {
 		case services.FIRE.ToStr():
-			if services.IsFirebaseEnabled(service.StateConfig(), &localService) {
+			if services.IsFirebaseEnabled(localService.StateConfig(), &localService) {
+				allEnabled = append(allEnabled, s)
+			} else {
+				allDisabled = append(allDisabled, s)
+			}
+		case services.S3.ToStr():
+			if services.IsS3Enabled(localService.StateConfig(), &localService) {
+				allEnabled = append(allEnabled, s)
+			} else {
+				allDisabled = append(allDisabled, s)
+			}
+		case services.WEBDAV.ToStr():
+			if services.IsWebDAVEnabled(localService.StateConfig(), &localService) {
+				allEnabled = append(allEnabled, s)
+			} else {
+				allDisabled = append(allDisabled, s)
+			}
+		case services.SFTP.ToStr():
+			if services.IsSFTPEnabled(localService.StateConfig(), &localService) {
+				allEnabled = append(allEnabled, s)
+			} else {
+				allDisabled = append(allDisabled, s)
+			}
+		case services.HTTP.ToStr():
+			if services.IsHTTPEnabled(localService.StateConfig(), &localService) {
 				allEnabled = append(allEnabled, s)
 			} else {
 				allDisabled = append(allDisabled, s)
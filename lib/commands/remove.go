@@ -8,11 +8,10 @@ package commands
 
 import (
 	"fmt"
-	"os"
 
-	"github.com/AlecAivazis/survey/v2"
 	"github.com/insolite-dev/notya/assets"
 	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/lib/services"
 	"github.com/insolite-dev/notya/pkg"
 	"github.com/spf13/cobra"
 )
@@ -25,7 +24,7 @@ var removeCommand = &cobra.Command{
 	Run:     runRemoveCommand,
 }
 
-var removeAll bool
+var removeAll, removePermanent bool
 
 // initRemoveCommand adds removeCommand to main application command.
 func initRemoveCommand() {
@@ -33,6 +32,10 @@ func initRemoveCommand() {
 		&removeAll, "all", "a", false,
 		"Remove all nodes (including nodes under the directories)",
 	)
+	removeCommand.Flags().BoolVarP(
+		&removePermanent, "permanent", "p", false,
+		"Remove permanently, without moving to trash",
+	)
 
 	appCommand.AddCommand(removeCommand)
 }
@@ -43,11 +46,24 @@ func runRemoveCommand(cmd *cobra.Command, args []string) {
 
 	if removeAll {
 		loading.Start()
-		clearedNodes, errs := service.ClearNodes()
+		clearNodes := service.ClearNodes
+		if !removePermanent {
+			clearNodes = func() ([]models.Node, []error) { return services.TrashAll(service) }
+		}
+
+		clearedNodes, errs := clearNodes()
 		loading.Stop()
 
-		pkg.PrintErrors("remove", errs)
-		pkg.Alert(pkg.SuccessL, fmt.Sprintf("Removed %v nodes", len(clearedNodes)))
+		// Services without trash, remove all nodes only if it's asked explicitly.
+		if len(errs) == 1 && errs[0] == assets.TrashNotAvailable {
+			alert(pkg.ErrorL, errs[0].Error())
+			alert(pkg.InfoL, "Use --permanent flag, to remove all nodes permanently")
+			return
+		}
+
+		addNodes(clearedNodes...)
+		printErrors("remove", errs)
+		alert(pkg.SuccessL, fmt.Sprintf("Removed %v nodes", len(clearedNodes)))
 		return
 	}
 
@@ -64,13 +80,13 @@ func runRemoveCommand(cmd *cobra.Command, args []string) {
 
 	loading.Stop()
 	if err != nil {
-		pkg.Alert(pkg.ErrorL, err.Error())
+		alert(pkg.ErrorL, err.Error())
 		return
 	}
 
 	// Ask for node selection.
 	var selected string
-	survey.AskOne(
+	ask(
 		assets.ChooseNodePrompt("node", "remove", nodeNames),
 		&selected,
 	)
@@ -81,16 +97,33 @@ func runRemoveCommand(cmd *cobra.Command, args []string) {
 // removeAndFinish removes given node and alerts success message if everything is OK.
 func removeAndFinish(node models.Node) {
 	if len(node.Title) == 0 {
-		os.Exit(-1)
+		abort()
 		return
 	}
 
 	loading.Start()
 
-	err := service.Remove(node)
+	var err error
+	if removePermanent {
+		err = service.Remove(node)
+	} else if err = services.MoveToTrash(service, node); err == assets.TrashNotAvailable {
+		loading.Stop()
+		warnPermanent()
+
+		loading.Start()
+		err = service.Remove(node)
+	}
 
 	loading.Stop()
 	if err != nil {
-		pkg.Alert(pkg.ErrorL, err.Error())
+		alert(pkg.ErrorL, err.Error())
+		return
 	}
+
+	addNodes(node)
+}
+
+// warnPermanent alerts that removed nodes aren't kept at trash, since current service doesn't have one.
+func warnPermanent() {
+	alert(pkg.InfoL, fmt.Sprintf("Trash is not available for %v service, removing permanently", service.Type()))
 }
@@ -7,11 +7,11 @@
 package commands
 
 import (
-	"os"
+	"fmt"
 
-	"github.com/AlecAivazis/survey/v2"
 	"github.com/insolite-dev/notya/assets"
 	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/lib/services"
 	"github.com/insolite-dev/notya/pkg"
 	"github.com/spf13/cobra"
 )
@@ -24,8 +24,16 @@ var renameCommand = &cobra.Command{
 	Run:     runRenameCommand,
 }
 
+// noRelink is the value of no-relink flag.
+var noRelink bool
+
 // initRenameCommand adds renameCommand to main application command.
 func initRenameCommand() {
+	renameCommand.Flags().BoolVar(
+		&noRelink, "no-relink", false,
+		"Don't rewrite links of notes, that point to renamed node",
+	)
+
 	appCommand.AddCommand(renameCommand)
 }
 
@@ -53,13 +61,13 @@ func runRenameCommand(cmd *cobra.Command, args []string) {
 	loading.Stop()
 
 	if err != nil {
-		pkg.Alert(pkg.ErrorL, err.Error())
+		alert(pkg.ErrorL, err.Error())
 		return
 	}
 
 	// Ask for node selection.
 	var selected string
-	survey.AskOne(
+	ask(
 		assets.ChooseNodePrompt("node", "rename", nodeNames),
 		&selected,
 	)
@@ -71,10 +79,10 @@ func runRenameCommand(cmd *cobra.Command, args []string) {
 // (for selected node), and changes its name.
 func askAndRename(selected string) {
 	var newname string
-	survey.AskOne(assets.NewNamePrompt(selected), &newname)
+	ask(assets.NewNamePrompt(selected), &newname)
 
 	if len(newname) == 0 {
-		os.Exit(-1)
+		abort()
 		return
 	}
 
@@ -84,7 +92,7 @@ func askAndRename(selected string) {
 // rename takes selected and newname, then makes changes and alerts it.
 func rename(selected string, newname string) {
 	if len(selected) == 0 || len(newname) == 0 {
-		os.Exit(-1)
+		abort()
 		return
 	}
 
@@ -94,12 +102,33 @@ func rename(selected string, newname string) {
 		New:     models.Node{Title: newname},
 	}
 
+	if noRelink {
+		loading.Start()
+		err := service.Rename(editNode)
+		loading.Stop()
+
+		if err != nil {
+			alert(pkg.ErrorL, err.Error())
+			return
+		}
+
+		addNodes(editNode.New)
+		return
+	}
+
 	loading.Start()
-	err := service.Rename(editNode)
+	relinked, errs := services.RenameAndRelink(service, editNode)
 	loading.Stop()
 
-	if err != nil {
-		pkg.Alert(pkg.ErrorL, err.Error())
+	// A single error without relinked notes is alerted, like failure of plain rename.
+	if len(relinked) == 0 && len(errs) == 1 {
+		alert(pkg.ErrorL, errs[0].Error())
 		return
 	}
+
+	addNodes(editNode.New)
+	printErrors("relink", errs)
+	if len(relinked) > 0 {
+		alert(pkg.SuccessL, fmt.Sprintf("Rewrote links of %v notes", len(relinked)))
+	}
 }
@@ -0,0 +1,91 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/insolite-dev/notya/assets"
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/lib/services"
+	"github.com/insolite-dev/notya/pkg"
+	"github.com/spf13/cobra"
+)
+
+// searchCommand is a command model that used to search nodes by their titles and bodies.
+var searchCommand = &cobra.Command{
+	Use:     "search",
+	Aliases: []string{"find", "grep"},
+	Short:   "Search notes by their titles and contents",
+	Run:     runSearchCommand,
+}
+
+// searchOpts is the options of search, that filled by flags.
+var searchOpts models.SearchOptions
+
+// rankedSearch is the value of ranked flag.
+var rankedSearch bool
+
+// initSearchCommand adds searchCommand to main application command.
+func initSearchCommand() {
+	searchCommand.Flags().BoolVarP(
+		&searchOpts.IgnoreCase, "ignore-case", "i", false,
+		"Search case-insensitively",
+	)
+	searchCommand.Flags().BoolVarP(
+		&searchOpts.Regex, "regex", "e", false,
+		"Use query as a regular expression",
+	)
+	searchCommand.Flags().BoolVarP(
+		&searchOpts.Word, "word", "w", false,
+		"Match only whole words",
+	)
+	searchCommand.Flags().StringVar(
+		&searchOpts.In, "in", "",
+		"Search only inside of provided folder",
+	)
+	searchCommand.Flags().BoolVarP(
+		&rankedSearch, "ranked", "r", false,
+		"Rank results by relevance, via the search index of local service",
+	)
+
+	appCommand.AddCommand(searchCommand)
+}
+
+// runSearchCommand runs full-text search on current service, and logs matched lines.
+func runSearchCommand(cmd *cobra.Command, args []string) {
+	determineService()
+
+	searchOpts.Query = strings.Join(args, " ")
+	if len(searchOpts.Query) == 0 {
+		askAll(assets.SearchPromptQuestion, &searchOpts.Query)
+	}
+
+	search := services.Search
+	if rankedSearch {
+		search = services.RankedSearch
+	}
+
+	loading.Start()
+	results, err := search(service, searchOpts)
+	loading.Stop()
+
+	if err != nil {
+		alert(pkg.ErrorL, err.Error())
+		return
+	}
+
+	if len(results) == 0 {
+		printMessage("Nothing found", color.FgHiYellow)
+		return
+	}
+
+	printData(results, len(results), func() { pkg.PrintSearchResults(results) })
+	alert(pkg.SuccessL, fmt.Sprintf("Found %v nodes", len(results)))
+}
@@ -0,0 +1,110 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package commands
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/lib/services"
+	"github.com/insolite-dev/notya/pkg"
+	"github.com/spf13/cobra"
+)
+
+// serveCommand is a command model that used to serve current service over REST API.
+var serveCommand = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve notes of current service over REST API, for HTTP remote service of other notya instances",
+	Run:   runServeCommand,
+}
+
+// Timeouts of server, so slow (or stalled) clients don't hold connections forever.
+const (
+	serveReadHeaderTimeout = 10 * time.Second
+	serveReadTimeout       = time.Minute
+	serveWriteTimeout      = time.Minute
+	serveIdleTimeout       = 2 * time.Minute
+)
+
+var (
+	serveAddr  string // value of addr flag.
+	serveToken string // value of token flag.
+	serveCert  string // value of cert flag.
+	serveKey   string // value of key flag.
+)
+
+// initServeCommand adds [serveCommand] to the [appCommand].
+func initServeCommand() {
+	serveCommand.Flags().StringVar(
+		&serveAddr, "addr", "127.0.0.1:7878",
+		"The address to listen on",
+	)
+	serveCommand.Flags().StringVar(
+		&serveToken, "token", "",
+		"The access token of API (or NOTYA_HTTP_TOKEN), generated randomly if it's empty",
+	)
+	serveCommand.Flags().StringVar(
+		&serveCert, "cert", "",
+		"The TLS certificate file, to serve over HTTPS",
+	)
+	serveCommand.Flags().StringVar(
+		&serveKey, "key", "",
+		"The TLS private key file, to serve over HTTPS",
+	)
+
+	appCommand.AddCommand(serveCommand)
+}
+
+// runServeCommand serves current service over REST API, until the process is stopped.
+func runServeCommand(cmd *cobra.Command, args []string) {
+	determineService()
+
+	token := serveToken
+	if len(token) == 0 {
+		token = os.Getenv(models.HTTPTokenEnv)
+	}
+
+	if len(token) == 0 {
+		secret := make([]byte, 16)
+		if _, err := rand.Read(secret); err != nil {
+			alert(pkg.ErrorL, err.Error())
+			finish(nil)
+			return
+		}
+
+		token = hex.EncodeToString(secret)
+		alert(pkg.InfoL, fmt.Sprintf("Generated access token: %v", token))
+	}
+
+	// Server is long-running, so changes of search index are written per request.
+	flushIndex()
+
+	server := &http.Server{
+		Addr:              serveAddr,
+		Handler:           services.NewHTTPHandler(service, token),
+		ReadHeaderTimeout: serveReadHeaderTimeout,
+		ReadTimeout:       serveReadTimeout,
+		WriteTimeout:      serveWriteTimeout,
+		IdleTimeout:       serveIdleTimeout,
+	}
+	alert(pkg.InfoL, fmt.Sprintf("Serving %v service at %v%v", service.Type(), serveAddr, services.APIPrefix))
+
+	var err error
+	if len(serveCert) > 0 || len(serveKey) > 0 {
+		err = server.ListenAndServeTLS(serveCert, serveKey)
+	} else {
+		err = server.ListenAndServe()
+	}
+
+	alert(pkg.ErrorL, err.Error())
+	finish(nil)
+}
@@ -7,7 +7,6 @@
 package commands
 
 import (
-	"github.com/AlecAivazis/survey/v2"
 	"github.com/fatih/color"
 	"github.com/insolite-dev/notya/assets"
 	"github.com/insolite-dev/notya/pkg"
@@ -51,32 +50,33 @@ func runSettingsCommand(cmd *cobra.Command, args []string) {
 	loading.Stop()
 
 	if err != nil {
-		pkg.Alert(pkg.ErrorL, err.Error())
+		alert(pkg.ErrorL, err.Error())
 		return
 	}
 
 	// Print settings' current values.
-	pkg.PrintSettings(*settings)
-	pkg.Print("\n > [notya settings -h/help] for more", color.FgGreen)
+	printData(settings, 1, func() { pkg.PrintSettings(*settings) })
+	printMessage("\n > [notya settings -h/help] for more", color.FgGreen)
 }
 
 // runViewSettingsCommand runs appropriate service functionalities
 // to open settings file(json) with CURRENT editor.
 func runEditSettingsCommand(cmd *cobra.Command, args []string) {
 	determineService()
+	requireEditor()
 
 	loading.Start()
 	beforeSettings, err := service.Settings(nil)
 	loading.Stop()
 
 	if err != nil {
-		pkg.Alert(pkg.ErrorL, err.Error())
+		alert(pkg.ErrorL, err.Error())
 		return
 	}
 
 	openErr := service.OpenSettings(*beforeSettings)
 	if openErr != nil {
-		pkg.Alert(pkg.ErrorL, openErr.Error())
+		alert(pkg.ErrorL, openErr.Error())
 		return
 	}
 
@@ -85,14 +85,14 @@ func runEditSettingsCommand(cmd *cobra.Command, args []string) {
 	loading.Stop()
 
 	if err != nil {
-		pkg.Alert(pkg.ErrorL, err.Error())
+		alert(pkg.ErrorL, err.Error())
 		return
 	}
 
 	// Ask to move notes if path were updated.
 	if pkg.IsPathUpdated(*beforeSettings, *afterSettings, service.Type()) {
 		var moveNotes bool
-		if survey.AskOne(assets.MoveNotesPrompt, &moveNotes); !moveNotes {
+		if ask(assets.MoveNotesPrompt, &moveNotes); !moveNotes {
 			return
 		}
 
@@ -101,7 +101,7 @@ func runEditSettingsCommand(cmd *cobra.Command, args []string) {
 		loading.Stop()
 
 		if err != nil {
-			pkg.Alert(pkg.ErrorL, err.Error())
+			alert(pkg.ErrorL, err.Error())
 		}
 	}
 }
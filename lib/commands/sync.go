@@ -0,0 +1,104 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package commands
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/lib/services"
+	"github.com/insolite-dev/notya/pkg"
+	"github.com/spf13/cobra"
+)
+
+// syncCommand is a command model that used to synchronize two services in both directions.
+var syncCommand = &cobra.Command{
+	Use:   "sync [service]",
+	Args:  cobra.MaximumNArgs(1),
+	Short: "Synchronizes [X] and [Y] services in both directions, and reports conflicts",
+	Run:   runSyncCommand,
+}
+
+// conflictCopies is the value of copies flag.
+var conflictCopies bool
+
+// initSyncCommand adds syncCommand to main application command.
+func initSyncCommand() {
+	syncCommand.Flags().BoolVarP(
+		&conflictCopies, "copies", "c", false,
+		"Resolve conflicts via side-by-side conflict copies, instead of conflict markers",
+	)
+
+	appCommand.AddCommand(syncCommand)
+}
+
+// runSyncCommand runs three-way synchronization between current and selected services.
+func runSyncCommand(cmd *cobra.Command, args []string) {
+	determineService()
+
+	selected, err := selectService(args)
+	if err != nil {
+		alert(pkg.ErrorL, err.Error())
+		return
+	}
+
+	if len(selected) == 0 {
+		abort()
+		return
+	}
+
+	selectedService := serviceFromType(selected, true)
+
+	// Snapshots are always kept at local notya directory.
+	notyaPath, _ := localService.Path()
+	snapshotsPath := notyaPath + models.SnapshotsName
+
+	snapshots := models.Snapshots{}
+	if data, err := pkg.ReadBody(snapshotsPath); err == nil {
+		snapshots = models.DecodeSnapshots(*data)
+	}
+
+	// Snapshots are kept per connection, so a remote connected to another place doesn't share the base of previous one.
+	key := models.SnapshotKey(services.ServiceKey(service), services.ServiceKey(selectedService))
+
+	loading.Start()
+	report, base, errs := services.Sync(service, selectedService, snapshots.Get(key), conflictCopies)
+	loading.Stop()
+
+	snapshots[key] = base
+	if err := pkg.WriteNote(snapshotsPath, snapshots.ToString()); err != nil {
+		errs = append(errs, err)
+	}
+
+	printErrors("sync", errs)
+
+	if report.IsEmpty() && len(errs) == 0 {
+		printMessage("Already up to date", color.FgHiGreen)
+		return
+	}
+
+	total := len(report.Pulled) + len(report.Pushed) + len(report.Removed) + len(report.Conflicts)
+	printData(report, total, func() {
+		if len(report.Conflicts) == 0 {
+			return
+		}
+
+		conflicts := []string{}
+		for _, c := range report.Conflicts {
+			conflicts = append(conflicts, c.Title)
+		}
+
+		printMessage("\nConflicts:", color.FgRed)
+		pkg.PrintServices(pkg.RED, conflicts)
+	})
+
+	alert(pkg.SuccessL, fmt.Sprintf(
+		"Pulled %v, pushed %v, removed %v nodes | %v conflicts",
+		len(report.Pulled), len(report.Pushed), len(report.Removed), len(report.Conflicts),
+	))
+}
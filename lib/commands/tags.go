@@ -0,0 +1,47 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package commands
+
+import (
+	"github.com/fatih/color"
+	"github.com/insolite-dev/notya/lib/services"
+	"github.com/insolite-dev/notya/pkg"
+	"github.com/spf13/cobra"
+)
+
+// tagsCommand is a command model that used to list all tags of notes.
+var tagsCommand = &cobra.Command{
+	Use:   "tags",
+	Short: "List all tags of notes, with amount of notes including them",
+	Run:   runTagsCommand,
+}
+
+// initTagsCommand adds tagsCommand to main application command.
+func initTagsCommand() {
+	appCommand.AddCommand(tagsCommand)
+}
+
+// runTagsCommand collects tags from front matter of all notes, and logs them.
+func runTagsCommand(cmd *cobra.Command, args []string) {
+	determineService()
+
+	loading.Start()
+	counts, err := services.TagCounts(service)
+	loading.Stop()
+
+	if err != nil {
+		alert(pkg.ErrorL, err.Error())
+		return
+	}
+
+	if len(counts) == 0 {
+		printMessage("No tags found", color.FgHiYellow)
+		return
+	}
+
+	printData(counts, len(counts), func() { pkg.PrintTags(counts) })
+}
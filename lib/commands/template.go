@@ -0,0 +1,235 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"time"
+
+	"github.com/insolite-dev/notya/assets"
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/lib/services"
+	"github.com/insolite-dev/notya/pkg"
+	"github.com/spf13/cobra"
+)
+
+// templateCommand is a command model that used to manage note templates.
+var templateCommand = &cobra.Command{
+	Use:     "template",
+	Aliases: []string{"templates"},
+	Short:   "Manage templates, that notes could be created from",
+	Run:     runTemplateListCommand,
+}
+
+// templateListCommand is a command model that used to list note templates.
+var templateListCommand = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List all note templates",
+	Run:     runTemplateListCommand,
+}
+
+// templateNewCommand is a command model that used to create a note template.
+var templateNewCommand = &cobra.Command{
+	Use:     "new",
+	Aliases: []string{"create"},
+	Short:   "Create new note template",
+	Run:     runTemplateNewCommand,
+}
+
+// templateEditCommand is a command model that used to edit a note template.
+var templateEditCommand = &cobra.Command{
+	Use:   "edit",
+	Short: "Edit note template via editor",
+	Run:   runTemplateEditCommand,
+}
+
+var (
+	providedTemplate string            // value of template flag.
+	templateVars     map[string]string // value of var flag.
+)
+
+// initTemplateCommand adds [templateCommand] to the [appCommand].
+func initTemplateCommand() {
+	initBodyFlags(templateNewCommand)
+
+	templateCommand.AddCommand(templateListCommand, templateNewCommand, templateEditCommand)
+
+	appCommand.AddCommand(templateCommand)
+}
+
+// initTemplateFlags adds template related flags to given [cmd].
+func initTemplateFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(
+		&providedTemplate, "template", "t", "",
+		"Name of template, that note is created from",
+	)
+	cmd.Flags().StringToStringVar(
+		&templateVars, "var", nil,
+		"Value of template variable, instead of asking for it, like: --var project=notya",
+	)
+}
+
+// runTemplateListCommand logs all templates of service.
+func runTemplateListCommand(cmd *cobra.Command, args []string) {
+	determineService()
+
+	loading.Start()
+	templates, err := services.Templates(service)
+	loading.Stop()
+
+	if err != nil {
+		alert(pkg.ErrorL, err.Error())
+		return
+	}
+
+	if len(templates) == 0 {
+		alert(pkg.InfoL, "There are no templates, create one via: notya template new <name>")
+		return
+	}
+
+	printNodes(templates)
+}
+
+// runTemplateNewCommand creates new template, from provided body, or opens it with editor.
+func runTemplateNewCommand(cmd *cobra.Command, args []string) {
+	determineService()
+
+	body, piped, err := readBodyInput()
+	if err != nil {
+		alert(pkg.ErrorL, err.Error())
+		return
+	}
+
+	var name string
+	if len(args) > 0 {
+		name = args[0]
+	} else if piped {
+		alert(pkg.ErrorL, assets.TitleRequired.Error())
+		return
+	} else {
+		askAll(assets.CreatePromptQuestion, &name)
+	}
+
+	if len(name) == 0 {
+		abort()
+		return
+	}
+
+	var text string
+	if body != nil {
+		text = *body
+	}
+
+	loading.Start()
+	template, err := services.NewTemplate(service, name, text)
+	loading.Stop()
+
+	if err != nil {
+		alert(pkg.ErrorL, err.Error())
+		return
+	}
+
+	addNodes(template.ToNode())
+
+	// Editor is opened only for an empty template.
+	if body != nil || !interactive() {
+		alert(pkg.SuccessL, fmt.Sprintf("Created template %v", name))
+		return
+	}
+
+	if err := service.Open(template.ToNode()); err != nil {
+		alert(pkg.ErrorL, err.Error())
+	}
+}
+
+// runTemplateEditCommand opens provided (or selected) template with editor.
+func runTemplateEditCommand(cmd *cobra.Command, args []string) {
+	determineService()
+	requireEditor()
+
+	var name string
+	if len(args) > 0 {
+		name = args[0]
+	} else {
+		loading.Start()
+		templates, err := services.Templates(service)
+		loading.Stop()
+
+		if err != nil {
+			alert(pkg.ErrorL, err.Error())
+			return
+		}
+
+		names := []string{}
+		for _, t := range templates {
+			names = append(names, t.Title)
+		}
+
+		ask(assets.ChooseNodePrompt("template", "edit", names), &name)
+	}
+
+	if len(name) == 0 {
+		abort()
+		return
+	}
+
+	loading.Start()
+	template, err := services.ViewTemplate(service, name)
+	loading.Stop()
+
+	if err != nil {
+		alert(pkg.ErrorL, err.Error())
+		return
+	}
+
+	if err := service.Open(template.ToNode()); err != nil {
+		alert(pkg.ErrorL, err.Error())
+	}
+}
+
+// renderTemplate renders the template of [name], for the note of [title].
+// Variables, those aren't built-in or provided via flags, are asked.
+func renderTemplate(name, title string) (string, error) {
+	loading.Start()
+	template, err := services.ViewTemplate(service, name)
+	loading.Stop()
+
+	if err != nil {
+		return "", err
+	}
+
+	vars, err := models.TemplateVars(template.Body)
+	if err != nil {
+		return "", err
+	}
+
+	values := models.TemplateBuiltins(title, currentUser(), service.Type(), time.Now())
+	for _, v := range vars {
+		if value, ok := templateVars[v]; ok {
+			values[v] = value
+			continue
+		}
+
+		var value string
+		ask(assets.TemplateVarPrompt(v), &value)
+		values[v] = value
+	}
+
+	return models.RenderTemplate(template.Body, values)
+}
+
+// currentUser returns the name of current machine user.
+func currentUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+
+	return os.Getenv("USER")
+}
@@ -0,0 +1,148 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/insolite-dev/notya/assets"
+	"github.com/insolite-dev/notya/lib/services"
+	"github.com/insolite-dev/notya/pkg"
+	"github.com/spf13/cobra"
+)
+
+// trashCommand is a command model that used to manage removed nodes.
+var trashCommand = &cobra.Command{
+	Use:   "trash",
+	Short: "Manage removed nodes, which are kept at trash",
+	Run:   runTrashListCommand,
+}
+
+// trashListCommand is a command model that used to list removed nodes.
+var trashListCommand = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List removed nodes, from the latest to the earliest",
+	Run:     runTrashListCommand,
+}
+
+// trashRestoreCommand is a command model that used to restore a removed node.
+var trashRestoreCommand = &cobra.Command{
+	Use:   "restore",
+	Short: "Restore a removed node to its original place",
+	Run:   runTrashRestoreCommand,
+}
+
+// trashEmptyCommand is a command model that used to remove nodes of trash permanently.
+var trashEmptyCommand = &cobra.Command{
+	Use:   "empty",
+	Short: "Permanently remove nodes of trash",
+	Run:   runTrashEmptyCommand,
+}
+
+var trashOlderThan string
+
+// initTrashCommand adds [trashCommand] to the [appCommand].
+func initTrashCommand() {
+	trashEmptyCommand.Flags().StringVar(
+		&trashOlderThan, "older-than", "",
+		"Remove only nodes, that were removed before given age, like: 12h, 30d, 2w",
+	)
+
+	trashCommand.AddCommand(trashListCommand, trashRestoreCommand, trashEmptyCommand)
+
+	appCommand.AddCommand(trashCommand)
+}
+
+// runTrashListCommand logs all entries of trash.
+func runTrashListCommand(cmd *cobra.Command, args []string) {
+	determineService()
+
+	loading.Start()
+	trash, err := services.TrashList(service)
+	loading.Stop()
+
+	if err != nil {
+		alert(pkg.ErrorL, err.Error())
+		return
+	}
+
+	if len(trash) == 0 {
+		alert(pkg.InfoL, "Trash is empty")
+		return
+	}
+
+	printData(trash, len(trash), func() { pkg.PrintTrash(trash) })
+}
+
+// runTrashRestoreCommand restores the latest removed node of provided (or selected) title.
+func runTrashRestoreCommand(cmd *cobra.Command, args []string) {
+	determineService()
+
+	var title string
+	if len(args) > 0 {
+		title = args[0]
+	} else {
+		loading.Start()
+		trash, err := services.TrashList(service)
+		loading.Stop()
+
+		if err != nil {
+			alert(pkg.ErrorL, err.Error())
+			return
+		}
+
+		titles := []string{}
+		for _, e := range trash {
+			titles = append(titles, e.Title)
+		}
+
+		ask(assets.ChooseNodePrompt("node", "restore", titles), &title)
+	}
+
+	if len(title) == 0 {
+		abort()
+		return
+	}
+
+	loading.Start()
+	entry, err := services.RestoreTrash(service, title)
+	loading.Stop()
+
+	if err != nil {
+		alert(pkg.ErrorL, err.Error())
+		return
+	}
+
+	addNodes(entry.Nodes...)
+	alert(pkg.SuccessL, fmt.Sprintf("Restored %v", entry.Title))
+}
+
+// runTrashEmptyCommand permanently removes all (or only old) entries of trash.
+func runTrashEmptyCommand(cmd *cobra.Command, args []string) {
+	determineService()
+
+	var olderThan time.Duration
+	if len(trashOlderThan) > 0 {
+		age, err := pkg.ParseAge(trashOlderThan)
+		if err != nil {
+			alert(pkg.ErrorL, err.Error())
+			return
+		}
+
+		olderThan = age
+	}
+
+	loading.Start()
+	emptied, errs := services.EmptyTrash(service, olderThan)
+	loading.Stop()
+
+	printData(emptied, len(emptied), func() {})
+	printErrors("empty", errs)
+	alert(pkg.SuccessL, fmt.Sprintf("Permanently removed %v entries of trash", len(emptied)))
+}
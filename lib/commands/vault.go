@@ -0,0 +1,244 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/insolite-dev/notya/assets"
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/lib/services"
+	"github.com/insolite-dev/notya/pkg"
+	"github.com/spf13/cobra"
+)
+
+// vaultCommand is a command model that used to manage vault folders.
+var vaultCommand = &cobra.Command{
+	Use:   "vault",
+	Short: "Manage vault folders, which are encrypted under their own passphrases",
+	Run:   runVaultListCommand,
+}
+
+// vaultCreateCommand is a command model that used to create a vault folder.
+var vaultCreateCommand = &cobra.Command{
+	Use:   "create <folder>",
+	Short: "Make a folder vault, and encrypt its notes",
+	Args:  cobra.ExactArgs(1),
+	Run:   runVaultCreateCommand,
+}
+
+// vaultUnlockCommand is a command model that used to unlock a vault folder.
+var vaultUnlockCommand = &cobra.Command{
+	Use:   "unlock <folder>",
+	Short: "Unlock a vault folder for a while",
+	Args:  cobra.ExactArgs(1),
+	Run:   runVaultUnlockCommand,
+}
+
+// vaultLockCommand is a command model that used to lock vault folders.
+var vaultLockCommand = &cobra.Command{
+	Use:   "lock [folder]",
+	Short: "Lock a vault folder, or all vault folders",
+	Args:  cobra.MaximumNArgs(1),
+	Run:   runVaultLockCommand,
+}
+
+// vaultTTL is the age, that unlocked vaults are kept unlocked for.
+var vaultTTL string
+
+// initVaultCommand adds [vaultCommand] to the [appCommand].
+func initVaultCommand() {
+	for _, cmd := range []*cobra.Command{vaultCreateCommand, vaultUnlockCommand} {
+		cmd.Flags().StringVar(
+			&vaultTTL, "ttl", "15m",
+			"Keep vault unlocked for provided age, like: 30m, 12h, 7d",
+		)
+	}
+
+	vaultCommand.AddCommand(vaultCreateCommand, vaultUnlockCommand, vaultLockCommand)
+
+	appCommand.AddCommand(vaultCommand)
+}
+
+// withVaults wraps [s] by vault service, if there's any vault folder.
+func withVaults(s services.ServiceRepo) services.ServiceRepo {
+	if localService == nil {
+		return s
+	}
+
+	settings := localService.StateConfig()
+	if len(settings.Vaults) == 0 {
+		return s
+	}
+
+	return services.NewVaultService(s, stdargs, services.SessionKey(services.VaultSessionsPath(settings)))
+}
+
+// findVault returns the vault at [folder], or [assets.NotVault] if there's no such vault.
+func findVault(folder string) (*models.Vault, error) {
+	settings := localService.StateConfig()
+	for i, v := range settings.Vaults {
+		if v.Folder == models.VaultFolder(folder) {
+			return &settings.Vaults[i], nil
+		}
+	}
+
+	return nil, assets.NotVault(models.VaultFolder(folder))
+}
+
+// runVaultListCommand logs all vault folders, with their lock state.
+func runVaultListCommand(cmd *cobra.Command, args []string) {
+	settings := localService.StateConfig()
+	if len(settings.Vaults) == 0 {
+		alert(pkg.InfoL, "There is no vault, create one via: notya vault create <folder>")
+		return
+	}
+
+	sessions := services.ReadVaultSessions(services.VaultSessionsPath(settings))
+
+	// Keys of sessions are never kept at result, only the lock states.
+	states := []map[string]interface{}{}
+	for _, v := range settings.Vaults {
+		state := map[string]interface{}{"folder": v.Folder, "locked": true}
+		if session := sessions.Find(v.Folder, time.Now()); session != nil {
+			state["locked"], state["expires"] = false, session.Expires
+		}
+
+		states = append(states, state)
+	}
+
+	printData(states, len(states), func() { pkg.PrintVaults(settings.Vaults, sessions) })
+}
+
+// runVaultCreateCommand makes the folder a vault (creates it if it doesn't exist),
+// seals its existing notes, and keeps it unlocked for [vaultTTL].
+func runVaultCreateCommand(cmd *cobra.Command, args []string) {
+	determineService()
+
+	ttl, err := pkg.ParseAge(vaultTTL)
+	if err != nil {
+		alert(pkg.ErrorL, err.Error())
+		return
+	}
+
+	passphrase, err := askPassphrase(VaultPassphraseEnv, true)
+	if err != nil {
+		alert(pkg.ErrorL, err.Error())
+		return
+	}
+
+	loading.Start()
+
+	settings, vault, err := services.CreateVault(localService.StateConfig(), args[0], passphrase)
+	if err == nil {
+		err = localService.WriteSettings(settings)
+	}
+
+	if err == nil {
+		// Reload state of local service, from updated settings.
+		err = localService.Init(nil)
+	}
+
+	var key []byte
+	if err == nil {
+		key, err = services.VaultKey(vault, passphrase)
+	}
+
+	if err == nil {
+		err = services.UnlockVault(services.VaultSessionsPath(settings), vault, key, ttl)
+	}
+
+	loading.Stop()
+
+	if err != nil {
+		alert(pkg.ErrorL, err.Error())
+		return
+	}
+
+	// Re-determine the service, to wrap it by vaults.
+	determineService()
+
+	folder := models.Folder{Title: vault.Folder}
+	if exists, _ := service.IsNodeExists(folder.ToNode()); !exists {
+		if _, err := service.Mkdir(folder); err != nil {
+			alert(pkg.ErrorL, err.Error())
+			return
+		}
+	}
+
+	loading.Start()
+	sealed, errs := service.(*services.VaultService).SealFolder(vault)
+	loading.Stop()
+
+	addNodes(sealed...)
+	printErrors("seal", errs)
+	alert(pkg.SuccessL, fmt.Sprintf("Created vault %v, and sealed %v notes", vault.Folder, len(sealed)))
+}
+
+// runVaultUnlockCommand verifies the passphrase of vault, and caches its key for [vaultTTL].
+func runVaultUnlockCommand(cmd *cobra.Command, args []string) {
+	vault, err := findVault(args[0])
+	if err != nil {
+		alert(pkg.ErrorL, err.Error())
+		return
+	}
+
+	ttl, err := pkg.ParseAge(vaultTTL)
+	if err != nil {
+		alert(pkg.ErrorL, err.Error())
+		return
+	}
+
+	passphrase, err := askPassphrase(VaultPassphraseEnv, false)
+	if err != nil {
+		alert(pkg.ErrorL, err.Error())
+		return
+	}
+
+	loading.Start()
+
+	key, err := services.VaultKey(*vault, passphrase)
+	if err == nil {
+		err = services.UnlockVault(services.VaultSessionsPath(localService.StateConfig()), *vault, key, ttl)
+	}
+
+	loading.Stop()
+
+	if err != nil {
+		alert(pkg.ErrorL, err.Error())
+		return
+	}
+
+	alert(pkg.SuccessL, fmt.Sprintf("Unlocked %v for %v", vault.Folder, ttl.Round(time.Second)))
+}
+
+// runVaultLockCommand removes cached key of provided vault, or keys of all vaults.
+func runVaultLockCommand(cmd *cobra.Command, args []string) {
+	var folder string
+	if len(args) > 0 {
+		vault, err := findVault(args[0])
+		if err != nil {
+			alert(pkg.ErrorL, err.Error())
+			return
+		}
+
+		folder = vault.Folder
+	}
+
+	if err := services.LockVault(services.VaultSessionsPath(localService.StateConfig()), folder); err != nil {
+		alert(pkg.ErrorL, err.Error())
+		return
+	}
+
+	if len(folder) == 0 {
+		alert(pkg.SuccessL, "Locked all vaults")
+		return
+	}
+
+	alert(pkg.SuccessL, fmt.Sprintf("Locked %v", folder))
+}
@@ -7,7 +7,6 @@
 package commands
 
 import (
-	"github.com/AlecAivazis/survey/v2"
 	"github.com/insolite-dev/notya/assets"
 	"github.com/insolite-dev/notya/lib/models"
 	"github.com/insolite-dev/notya/pkg"
@@ -39,9 +38,9 @@ func runViewCommand(cmd *cobra.Command, args []string) {
 		loading.Stop()
 
 		if err != nil {
-			pkg.Alert(pkg.ErrorL, err.Error())
+			alert(pkg.ErrorL, err.Error())
 		} else {
-			pkg.PrintNote(*note)
+			printNote(*note)
 		}
 
 		return
@@ -51,20 +50,20 @@ func runViewCommand(cmd *cobra.Command, args []string) {
 	nodes, noteNames, err := service.GetAll("", "file", models.NotyaIgnoreFiles)
 	loading.Stop()
 	if err != nil {
-		pkg.Alert(pkg.ErrorL, err.Error())
+		alert(pkg.ErrorL, err.Error())
 		return
 	}
 
 	// Ask for note selection.
 	var selected string
-	survey.AskOne(
+	ask(
 		assets.ChooseNodePrompt("note", "view", noteNames),
 		&selected,
 	)
 
 	for _, n := range nodes {
 		if n.Title == selected {
-			pkg.PrintNote(n.ToNote())
+			printNote(n.ToNote())
 		}
 	}
 }
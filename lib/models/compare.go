@@ -0,0 +1,58 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package models
+
+// Side is a custom string wrapper to represent where a compared node exists.
+type Side string
+
+var (
+	CURRENT Side = "current"
+	AGAINST Side = "against"
+	BOTH    Side = "both"
+)
+
+// NodeDiff is the difference of one node, between two services.
+type NodeDiff struct {
+	Title string   `json:"title"`
+	Type  NodeType `json:"type"`
+	Side  Side     `json:"side"`
+
+	// Diff is the unified diff of note's body, from [Against] service to [Current] service.
+	// Available only for notes that exist on both sides.
+	Diff string `json:"diff,omitempty"`
+}
+
+// Comparison is the list of differences between nodes of [Current] and [Against] services.
+//
+//	Example:
+//
+// ╭───────────────────────────────────────────╮
+// │ Current: LOCAL | Against: FIREBASE        │
+// │   + ideas/todo.md   (only at LOCAL)       │
+// │   - old.md          (only at FIREBASE)    │
+// │   ~ notes.md                              │
+// │     @@ -1 +1 @@                           │
+// │     -first                                │
+// │     +second                               │
+// ╰───────────────────────────────────────────╯
+type Comparison struct {
+	Current string     `json:"current"`
+	Against string     `json:"against"`
+	Nodes   []NodeDiff `json:"nodes"`
+}
+
+// Count returns the amount of differences at provided side.
+func (c *Comparison) Count(side Side) int {
+	count := 0
+	for _, n := range c.Nodes {
+		if n.Side == side {
+			count++
+		}
+	}
+
+	return count
+}
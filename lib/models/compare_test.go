@@ -0,0 +1,36 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package models_test
+
+import (
+	"testing"
+
+	"github.com/insolite-dev/notya/lib/models"
+)
+
+func TestComparisonCount(t *testing.T) {
+	c := models.Comparison{Nodes: []models.NodeDiff{
+		{Title: "a.md", Side: models.CURRENT},
+		{Title: "b.md", Side: models.CURRENT},
+		{Title: "c.md", Side: models.BOTH},
+	}}
+
+	tests := []struct {
+		side     models.Side
+		expected int
+	}{
+		{side: models.CURRENT, expected: 2},
+		{side: models.AGAINST, expected: 0},
+		{side: models.BOTH, expected: 1},
+	}
+
+	for _, td := range tests {
+		if got := c.Count(td.side); got != td.expected {
+			t.Errorf("Count sum was different for %v: Want: %v | Got: %v", td.side, td.expected, got)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package models
+
+import (
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FrontMatterDelimiter is the line, that opens and closes front matter block.
+const FrontMatterDelimiter = "---"
+
+// FrontMatter is the structured metadata of note, which is kept
+// as a YAML block at the very beginning of note's body.
+//
+//	Example:
+//
+// ╭──────────────────────────────────╮
+// │ ---                              │
+// │ title: Weekly plan               │
+// │ tags: [work, planning]           │
+// │ aliases: [plan]                  │
+// │ created: 2023-01-02              │
+// │ priority: high                   │
+// │ ---                              │
+// │ ... Note content here ...        │
+// ╰──────────────────────────────────╯
+//
+// Front matter is only read from body, and never re-written.
+// So, bodies always round-trip exactly as they were written.
+type FrontMatter struct {
+	Title   string     `yaml:"title,omitempty" json:"title,omitempty"`
+	Tags    StringList `yaml:"tags,omitempty" json:"tags,omitempty"`
+	Aliases StringList `yaml:"aliases,omitempty" json:"aliases,omitempty"`
+	Created string     `yaml:"created,omitempty" json:"created,omitempty"`
+
+	// Custom are the keys, that aren't known by notya.
+	Custom map[string]interface{} `yaml:",inline" json:"custom,omitempty"`
+}
+
+// StringList is a list of strings, which also accepts a single string in YAML.
+//
+//	tags: work          ─▶ [work]
+//	tags: [work, home]  ─▶ [work home]
+type StringList []string
+
+// UnmarshalYAML decodes a scalar or sequence YAML node to [StringList].
+func (l *StringList) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*l = StringList{value.Value}
+		return nil
+	}
+
+	var list []string
+	if err := value.Decode(&list); err != nil {
+		return err
+	}
+
+	*l = list
+	return nil
+}
+
+// ParseFrontMatter splits [body] to its front matter and content.
+// If body doesn't start with a front matter block, nil is returned with the whole body.
+func ParseFrontMatter(body string) (*FrontMatter, string, error) {
+	lines := strings.SplitAfter(body, "\n")
+	if len(lines) == 0 || strings.TrimRight(lines[0], "\r\n") != FrontMatterDelimiter {
+		return nil, body, nil
+	}
+
+	for i := 1; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], "\r\n")
+		if line != FrontMatterDelimiter && line != "..." {
+			continue
+		}
+
+		fm := FrontMatter{}
+		if err := yaml.Unmarshal([]byte(strings.Join(lines[1:i], "")), &fm); err != nil {
+			return nil, body, err
+		}
+
+		return &fm, strings.Join(lines[i+1:], ""), nil
+	}
+
+	// Block was never closed, so it's not a front matter.
+	return nil, body, nil
+}
+
+// FrontMatter parses the front matter of note's body.
+// Notes without (or with invalid) front matter, would have nil front matter.
+func (n *Note) FrontMatter() *FrontMatter {
+	fm, _, err := ParseFrontMatter(n.Body)
+	if err != nil {
+		return nil
+	}
+
+	return fm
+}
+
+// HasTag checks if front matter includes provided [tag].
+// A leading "#" of tag is ignored, so both "#work" and "work" match.
+func (f *FrontMatter) HasTag(tag string) bool {
+	tag = strings.TrimPrefix(tag, "#")
+	for _, t := range f.Tags {
+		if strings.TrimPrefix(t, "#") == tag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,84 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package models_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/insolite-dev/notya/lib/models"
+)
+
+func TestParseFrontMatter(t *testing.T) {
+	tests := []struct {
+		testName string
+		body     string
+		expected string
+		content  string
+		err      bool
+	}{
+		{
+			testName: "should parse known and custom keys",
+			body:     "---\ntitle: Plan\ntags: [work, \"#home\"]\naliases: plan\ncreated: 2023-01-02\npriority: high\n---\ncontent\n",
+			expected: "Plan [work #home] [plan] 2023-01-02 map[priority:high]",
+			content:  "content\n",
+		},
+		{
+			testName: "should accept windows line endings and dots as closing delimiter",
+			body:     "---\r\ntags: work\r\n...\r\ncontent",
+			expected: " [work] []  map[]",
+			content:  "content",
+		},
+		{
+			testName: "should skip bodies without front matter",
+			body:     "content\n---\ntags: work\n---\n",
+			expected: "<nil>",
+			content:  "content\n---\ntags: work\n---\n",
+		},
+		{
+			testName: "should skip unclosed blocks",
+			body:     "---\ntags: work\n",
+			expected: "<nil>",
+			content:  "---\ntags: work\n",
+		},
+		{
+			testName: "should fail on invalid yaml",
+			body:     "---\ntags: [work\n---\n",
+			expected: "<nil>",
+			content:  "---\ntags: [work\n---\n",
+			err:      true,
+		},
+	}
+
+	for _, td := range tests {
+		t.Run(td.testName, func(t *testing.T) {
+			fm, content, err := models.ParseFrontMatter(td.body)
+			if (err != nil) != td.err {
+				t.Fatalf("ParseFrontMatter error was different: Got: %v", err)
+			}
+
+			got := "<nil>"
+			if fm != nil {
+				got = fmt.Sprint(fm.Title, " ", fm.Tags, " ", fm.Aliases, " ", fm.Created, " ", fm.Custom)
+			}
+
+			if got != td.expected || content != td.content {
+				t.Errorf("ParseFrontMatter sum was different: Want: %v, %q | Got: %v, %q", td.expected, td.content, got, content)
+			}
+		})
+	}
+}
+
+func TestHasTag(t *testing.T) {
+	fm := models.FrontMatter{Tags: models.StringList{"work", "#home"}}
+
+	for tag, expected := range map[string]bool{"work": true, "#work": true, "home": true, "play": false} {
+		if got := fm.HasTag(tag); got != expected {
+			t.Errorf("HasTag(%v) sum was different: Want: %v | Got: %v", tag, expected, got)
+		}
+	}
+}
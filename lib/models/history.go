@@ -0,0 +1,112 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package models
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// HistoryName is the name of hidden folder, that keeps earlier versions of local notes.
+// Remote services keep them at "versions" sub collection of each note.
+const HistoryName = ".history"
+
+// Version is an earlier body of note, which was replaced by an edit.
+//
+//	Example:
+//
+// ╭──────────────────────────────────────────╮
+// │ Rev: 3                                   │
+// │ Title: ideas/todo.md                     │
+// │ Size: 120                                │
+// │ Updated: 2022-01-02T15:04:05Z            │
+// ╰──────────────────────────────────────────╯
+type Version struct {
+	// Rev is the revision number of version, starting from 1.
+	Rev int `json:"rev"`
+
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	Size  int64  `json:"size"`
+	Hash  string `json:"hash,omitempty"`
+
+	// Updated is the time, that the body was last written at.
+	Updated time.Time `json:"updated"`
+}
+
+// ToJSON converts version to map value.
+func (v *Version) ToJSON() map[string]interface{} {
+	b, _ := json.Marshal(&v)
+
+	var m map[string]interface{}
+	_ = json.Unmarshal(b, &m)
+
+	return m
+}
+
+// FromJson converts provided map data to [Version] structure.
+func (v *Version) FromJson(data map[string]interface{}) error {
+	jsonBytes, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(jsonBytes, &v)
+}
+
+// History is the list of note's [Version]s.
+type History []Version
+
+// Sort orders versions by their revisions, from the latest to the earliest.
+func (h History) Sort() History {
+	sort.SliceStable(h, func(i, j int) bool { return h[i].Rev > h[j].Rev })
+	return h
+}
+
+// NextRev returns the revision number of next version.
+func (h History) NextRev() int {
+	next := 1
+	for _, v := range h {
+		if v.Rev >= next {
+			next = v.Rev + 1
+		}
+	}
+
+	return next
+}
+
+// Find returns the version of [rev], or nil if there's no such revision.
+// Zero [rev] finds the latest version.
+func (h History) Find(rev int) *Version {
+	var found *Version
+	for i, v := range h {
+		if (rev == 0 && (found == nil || v.Rev > found.Rev)) || v.Rev == rev {
+			found = &h[i]
+		}
+	}
+
+	return found
+}
+
+// ToString converts history to a formatted JSON string.
+func (h History) ToString() string {
+	jsonBytes, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return ""
+	}
+
+	return string(jsonBytes)
+}
+
+// DecodeHistory converts string(list) value to [History].
+func DecodeHistory(value string) History {
+	h := History{}
+	_ = json.Unmarshal([]byte(value), &h)
+
+	return h
+}
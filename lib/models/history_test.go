@@ -0,0 +1,66 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/insolite-dev/notya/lib/models"
+)
+
+func TestHistory(t *testing.T) {
+	history := models.History{{Rev: 1, Body: "a"}, {Rev: 3, Body: "c"}, {Rev: 2, Body: "b"}}
+
+	if got := history.NextRev(); got != 4 {
+		t.Errorf("NextRev sum was different: Want: %v | Got: %v", 4, got)
+	}
+
+	if got := (models.History{}).NextRev(); got != 1 {
+		t.Errorf("NextRev sum was different: Want: %v | Got: %v", 1, got)
+	}
+
+	tests := []struct {
+		rev      int
+		expected string
+	}{
+		{rev: 0, expected: "c"},
+		{rev: 2, expected: "b"},
+		{rev: 5, expected: ""},
+	}
+
+	for _, td := range tests {
+		got := ""
+		if v := history.Find(td.rev); v != nil {
+			got = v.Body
+		}
+
+		if got != td.expected {
+			t.Errorf("Find sum was different for r%v: Want: %v | Got: %v", td.rev, td.expected, got)
+		}
+	}
+
+	sorted := history.Sort()
+	if sorted[0].Rev != 3 || sorted[2].Rev != 1 {
+		t.Errorf("Sort sum was different, Got: %v", sorted)
+	}
+}
+
+func TestDecodeHistory(t *testing.T) {
+	updated := time.Unix(60, 0).UTC()
+	history := models.History{{Rev: 1, Title: "todo.md", Body: "todo", Size: 4, Updated: updated}}
+
+	got := models.DecodeHistory(history.ToString())
+	if len(got) != 1 || got[0].Body != "todo" || !got[0].Updated.Equal(updated) {
+		t.Errorf("DecodeHistory sum was different: Want: %v | Got: %v", history, got)
+	}
+
+	var version models.Version
+	if err := version.FromJson(history[0].ToJSON()); err != nil || version.Title != "todo.md" || version.Rev != 1 {
+		t.Errorf("Version JSON sum was different: Want: %v | Got: %v (%v)", history[0], version, err)
+	}
+}
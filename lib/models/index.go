@@ -0,0 +1,245 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package models
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// IndexName is the name of file that keeps the search index of local notes.
+const IndexName = ".index.json"
+
+// BM25 ranking parameters.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// IndexDoc is the indexed state of one note.
+type IndexDoc struct {
+	// Updated is the modification time of note, at the moment of indexing.
+	// Used to catch notes, that were edited outside of notya.
+	Updated time.Time `json:"updated"`
+
+	// Length is the amount of terms at note.
+	Length int `json:"length"`
+
+	// Terms are the unique terms of note, used to clean postings on removal.
+	Terms []string `json:"terms"`
+}
+
+// Index is an inverted index of notes, which maps terms to the notes including them.
+//
+//	Example:
+//
+// ╭──────────────────────────────────────────────╮
+// │ Postings:                                    │
+// │    milk ─▶ { todo.md: 2, ideas/shop.md: 1 }  │
+// │ Docs:                                        │
+// │    todo.md ─▶ { updated, length: 14, ... }   │
+// ╰──────────────────────────────────────────────╯
+type Index struct {
+	Docs     map[string]IndexDoc       `json:"docs"`
+	Postings map[string]map[string]int `json:"postings"`
+}
+
+// IndexHit is a single ranked result of index search.
+type IndexHit struct {
+	Title string  `json:"title"`
+	Score float64 `json:"score"`
+}
+
+// NewIndex creates an empty index.
+func NewIndex() *Index {
+	return &Index{Docs: map[string]IndexDoc{}, Postings: map[string]map[string]int{}}
+}
+
+// Tokenize splits [text] to lower-cased terms, by non letter and non digit characters.
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Add indexes the note of [title] with its [body].
+// Title is indexed as a part of body, so notes can be found by their titles too.
+func (i *Index) Add(title, body string, updated time.Time) {
+	freqs := map[string]int{}
+	for _, t := range Tokenize(title + "\n" + body) {
+		freqs[t]++
+	}
+
+	i.put(title, freqs, updated)
+}
+
+// Remove removes the note of [title], or all notes under it if it's a folder.
+func (i *Index) Remove(title string) {
+	for _, t := range i.titlesOf(title) {
+		i.remove(t)
+	}
+}
+
+// Rename moves the note of [current] title, or all notes under it if it's a folder, to [new] title.
+// Titles are indexed as a part of bodies, so terms of old title are replaced by terms of new title.
+// Which equals re-adding the note, without reading its body again.
+func (i *Index) Rename(current, new string) {
+	folder := strings.TrimSuffix(current, "/") + "/"
+
+	for _, t := range i.titlesOf(current) {
+		renamed := new
+		if strings.HasPrefix(t, folder) {
+			renamed = strings.TrimSuffix(new, "/") + "/" + strings.TrimPrefix(t, folder)
+		}
+
+		doc := i.Docs[t]
+
+		freqs := map[string]int{}
+		for _, term := range doc.Terms {
+			freqs[term] = i.Postings[term][t]
+		}
+
+		for _, term := range Tokenize(t) {
+			freqs[term]--
+		}
+
+		for _, term := range Tokenize(renamed) {
+			freqs[term]++
+		}
+
+		i.remove(t)
+		i.put(renamed, freqs, doc.Updated)
+	}
+}
+
+// Search ranks indexed notes by [query] via BM25.
+// Hits are sorted by their scores, from highest to lowest.
+func (i *Index) Search(query string) []IndexHit {
+	if len(i.Docs) == 0 {
+		return []IndexHit{}
+	}
+
+	total := 0
+	for _, d := range i.Docs {
+		total += d.Length
+	}
+
+	n := float64(len(i.Docs))
+	avgLength := float64(total) / n
+
+	scores := map[string]float64{}
+	for _, term := range Tokenize(query) {
+		postings := i.Postings[term]
+		df := float64(len(postings))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+
+		for title, f := range postings {
+			tf := float64(f)
+			norm := 1 - bm25B + bm25B*float64(i.Docs[title].Length)/avgLength
+			scores[title] += idf * tf * (bm25K1 + 1) / (tf + bm25K1*norm)
+		}
+	}
+
+	hits := []IndexHit{}
+	for title, score := range scores {
+		hits = append(hits, IndexHit{Title: title, Score: score})
+	}
+
+	sort.Slice(hits, func(a, b int) bool {
+		if hits[a].Score == hits[b].Score {
+			return hits[a].Title < hits[b].Title
+		}
+
+		return hits[a].Score > hits[b].Score
+	})
+
+	return hits
+}
+
+// ToString converts index to a JSON string.
+func (i *Index) ToString() string {
+	jsonBytes, err := json.Marshal(i)
+	if err != nil {
+		return ""
+	}
+
+	return string(jsonBytes)
+}
+
+// DecodeIndex converts string(map) value to [Index].
+func DecodeIndex(value string) *Index {
+	i := NewIndex()
+	_ = json.Unmarshal([]byte(value), i)
+
+	if i.Docs == nil {
+		i.Docs = map[string]IndexDoc{}
+	}
+
+	if i.Postings == nil {
+		i.Postings = map[string]map[string]int{}
+	}
+
+	return i
+}
+
+// titlesOf collects indexed titles, that are [title] itself or under it.
+func (i *Index) titlesOf(title string) []string {
+	folder := strings.TrimSuffix(title, "/") + "/"
+
+	titles := []string{}
+	for t := range i.Docs {
+		if t == title || strings.HasPrefix(t, folder) {
+			titles = append(titles, t)
+		}
+	}
+
+	return titles
+}
+
+// put indexes the note of [title] by frequencies of its terms.
+func (i *Index) put(title string, freqs map[string]int, updated time.Time) {
+	i.remove(title)
+
+	doc := IndexDoc{Updated: updated, Terms: []string{}}
+	for t, f := range freqs {
+		if f <= 0 {
+			continue
+		}
+
+		if i.Postings[t] == nil {
+			i.Postings[t] = map[string]int{}
+		}
+
+		i.Postings[t][title] = f
+		doc.Length += f
+		doc.Terms = append(doc.Terms, t)
+	}
+
+	sort.Strings(doc.Terms)
+	i.Docs[title] = doc
+}
+
+// remove removes exactly the note of [title] from index.
+func (i *Index) remove(title string) {
+	doc, ok := i.Docs[title]
+	if !ok {
+		return
+	}
+
+	for _, t := range doc.Terms {
+		delete(i.Postings[t], title)
+		if len(i.Postings[t]) == 0 {
+			delete(i.Postings, t)
+		}
+	}
+
+	delete(i.Docs, title)
+}
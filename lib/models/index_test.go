@@ -0,0 +1,87 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package models_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/insolite-dev/notya/lib/models"
+)
+
+// hitTitles collects titles of index hits.
+func hitTitles(hits []models.IndexHit) string {
+	titles := []string{}
+	for _, h := range hits {
+		titles = append(titles, h.Title)
+	}
+
+	return fmt.Sprint(titles)
+}
+
+func TestTokenize(t *testing.T) {
+	got := fmt.Sprint(models.Tokenize("Buy MILK, read-book!\n2 times"))
+	expected := "[buy milk read book 2 times]"
+
+	if got != expected {
+		t.Errorf("Tokenize sum was different: Want: %v | Got: %v", expected, got)
+	}
+}
+
+func TestIndexSearch(t *testing.T) {
+	index := models.NewIndex()
+	now := time.Now()
+
+	index.Add("todo.md", "milk milk milk and bread", now)
+	index.Add("ideas/shop.md", "milk", now)
+	index.Add("ideas/book.md", "a long note about reading books and bread and other things", now)
+
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{query: "milk", expected: "[todo.md ideas/shop.md]"},
+		{query: "bread", expected: "[todo.md ideas/book.md]"},
+		{query: "shop", expected: "[ideas/shop.md]"},
+		{query: "coffee", expected: "[]"},
+	}
+
+	for _, td := range tests {
+		if got := hitTitles(index.Search(td.query)); got != td.expected {
+			t.Errorf("Search(%v) sum was different: Want: %v | Got: %v", td.query, td.expected, got)
+		}
+	}
+
+	// Renaming folder should move each note under it.
+	index.Rename("ideas", "archive")
+	if got := hitTitles(index.Search("milk")); got != "[todo.md archive/shop.md]" {
+		t.Errorf("Renamed index sum was different: Got: %v", got)
+	}
+
+	// Terms of old titles shouldn't match anymore, and terms of new titles should.
+	if ideas, archive := hitTitles(index.Search("ideas")), hitTitles(index.Search("archive")); ideas != "[]" || archive != "[archive/shop.md archive/book.md]" {
+		t.Errorf("Renamed titles weren't re-tokenized: ideas: %v | archive: %v", ideas, archive)
+	}
+
+	expected := models.NewIndex()
+	expected.Add("archive/shop.md", "milk", now)
+	if got, want := index.Docs["archive/shop.md"], expected.Docs["archive/shop.md"]; got.Length != want.Length || fmt.Sprint(got.Terms) != fmt.Sprint(want.Terms) {
+		t.Errorf("Renamed note should be indexed as it's re-added: Want: %v | Got: %v", want, got)
+	}
+
+	// Removing folder should remove each note under it.
+	index.Remove("archive/")
+	if len(index.Docs) != 1 || len(index.Postings["reading"]) != 0 {
+		t.Errorf("Removed index sum was different: Docs: %v | Postings: %v", index.Docs, index.Postings)
+	}
+
+	decoded := models.DecodeIndex(index.ToString())
+	if got := hitTitles(decoded.Search("milk")); got != "[todo.md]" {
+		t.Errorf("Decoded index sum was different: Got: %v", got)
+	}
+}
@@ -0,0 +1,89 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultJournalPattern is the default pattern of journal note titles (see [JournalTitle]).
+//
+//	2022-01-02 ─▶ journal/2022/01/2022-01-02.md
+const DefaultJournalPattern = "journal/{yyyy}/{mm}/{yyyy}-{mm}-{dd}.md"
+
+// JournalDateLayout is the layout of dates, that journal commands take.
+const JournalDateLayout = "2006-01-02"
+
+// JournalTitle generates the title of journal note of [date], by journal pattern of settings.
+// Placeholders of pattern are replaced by parts of [date], and the rest is kept as it is.
+//
+//	{yyyy} ─▶ 2022    {yy} ─▶ 22
+//	{mm}   ─▶ 01      {dd} ─▶ 02
+//	{ww}   ─▶ 52 (ISO week)
+func (s *Settings) JournalTitle(date time.Time) string {
+	pattern := s.JournalPattern
+	if len(pattern) == 0 {
+		pattern = DefaultJournalPattern
+	}
+
+	_, week := date.ISOWeek()
+
+	return strings.NewReplacer(
+		"{yyyy}", fmt.Sprintf("%04d", date.Year()),
+		"{yy}", fmt.Sprintf("%02d", date.Year()%100),
+		"{mm}", fmt.Sprintf("%02d", int(date.Month())),
+		"{dd}", fmt.Sprintf("%02d", date.Day()),
+		"{ww}", fmt.Sprintf("%02d", week),
+	).Replace(pattern)
+}
+
+// ParseJournalDate parses [date] by [JournalDateLayout], at location of [now].
+// Besides dates, it accepts "today" and "yesterday", relative to [now].
+func ParseJournalDate(date string, now time.Time) (time.Time, error) {
+	switch strings.ToLower(date) {
+	case "", "today":
+		return now, nil
+	case "yesterday":
+		return now.AddDate(0, 0, -1), nil
+	}
+
+	return time.ParseInLocation(JournalDateLayout, date, now.Location())
+}
+
+// JournalWeek returns all days of the week (from monday to sunday) of [date].
+func JournalWeek(date time.Time) []time.Time {
+	// Sunday is the last day of week, instead of the first one.
+	offset := (int(date.Weekday()) + 6) % 7
+	monday := date.AddDate(0, 0, -offset)
+
+	days := []time.Time{}
+	for i := 0; i < 7; i++ {
+		days = append(days, monday.AddDate(0, 0, i))
+	}
+
+	return days
+}
+
+// ConcatJournal concatenates bodies of journal [notes], under headings of their titles.
+//
+//	# journal/2022/01/2022-01-03.md
+//
+//	<body of monday>
+//
+//	# journal/2022/01/2022-01-04.md
+//
+//	<body of tuesday>
+func ConcatJournal(notes []Note) string {
+	entries := []string{}
+	for _, n := range notes {
+		entries = append(entries, fmt.Sprintf("# %v\n\n%v", n.Title, strings.TrimSpace(n.Body)))
+	}
+
+	return strings.Join(entries, "\n\n")
+}
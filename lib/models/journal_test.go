@@ -0,0 +1,100 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/insolite-dev/notya/lib/models"
+)
+
+func TestJournalTitle(t *testing.T) {
+	date := time.Date(2022, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		testName string
+		pattern  string
+		expected string
+	}{
+		{"should use default pattern", "", "journal/2022/01/2022-01-02.md"},
+		{"should use pattern of settings", "days/{yyyy}-{mm}-{dd}.txt", "days/2022-01-02.txt"},
+		{"should keep digits of pattern", "2006-notes/{yy}/week-{ww}/{dd}.md", "2006-notes/22/week-52/02.md"},
+	}
+
+	for _, td := range tests {
+		t.Run(td.testName, func(t *testing.T) {
+			settings := models.Settings{JournalPattern: td.pattern}
+			if got := settings.JournalTitle(date); got != td.expected {
+				t.Errorf("JournalTitle sum was different: Want: %v | Got: %v", td.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseJournalDate(t *testing.T) {
+	now := time.Date(2022, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		date     string
+		expected string
+		err      bool
+	}{
+		{date: "", expected: "2022-01-02"},
+		{date: "today", expected: "2022-01-02"},
+		{date: "Yesterday", expected: "2022-01-01"},
+		{date: "2021-12-25", expected: "2021-12-25"},
+		{date: "25.12.2021", err: true},
+	}
+
+	for _, td := range tests {
+		got, err := models.ParseJournalDate(td.date, now)
+		if (err != nil) != td.err {
+			t.Errorf("ParseJournalDate error was different for %q: Want error: %v | Got: %v", td.date, td.err, err)
+			continue
+		}
+
+		if !td.err && got.Format(models.JournalDateLayout) != td.expected {
+			t.Errorf("ParseJournalDate sum was different for %q: Want: %v | Got: %v", td.date, td.expected, got)
+		}
+	}
+}
+
+func TestJournalWeek(t *testing.T) {
+	tests := []struct {
+		date           time.Time
+		monday, sunday string
+	}{
+		{time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC), "2021-12-27", "2022-01-02"}, // sunday
+		{time.Date(2022, 1, 3, 0, 0, 0, 0, time.UTC), "2022-01-03", "2022-01-09"}, // monday
+		{time.Date(2022, 1, 5, 0, 0, 0, 0, time.UTC), "2022-01-03", "2022-01-09"}, // wednesday
+	}
+
+	for _, td := range tests {
+		week := models.JournalWeek(td.date)
+		if len(week) != 7 {
+			t.Fatalf("JournalWeek should return 7 days, Got: %v", week)
+		}
+
+		monday, sunday := week[0].Format(models.JournalDateLayout), week[6].Format(models.JournalDateLayout)
+		if monday != td.monday || sunday != td.sunday {
+			t.Errorf("JournalWeek sum was different: Want: %v-%v | Got: %v-%v", td.monday, td.sunday, monday, sunday)
+		}
+	}
+}
+
+func TestConcatJournal(t *testing.T) {
+	notes := []models.Note{
+		{Title: "journal/2022-01-03.md", Body: "monday\n"},
+		{Title: "journal/2022-01-04.md", Body: "tuesday"},
+	}
+
+	expected := "# journal/2022-01-03.md\n\nmonday\n\n# journal/2022-01-04.md\n\ntuesday"
+	if got := models.ConcatJournal(notes); got != expected {
+		t.Errorf("ConcatJournal sum was different: Want: %q | Got: %q", expected, got)
+	}
+}
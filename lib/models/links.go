@@ -0,0 +1,164 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package models
+
+import (
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// WikiLinkRegex matches wiki-style links, like: [[note]], [[note|alias]] or [[note#heading]].
+var WikiLinkRegex = regexp.MustCompile(`\[\[([^\[\]\n]+?)\]\]`)
+
+// Link is a wiki-style link from one note to another.
+type Link struct {
+	// From is the title of note, that includes the link.
+	From string `json:"from"`
+
+	// Target is the linked title, as it's written at note.
+	Target string `json:"target"`
+
+	// Resolved is the title of linked note, or empty if it couldn't be resolved.
+	Resolved string `json:"resolved,omitempty"`
+
+	// Line is the number of line that includes the link, starting from 1.
+	Line int `json:"line"`
+}
+
+// ParseLinks collects all wiki-style links of [body].
+// Aliases and headings of links are dropped: [[note#heading|alias]] ─▶ note
+func ParseLinks(from, body string) []Link {
+	links := []Link{}
+
+	for i, line := range strings.Split(body, "\n") {
+		for _, m := range WikiLinkRegex.FindAllStringSubmatch(line, -1) {
+			target := LinkTarget(m[1])
+			if len(target) > 0 {
+				links = append(links, Link{From: from, Target: target, Line: i + 1})
+			}
+		}
+	}
+
+	return links
+}
+
+// LinkTarget drops alias and heading parts of link's inner text.
+func LinkTarget(inner string) string {
+	if i := strings.IndexAny(inner, "|#"); i >= 0 {
+		inner = inner[:i]
+	}
+
+	return strings.TrimSpace(inner)
+}
+
+// LinkGraph is the graph of wiki-style links between notes.
+type LinkGraph struct {
+	// Outgoing maps note titles to links they include.
+	Outgoing map[string][]Link `json:"outgoing"`
+
+	// Incoming maps note titles to links, that point to them.
+	Incoming map[string][]Link `json:"incoming"`
+
+	// Unresolved are those links, which's target couldn't be found between notes.
+	Unresolved []Link `json:"unresolved"`
+}
+
+// BuildLinkGraph parses links of each note at [nodes], and resolves them to titles of notes.
+//
+// A link target is resolved by, in order:
+//   - exact title: [[ideas/todo.md]]
+//   - title without extension: [[ideas/todo]]
+//   - file name, with or without extension: [[todo]]
+//   - aliases of front matter.
+func BuildLinkGraph(nodes []Node) *LinkGraph {
+	graph := &LinkGraph{Outgoing: map[string][]Link{}, Incoming: map[string][]Link{}, Unresolved: []Link{}}
+	resolver := NewLinkResolver(nodes)
+
+	for _, n := range nodes {
+		if n.IsFolder() {
+			continue
+		}
+
+		for _, link := range ParseLinks(n.Title, n.Body) {
+			link.Resolved = resolver.Resolve(link.Target)
+
+			graph.Outgoing[n.Title] = append(graph.Outgoing[n.Title], link)
+			if len(link.Resolved) > 0 {
+				graph.Incoming[link.Resolved] = append(graph.Incoming[link.Resolved], link)
+			} else {
+				graph.Unresolved = append(graph.Unresolved, link)
+			}
+		}
+	}
+
+	return graph
+}
+
+// LinkResolver resolves link targets to titles of notes.
+type LinkResolver struct {
+	keys map[string]string
+}
+
+// NewLinkResolver generates resolver keys for each note of [nodes].
+// Keys of more exact matches overwrite the less exact ones, so
+// [[todo]] prefers "todo" over "ideas/todo.md".
+func NewLinkResolver(nodes []Node) *LinkResolver {
+	r := &LinkResolver{keys: map[string]string{}}
+
+	titles := []string{}
+	aliases := map[string][]string{}
+	for _, n := range nodes {
+		if n.IsFolder() {
+			continue
+		}
+
+		titles = append(titles, n.Title)
+
+		note := n.ToNote()
+		if fm := note.FrontMatter(); fm != nil {
+			aliases[n.Title] = fm.Aliases
+		}
+	}
+
+	// Deeper titles come first, so shallower ones win on same keys.
+	sort.Slice(titles, func(i, j int) bool {
+		if len(titles[i]) == len(titles[j]) {
+			return titles[i] > titles[j]
+		}
+
+		return len(titles[i]) > len(titles[j])
+	})
+
+	set := func(key, title string) { r.keys[strings.ToLower(key)] = title }
+
+	for _, t := range titles {
+		for _, a := range aliases[t] {
+			set(a, t)
+		}
+	}
+
+	for _, t := range titles {
+		base := filepath.Base(t)
+		set(strings.TrimSuffix(base, filepath.Ext(base)), t)
+		set(base, t)
+	}
+
+	for _, t := range titles {
+		set(strings.TrimSuffix(t, filepath.Ext(t)), t)
+		set(t, t)
+	}
+
+	return r
+}
+
+// Resolve returns the title of note that [target] points to, or empty string.
+// Targets are matched case-insensitively.
+func (r *LinkResolver) Resolve(target string) string {
+	return r.keys[strings.ToLower(strings.TrimPrefix(target, "/"))]
+}
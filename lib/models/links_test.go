@@ -0,0 +1,69 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package models_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/insolite-dev/notya/lib/models"
+)
+
+func TestParseLinks(t *testing.T) {
+	body := "see [[todo]] and [[ideas/plan.md#goals|the plan]]\n\n[[ ]] [[a]][[b]]"
+	got := fmt.Sprint(models.ParseLinks("x.md", body))
+	expected := "[{x.md todo  1} {x.md ideas/plan.md  1} {x.md a  3} {x.md b  3}]"
+
+	if got != expected {
+		t.Errorf("ParseLinks sum was different: Want: %v | Got: %v", expected, got)
+	}
+}
+
+func TestLinkResolver(t *testing.T) {
+	nodes := []models.Node{
+		{Type: models.FILE, Title: "todo.md"},
+		{Type: models.FILE, Title: "ideas/todo.md"},
+		{Type: models.FILE, Title: "ideas/plan.md", Body: "---\naliases: [roadmap]\n---\n"},
+		{Type: models.FOLDER, Title: "ideas/"},
+	}
+
+	resolver := models.NewLinkResolver(nodes)
+
+	tests := map[string]string{
+		"todo":          "todo.md",
+		"Todo.md":       "todo.md",
+		"ideas/todo":    "ideas/todo.md",
+		"plan":          "ideas/plan.md",
+		"roadmap":       "ideas/plan.md",
+		"/ideas/plan":   "ideas/plan.md",
+		"ideas":         "",
+		"missing title": "",
+	}
+
+	for target, expected := range tests {
+		if got := resolver.Resolve(target); got != expected {
+			t.Errorf("Resolve(%v) sum was different: Want: %v | Got: %v", target, expected, got)
+		}
+	}
+}
+
+func TestBuildLinkGraph(t *testing.T) {
+	nodes := []models.Node{
+		{Type: models.FILE, Title: "a.md", Body: "[[b]] [[missing]]"},
+		{Type: models.FILE, Title: "b.md", Body: "[[a.md]]"},
+	}
+
+	graph := models.BuildLinkGraph(nodes)
+
+	if len(graph.Outgoing["a.md"]) != 2 || len(graph.Incoming["b.md"]) != 1 || len(graph.Incoming["a.md"]) != 1 {
+		t.Errorf("BuildLinkGraph sum was different: Got: %v", graph)
+	}
+
+	if len(graph.Unresolved) != 1 || graph.Unresolved[0].Target != "missing" {
+		t.Errorf("Unresolved links were different: Got: %v", graph.Unresolved)
+	}
+}
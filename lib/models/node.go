@@ -10,6 +10,7 @@ import (
 	"encoding/json"
 	"os"
 	"strings"
+	"time"
 )
 
 var (
@@ -42,6 +43,24 @@ type Node struct {
 	// A field representation of [Note]'s [Body].
 	Body string `json:"body,omitempty"`
 
+	// Created is the creation time of "current" node.
+	Created time.Time `json:"created"`
+
+	// Updated is the last modification time of "current" node.
+	Updated time.Time `json:"updated"`
+
+	// Size is the byte size of node's body.
+	Size int64 `json:"size,omitempty"`
+
+	// Hash is the sha256 hash of node's body.
+	Hash string `json:"hash,omitempty"`
+
+	// Tags are the tags of node's front matter.
+	Tags []string `json:"tags,omitempty"`
+
+	// Locked marks nodes of locked vault folders, which bodies are kept encrypted.
+	Locked bool `json:"locked,omitempty"`
+
 	// Pretty is Title but powered with ascii emojis.
 	// Shouldn't used as a production field.
 	Pretty []string `json:"pretty,omitempty"`
@@ -117,7 +136,19 @@ func (n *Node) ToNote() Note {
 		title = title[:len(title)-1]
 	}
 
-	return Note{Title: title, Path: n.Path, Body: n.Body}
+	return Note{Title: title, Path: n.Path, Body: n.Body, Locked: n.Locked}
+}
+
+// FillTags sets [Tags] of node, from front matter of its body.
+func (n *Node) FillTags() *Node {
+	n.Tags = nil
+
+	note := n.ToNote()
+	if fm := note.FrontMatter(); fm != nil && len(fm.Tags) > 0 {
+		n.Tags = fm.Tags
+	}
+
+	return n
 }
 
 // ToFile converts [Node] object to [Folder].
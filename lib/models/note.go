@@ -8,6 +8,7 @@ package models
 
 import (
 	"encoding/json"
+	"strings"
 )
 
 // Note is the main note model of application.
@@ -23,6 +24,9 @@ type Note struct {
 	Title string            `json:"title"`
 	Path  map[string]string `json:"path"`
 	Body  string            `json:"body"`
+
+	// Locked marks notes of locked vault folders, which bodies are kept encrypted.
+	Locked bool `json:"locked,omitempty"`
 }
 
 // GetPath returns exact path of provided service.
@@ -43,5 +47,36 @@ func (s *Note) ToJSON() map[string]interface{} {
 
 // ToNode converts [Note] model to [Node] model.
 func (n *Note) ToNode() Node {
-	return Node{Type: FILE, Title: n.Title, Path: n.Path, Body: n.Body}
+	return Node{Type: FILE, Title: n.Title, Path: n.Path, Body: n.Body, Locked: n.Locked}
+}
+
+// AppendBody adds [text] to the end of [body], on a new line.
+func AppendBody(body, text string) string {
+	if len(body) > 0 && !strings.HasSuffix(body, "\n") {
+		body += "\n"
+	}
+
+	return body + text
+}
+
+// PrependBody adds [text] to the beginning of [body], on its own line.
+// If body starts with a front matter, text is placed right after it,
+// so front matter stays at the very beginning of note.
+//
+//	---                  ---
+//	tags: [log]          tags: [log]
+//	---           ─▶     ---
+//	earlier              <text>
+//	                     earlier
+func PrependBody(body, text string) string {
+	var front string
+	if fm, content, err := ParseFrontMatter(body); err == nil && fm != nil {
+		front, body = body[:len(body)-len(content)], content
+	}
+
+	if len(body) > 0 && !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+
+	return front + text + body
 }
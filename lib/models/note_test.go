@@ -65,3 +65,47 @@ func TestNoteToNode(t *testing.T) {
 		}
 	}
 }
+
+func TestAppendAndPrependBody(t *testing.T) {
+	tests := []struct {
+		testName string
+		body     string
+		text     string
+		appended string
+		prepend  string
+	}{
+		{
+			testName: "should write text to empty body",
+			body:     "",
+			text:     "first",
+			appended: "first",
+			prepend:  "first",
+		},
+		{
+			testName: "should keep text on its own line",
+			body:     "earlier",
+			text:     "later\n",
+			appended: "earlier\nlater\n",
+			prepend:  "later\nearlier",
+		},
+		{
+			testName: "should keep front matter at the beginning",
+			body:     "---\ntags: [log]\n---\nearlier\n",
+			text:     "later",
+			appended: "---\ntags: [log]\n---\nearlier\nlater",
+			prepend:  "---\ntags: [log]\n---\nlater\nearlier\n",
+		},
+	}
+
+	for _, td := range tests {
+		t.Run(td.testName, func(t *testing.T) {
+			if got := models.AppendBody(td.body, td.text); got != td.appended {
+				t.Errorf("AppendBody sum was different: Want: %q | Got: %q", td.appended, got)
+			}
+
+			if got := models.PrependBody(td.body, td.text); got != td.prepend {
+				t.Errorf("PrependBody sum was different: Want: %q | Got: %q", td.prepend, got)
+			}
+		})
+	}
+}
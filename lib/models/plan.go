@@ -0,0 +1,72 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package models
+
+import "encoding/json"
+
+// Action is a custom string wrapper to represent the action of [Change].
+type Action string
+
+var (
+	CREATE Action = "create"
+	UPDATE Action = "update"
+	DELETE Action = "delete"
+)
+
+// Change is a single planned modification of one node.
+type Change struct {
+	Action Action   `json:"action"`
+	Type   NodeType `json:"type"`
+	Title  string   `json:"title"`
+
+	// Size is the byte size of node's body, that'd be written or deleted.
+	Size int `json:"size"`
+
+	// Node is the source of change, which is used on applying the change.
+	Node Node `json:"-"`
+}
+
+// Plan is a list of changes, that an action(push, fetch or migrate)
+// would make at [To] service, by using [From] service's nodes.
+//
+//	Example:
+//
+// ╭─────────────────────────────────────────╮
+// │ Act: push | From: LOCAL | To: FIREBASE  │
+// │   + create  ideas/        (0 B)         │
+// │   + create  ideas/todo.md (24 B)        │
+// │   ~ update  notes.md      (120 B)       │
+// │   - delete  old.md        (7 B)         │
+// ╰─────────────────────────────────────────╯
+type Plan struct {
+	Act     string   `json:"act"`
+	From    string   `json:"from"`
+	To      string   `json:"to"`
+	Changes []Change `json:"changes"`
+}
+
+// Count returns the amount of changes with provided action.
+func (p *Plan) Count(action Action) int {
+	count := 0
+	for _, c := range p.Changes {
+		if c.Action == action {
+			count++
+		}
+	}
+
+	return count
+}
+
+// ToString converts plan to a formatted JSON string.
+func (p *Plan) ToString() string {
+	jsonBytes, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return ""
+	}
+
+	return string(jsonBytes)
+}
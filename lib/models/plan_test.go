@@ -0,0 +1,53 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package models_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/insolite-dev/notya/lib/models"
+)
+
+func TestPlanCount(t *testing.T) {
+	plan := models.Plan{Changes: []models.Change{
+		{Action: models.CREATE}, {Action: models.CREATE}, {Action: models.DELETE},
+	}}
+
+	tests := []struct {
+		action   models.Action
+		expected int
+	}{
+		{action: models.CREATE, expected: 2},
+		{action: models.UPDATE, expected: 0},
+		{action: models.DELETE, expected: 1},
+	}
+
+	for _, td := range tests {
+		if got := plan.Count(td.action); got != td.expected {
+			t.Errorf("Count sum was different for %v: Want: %v | Got: %v", td.action, td.expected, got)
+		}
+	}
+}
+
+func TestPlanToString(t *testing.T) {
+	plan := models.Plan{
+		Act: "push", From: "LOCAL", To: "FIREBASE",
+		Changes: []models.Change{{Action: models.UPDATE, Type: models.FILE, Title: "a.md", Size: 5, Node: models.Node{Body: "hello"}}},
+	}
+
+	got := plan.ToString()
+	for _, expected := range []string{`"act": "push"`, `"action": "update"`, `"size": 5`} {
+		if !strings.Contains(got, expected) {
+			t.Errorf("ToString should contain %v, Got: %v", expected, got)
+		}
+	}
+
+	if strings.Contains(got, "hello") {
+		t.Errorf("ToString shouldn't contain the body of nodes, Got: %v", got)
+	}
+}
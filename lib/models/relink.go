@@ -0,0 +1,171 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package models
+
+import (
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// MarkdownLinkRegex matches inline markdown links, like: [text](ideas/todo.md#heading).
+var MarkdownLinkRegex = regexp.MustCompile(`(!?\[[^\]]*\]\()([^)\s]+)(\))`)
+
+// Moves maps old titles of renamed nodes to their new titles.
+type Moves struct {
+	// Notes maps old titles of moved notes to new titles.
+	Notes map[string]string `json:"notes"`
+
+	// Folders maps old titles of moved folders to new titles, without trailing slashes.
+	Folders map[string]string `json:"folders"`
+}
+
+// MovesOf works out the moves of renaming [current] title to [new] title,
+// by titles of all notes at [nodes]. If [current] is a folder, each note under it moves too.
+func MovesOf(nodes []Node, current, new string) Moves {
+	moves := Moves{Notes: map[string]string{}, Folders: map[string]string{}}
+
+	current, new = strings.Trim(current, "/"), strings.Trim(new, "/")
+	for _, n := range nodes {
+		title := strings.Trim(n.Title, "/")
+
+		switch {
+		case n.IsFolder() && title == current:
+			moves.Folders[current] = new
+		case strings.HasPrefix(title, current+"/"):
+			moves.Folders[current] = new
+			if !n.IsFolder() {
+				moves.Notes[title] = new + "/" + strings.TrimPrefix(title, current+"/")
+			}
+		case !n.IsFolder() && title == current:
+			moves.Notes[current] = new
+		}
+	}
+
+	return moves
+}
+
+// Title returns the new title of [title], which is the same title if it wasn't moved.
+func (m Moves) Title(title string) string {
+	title = strings.Trim(title, "/")
+	if moved, ok := m.Notes[title]; ok {
+		return moved
+	}
+
+	for old, moved := range m.Folders {
+		if title == old {
+			return moved
+		}
+
+		if strings.HasPrefix(title, old+"/") {
+			return moved + "/" + strings.TrimPrefix(title, old+"/")
+		}
+	}
+
+	return title
+}
+
+// RelinkBody rewrites links of note [title] at [body], that point to moved nodes.
+//
+// Wiki-style links are resolved by [resolver] (which must be built before moving),
+// and keep their style: [[todo]] ─▶ [[done]], [[ideas/todo.md]] ─▶ [[archive/todo.md]].
+// Relative markdown links are rewritten relatively to the new location of note.
+func RelinkBody(title, body string, moves Moves, resolver *LinkResolver) string {
+	body = WikiLinkRegex.ReplaceAllStringFunc(body, func(match string) string {
+		inner := match[2 : len(match)-2]
+		target := LinkTarget(inner)
+
+		relinked := relinkTarget(target, moves, resolver)
+		if relinked == target {
+			return match
+		}
+
+		return "[[" + strings.Replace(inner, target, relinked, 1) + "]]"
+	})
+
+	oldDir, newDir := path.Dir(title), path.Dir(moves.Title(title))
+
+	return MarkdownLinkRegex.ReplaceAllStringFunc(body, func(match string) string {
+		parts := MarkdownLinkRegex.FindStringSubmatch(match)
+		link := parts[2]
+
+		// Skip absolute URLs, and anchors of note itself.
+		if strings.Contains(link, "://") || strings.HasPrefix(link, "mailto:") || strings.HasPrefix(link, "#") {
+			return match
+		}
+
+		target, anchor := link, ""
+		if i := strings.Index(link, "#"); i >= 0 {
+			target, anchor = link[:i], link[i:]
+		}
+
+		decoded := strings.ReplaceAll(target, "%20", " ")
+		old := path.Clean(path.Join(oldDir, decoded))
+		moved := moves.Title(old)
+
+		// Keep the original text, if neither note nor target has moved.
+		if oldDir == newDir && moved == strings.Trim(old, "/") {
+			return match
+		}
+
+		rel, err := filepath.Rel(filepath.FromSlash(newDir), filepath.FromSlash(moved))
+		if err != nil {
+			return match
+		}
+
+		relinked := filepath.ToSlash(rel)
+		if strings.HasSuffix(target, "/") {
+			relinked += "/"
+		}
+
+		if strings.Contains(target, "%20") {
+			relinked = strings.ReplaceAll(relinked, " ", "%20")
+		}
+
+		return parts[1] + relinked + anchor + parts[3]
+	})
+}
+
+// relinkTarget generates the new target of wiki-style link, in the same style as [target].
+func relinkTarget(target string, moves Moves, resolver *LinkResolver) string {
+	resolved := resolver.Resolve(target)
+	if len(resolved) == 0 {
+		// Could be a link to moved folder.
+		if moved, ok := moves.Folders[strings.Trim(target, "/")]; ok {
+			if strings.HasSuffix(target, "/") {
+				return moved + "/"
+			}
+
+			return moved
+		}
+
+		return target
+	}
+
+	moved := moves.Title(resolved)
+	if moved == resolved {
+		return target
+	}
+
+	t := strings.ToLower(strings.TrimPrefix(target, "/"))
+	base := path.Base(moved)
+
+	switch t {
+	case strings.ToLower(resolved):
+		return moved
+	case strings.ToLower(strings.TrimSuffix(resolved, path.Ext(resolved))):
+		return strings.TrimSuffix(moved, path.Ext(moved))
+	case strings.ToLower(path.Base(resolved)):
+		return base
+	case strings.ToLower(strings.TrimSuffix(path.Base(resolved), path.Ext(resolved))):
+		return strings.TrimSuffix(base, path.Ext(base))
+	}
+
+	// Target is an alias, which moves together with note.
+	return target
+}
@@ -0,0 +1,91 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package models_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/insolite-dev/notya/lib/models"
+)
+
+func TestMovesOf(t *testing.T) {
+	nodes := []models.Node{
+		{Type: models.FOLDER, Title: "ideas/"},
+		{Type: models.FILE, Title: "ideas/a.md"},
+		{Type: models.FILE, Title: "ideasb.md"},
+		{Type: models.FILE, Title: "todo.md"},
+	}
+
+	tests := []struct {
+		current, new, expected string
+	}{
+		{current: "todo.md", new: "done.md", expected: "map[todo.md:done.md] map[]"},
+		{current: "ideas", new: "archive/ideas/", expected: "map[ideas/a.md:archive/ideas/a.md] map[ideas:archive/ideas]"},
+	}
+
+	for _, td := range tests {
+		moves := models.MovesOf(nodes, td.current, td.new)
+		if got := fmt.Sprint(moves.Notes, " ", moves.Folders); got != td.expected {
+			t.Errorf("MovesOf sum was different: Want: %v | Got: %v", td.expected, got)
+		}
+	}
+}
+
+func TestRelinkBody(t *testing.T) {
+	nodes := []models.Node{
+		{Type: models.FOLDER, Title: "ideas/"},
+		{Type: models.FILE, Title: "ideas/plan.md", Body: "---\naliases: [roadmap]\n---\n"},
+		{Type: models.FILE, Title: "todo.md"},
+		{Type: models.FILE, Title: "other.md"},
+	}
+	resolver := models.NewLinkResolver(nodes)
+
+	tests := []struct {
+		testName              string
+		current, new          string
+		title, body, expected string
+	}{
+		{
+			testName: "should keep style of wiki links",
+			current:  "todo.md", new: "done.md",
+			title:    "other.md",
+			body:     "[[todo]] [[todo.md|my list]] [[Todo#top]] [[other]]",
+			expected: "[[done]] [[done.md|my list]] [[done#top]] [[other]]",
+		},
+		{
+			testName: "should rewrite links to folder and notes under it",
+			current:  "ideas", new: "archive/ideas",
+			title:    "todo.md",
+			body:     "[[ideas/plan]] [[plan]] [[roadmap]] [[ideas/]] [p](ideas/plan.md#goals) [d](ideas/)",
+			expected: "[[archive/ideas/plan]] [[plan]] [[roadmap]] [[archive/ideas/]] [p](archive/ideas/plan.md#goals) [d](archive/ideas/)",
+		},
+		{
+			testName: "should rewrite relative links of moved note",
+			current:  "ideas", new: "archive/ideas",
+			title:    "ideas/plan.md",
+			body:     "[t](../todo.md) [web](https://notya.dev) [self](#top) ![img](../other.md)",
+			expected: "[t](../../todo.md) [web](https://notya.dev) [self](#top) ![img](../../other.md)",
+		},
+		{
+			testName: "should keep links, which point to unmoved notes",
+			current:  "todo.md", new: "done.md",
+			title:    "ideas/plan.md",
+			body:     "[o](../other.md) [[missing]]",
+			expected: "[o](../other.md) [[missing]]",
+		},
+	}
+
+	for _, td := range tests {
+		t.Run(td.testName, func(t *testing.T) {
+			moves := models.MovesOf(nodes, td.current, td.new)
+			if got := models.RelinkBody(td.title, td.body, moves, resolver); got != td.expected {
+				t.Errorf("RelinkBody sum was different: Want: %v | Got: %v", td.expected, got)
+			}
+		})
+	}
+}
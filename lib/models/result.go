@@ -0,0 +1,51 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package models
+
+import "encoding/json"
+
+// Result is the structured result of a command, printed at JSON mode.
+//
+//	Example:
+//
+// ╭──────────────────────────────────────────╮
+// │ Command: push                            │
+// │ OK: false                                │
+// │ Messages: [Pushed 2 nodes]               │
+// │ Errors: [Cannot push ideas/todo.md: ...] │
+// │ Count: 2                                 │
+// │ Nodes: [ideas/, ideas/plan.md]           │
+// ╰──────────────────────────────────────────╯
+type Result struct {
+	// Command is the full name of command, like: "trash restore".
+	Command string `json:"command"`
+
+	// OK is false, if command has failed or had any error.
+	OK bool `json:"ok"`
+
+	Messages []string `json:"messages,omitempty"`
+	Errors   []string `json:"errors,omitempty"`
+
+	// Count is the amount of listed or affected items.
+	Count int `json:"count"`
+
+	// Nodes are the listed or affected nodes.
+	Nodes []Node `json:"nodes,omitempty"`
+
+	// Data is the command specific result, like: search results or plan of changes.
+	Data interface{} `json:"data,omitempty"`
+}
+
+// ToString converts result to a formatted JSON string.
+func (r *Result) ToString() string {
+	jsonBytes, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return ""
+	}
+
+	return string(jsonBytes)
+}
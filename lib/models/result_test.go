@@ -0,0 +1,41 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package models_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/insolite-dev/notya/lib/models"
+)
+
+func TestResultToString(t *testing.T) {
+	result := models.Result{
+		Command: "push",
+		Errors:  []string{"failed"},
+		Count:   1,
+		Nodes:   []models.Node{{Type: models.FILE, Title: "todo.md"}},
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal([]byte(result.ToString()), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]interface{}{
+		"command": "push",
+		"ok":      false,
+		"errors":  []interface{}{"failed"},
+		"count":   float64(1),
+		"nodes":   []interface{}{map[string]interface{}{"typ": "FILE", "title": "todo.md", "path": nil, "created": "0001-01-01T00:00:00Z", "updated": "0001-01-01T00:00:00Z"}},
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("ToString sum was different: Want: %v | Got: %v", expected, got)
+	}
+}
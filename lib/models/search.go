@@ -0,0 +1,114 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package models
+
+import (
+	"regexp"
+	"strings"
+)
+
+// SearchOptions is the configuration of full-text search.
+type SearchOptions struct {
+	// Query is the searched text, or pattern if [Regex] is enabled.
+	Query string `json:"query"`
+
+	// IgnoreCase makes the search case-insensitive.
+	IgnoreCase bool `json:"ignore_case"`
+
+	// Regex makes the [Query] be used as a regular expression.
+	Regex bool `json:"regex"`
+
+	// Word matches only the whole words of [Query].
+	Word bool `json:"word"`
+
+	// In is the folder, that search is scoped to.
+	In string `json:"in"`
+}
+
+// Matcher compiles search options to a regular expression.
+//
+//	{Query: "to.do", IgnoreCase: true, Word: true} ─▶ (?i)\b(?:to\.do)\b
+func (o *SearchOptions) Matcher() (*regexp.Regexp, error) {
+	pattern := o.Query
+	if !o.Regex {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+
+	if o.Word {
+		pattern = `\b(?:` + pattern + `)\b`
+	}
+
+	if o.IgnoreCase {
+		pattern = "(?i)" + pattern
+	}
+
+	return regexp.Compile(pattern)
+}
+
+// Match is a single matched line of note's body.
+type Match struct {
+	// Line is the number of matched line, starting from 1.
+	Line int `json:"line"`
+
+	// Text is the full text of matched line.
+	Text string `json:"text"`
+
+	// Ranges are the [start, end] byte indexes of matches at [Text].
+	Ranges [][]int `json:"ranges"`
+}
+
+// SearchResult is the list of matches of one node.
+type SearchResult struct {
+	Node Node `json:"node"`
+
+	// TitleRanges are the [start, end] byte indexes of matches at node's title.
+	TitleRanges [][]int `json:"title_ranges,omitempty"`
+
+	// Matches are the matched lines of node's body.
+	Matches []Match `json:"matches,omitempty"`
+
+	// Score is the rank of result, available only for ranked search.
+	Score float64 `json:"score,omitempty"`
+}
+
+// MatchNode searches the title and body of [node] via [matcher].
+// The [scope] prefix of title (i.e the searched folder) isn't matched, but ranges
+// of title are still indexes of the whole title. Empty matches are skipped.
+// Returns nil, if neither title nor body matches.
+func MatchNode(node Node, scope string, matcher *regexp.Regexp) *SearchResult {
+	result := SearchResult{Node: node}
+
+	title := strings.TrimPrefix(node.Title, scope)
+	for _, r := range findAll(matcher, title) {
+		offset := len(node.Title) - len(title)
+		result.TitleRanges = append(result.TitleRanges, []int{r[0] + offset, r[1] + offset})
+	}
+
+	for i, line := range strings.Split(node.Body, "\n") {
+		if ranges := findAll(matcher, line); len(ranges) > 0 {
+			result.Matches = append(result.Matches, Match{Line: i + 1, Text: line, Ranges: ranges})
+		}
+	}
+
+	if len(result.TitleRanges) == 0 && len(result.Matches) == 0 {
+		return nil
+	}
+
+	return &result
+}
+
+// findAll returns the [start, end] byte indexes of non-empty matches of [matcher] at [text].
+func findAll(matcher *regexp.Regexp, text string) [][]int {
+	ranges := [][]int{}
+	for _, r := range matcher.FindAllStringIndex(text, -1) {
+		if r[1] > r[0] {
+			ranges = append(ranges, r)
+		}
+	}
+
+	return ranges
+}
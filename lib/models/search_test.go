@@ -0,0 +1,71 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package models_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/insolite-dev/notya/lib/models"
+)
+
+func TestSearchMatcher(t *testing.T) {
+	tests := []struct {
+		opts     models.SearchOptions
+		expected string
+		err      bool
+	}{
+		{opts: models.SearchOptions{Query: "to.do"}, expected: `to\.do`},
+		{opts: models.SearchOptions{Query: "to.do", IgnoreCase: true, Word: true}, expected: `(?i)\b(?:to\.do)\b`},
+		{opts: models.SearchOptions{Query: "to.do", Regex: true}, expected: `to.do`},
+		{opts: models.SearchOptions{Query: "(", Regex: true}, err: true},
+	}
+
+	for _, td := range tests {
+		got, err := td.opts.Matcher()
+		if (err != nil) != td.err {
+			t.Errorf("Matcher error was different: Got: %v", err)
+			continue
+		}
+
+		if err == nil && got.String() != td.expected {
+			t.Errorf("Matcher sum was different: Want: %v | Got: %v", td.expected, got)
+		}
+	}
+}
+
+func TestMatchNode(t *testing.T) {
+	tests := []struct {
+		opts     models.SearchOptions
+		expected string
+	}{
+		{opts: models.SearchOptions{Query: "milk"}, expected: "[] [{1 buy milk [[4 8]]}]"},
+		{opts: models.SearchOptions{Query: "milk", IgnoreCase: true}, expected: "[] [{1 buy milk [[4 8]]} {3 Milkshake [[0 4]]}]"},
+		{opts: models.SearchOptions{Query: "milk", IgnoreCase: true, Word: true}, expected: "[] [{1 buy milk [[4 8]]}]"},
+		{opts: models.SearchOptions{Query: "todo"}, expected: "[[6 10]] []"},
+		{opts: models.SearchOptions{Query: "coffee"}, expected: "<nil>"},
+		{opts: models.SearchOptions{Query: "x*", Regex: true}, expected: "<nil>"},
+		{opts: models.SearchOptions{Query: "ideas"}, expected: "<nil>"},
+		{opts: models.SearchOptions{Query: "do"}, expected: "[[8 10]] []"},
+	}
+
+	// Scope of title isn't matched, but ranges are indexes of whole title.
+	node := models.Node{Title: "ideas/todo.md", Body: "buy milk\nread book\nMilkshake"}
+
+	for _, td := range tests {
+		matcher, _ := td.opts.Matcher()
+
+		got := "<nil>"
+		if r := models.MatchNode(node, "ideas/", matcher); r != nil {
+			got = fmt.Sprint(r.TitleRanges, r.Matches)
+		}
+
+		if got != td.expected {
+			t.Errorf("MatchNode sum was different: Want: %v | Got: %v", td.expected, got)
+		}
+	}
+}
@@ -8,6 +8,8 @@ package models
 
 import (
 	"encoding/json"
+	"net"
+	"strings"
 
 	"github.com/mitchellh/mapstructure"
 )
@@ -18,12 +20,35 @@ const (
 	SettingsName     = ".settings.json"
 	DefaultEditor    = "vi"
 	DefaultLocalPath = "notya"
+	SQLiteName       = ".notya.db"
+
+	DefaultS3Endpoint = "s3.amazonaws.com"
+	WebDAVPasswordEnv = "NOTYA_WEBDAV_PASSWORD"
+	DefaultSSHPort    = "22"
+	HTTPTokenEnv      = "NOTYA_HTTP_TOKEN"
+)
+
+// Encryption modes of settings.
+const (
+	// EncryptAll encrypts bodies at every service, including local notes folder.
+	EncryptAll = "all"
+
+	// EncryptRemote encrypts bodies only at remote services.
+	EncryptRemote = "remote"
 )
 
 // NotyaIgnoreFiles are those files that shouldn't
 // be represented as note files.
 var NotyaIgnoreFiles []string = []string{
 	SettingsName,
+	SnapshotsName,
+	TombstonesName,
+	IndexName,
+	TrashName,
+	HistoryName,
+	TemplatesName,
+	SQLiteName,
+	SQLiteName + "-journal",
 	".DS_Store", // Darwin related.
 	".git",
 }
@@ -39,6 +64,7 @@ var NotyaIgnoreFiles []string = []string{
 // │ Firebase Project ID: notya-98tf3                   │
 // │ Firebase Account Key: /User/.../notya/key.json     │
 // │ Firebase Collection: notya-notes                   │
+// │ Encryption: remote                                 │
 // ╰────────────────────────────────────────────────────╯
 type Settings struct {
 	// Alert: development related field, shouldn't be used in production.
@@ -64,6 +90,10 @@ type Settings struct {
 	// Does same job as [FirebaseCollection] for local env.
 	NotesPath string `json:"notes_path" mapstructure:"notes_path" survey:"notes_path"`
 
+	// FirebaseTagsIndexed is set after "tags" field of notes written before
+	// tags were stored at documents, is filled. Until then, tags are queried on client side.
+	FirebaseTagsIndexed bool `json:"fire_tags_indexed,omitempty" mapstructure:"fire_tags_indexed,omitempty"`
+
 	// The project id of your firebase project.
 	//
 	// It is required for firebase remote connection.
@@ -78,6 +108,101 @@ type Settings struct {
 	// The concrete collection of nodes.
 	// Does same job as [NotesPath] but has to take just name of collection.
 	FirebaseCollection string `json:"fire_collection,omitempty" mapstructure:"fire_collection,omitempty" survey:"fire_collection"`
+
+	// The host of SSH server, like: "dev.example.com" or "dev.example.com:2222".
+	//
+	// It is required for SFTP remote connection.
+	SFTPHost string `json:"sftp_host,omitempty" mapstructure:"sftp_host,omitempty" survey:"sftp_host"`
+
+	// The user of SSH server. If it's empty, current user of machine is used.
+	SFTPUser string `json:"sftp_user,omitempty" mapstructure:"sftp_user,omitempty" survey:"sftp_user"`
+
+	// The path of private key file, like: "/User/john-doe/.ssh/id_ed25519".
+	// If it's empty, keys of ssh-agent (at SSH_AUTH_SOCK) are used.
+	SFTPKeyFile string `json:"sftp_key_file,omitempty" mapstructure:"sftp_key_file,omitempty" survey:"sftp_key_file"`
+
+	// The remote directory of notes, like: "/home/john-doe/notya".
+	// Does same job as [NotesPath] for SFTP remote connection.
+	//
+	// It is required for SFTP remote connection.
+	SFTPPath string `json:"sftp_path,omitempty" mapstructure:"sftp_path,omitempty" survey:"sftp_path"`
+
+	// The path of known hosts file, that host key of SSH server is verified by.
+	//
+	// Default: "~/.ssh/known_hosts".
+	SFTPKnownHosts string `json:"sftp_known_hosts,omitempty" mapstructure:"sftp_known_hosts,omitempty"`
+
+	// Encryption decides which services keep encrypted bodies of notes.
+	// Could be:
+	//   - "" (disabled)
+	//   - remote (only remote services)
+	//   - all (every service, including local notes folder)
+	Encryption string `json:"encryption,omitempty" mapstructure:"encryption,omitempty"`
+
+	// The base64 salt of passphrase's key derivation.
+	EncryptionSalt string `json:"encryption_salt,omitempty" mapstructure:"encryption_salt,omitempty"`
+
+	// A known value sealed by encryption key, that used to verify the passphrase.
+	EncryptionCheck string `json:"encryption_check,omitempty" mapstructure:"encryption_check,omitempty"`
+
+	// Vaults are folders, encrypted under their own passphrases.
+	Vaults []Vault `json:"vaults,omitempty" mapstructure:"vaults,omitempty"`
+
+	// Pattern of journal note titles, with date placeholders like {yyyy}, {mm} and {dd}.
+	// Default: [DefaultJournalPattern].
+	JournalPattern string `json:"journal_pattern,omitempty" mapstructure:"journal_pattern,omitempty"`
+
+	// The path of SQLite database file of notes.
+	// Must be given full path, like: "./User/john-doe/.../notes.db"
+	//
+	// Default: [SQLiteName] at notya's main folder.
+	SQLitePath string `json:"sqlite_path,omitempty" mapstructure:"sqlite_path,omitempty"`
+
+	// Name of template, that new journal notes are created from.
+	JournalTemplate string `json:"journal_template,omitempty" mapstructure:"journal_template,omitempty"`
+
+	// The endpoint of S3 compatible object storage, like: "s3.amazonaws.com" or "http://localhost:9000".
+	// Endpoints without "http://" scheme are connected via TLS.
+	//
+	// Default: [DefaultS3Endpoint].
+	S3Endpoint string `json:"s3_endpoint,omitempty" mapstructure:"s3_endpoint,omitempty" survey:"s3_endpoint"`
+
+	// The bucket of object storage, that keeps notes.
+	//
+	// It is required for S3 remote connection.
+	S3Bucket string `json:"s3_bucket,omitempty" mapstructure:"s3_bucket,omitempty" survey:"s3_bucket"`
+
+	// The region of bucket, like: "eu-central-1".
+	S3Region string `json:"s3_region,omitempty" mapstructure:"s3_region,omitempty" survey:"s3_region"`
+
+	// The path of AWS shared credentials file, like: "/User/john-doe/.aws/credentials".
+	// If it's empty, credentials are taken from environment variables or the default credentials file.
+	S3CredentialsFile string `json:"s3_credentials_file,omitempty" mapstructure:"s3_credentials_file,omitempty" survey:"s3_credentials_file"`
+
+	// The key prefix of nodes at bucket.
+	// Does same job as [FirebaseCollection] for S3 remote connection.
+	S3Prefix string `json:"s3_prefix,omitempty" mapstructure:"s3_prefix,omitempty" survey:"s3_prefix"`
+
+	// The URL of WebDAV collection of notes, like: "https://cloud.example.com/remote.php/dav/files/john-doe/notya/".
+	//
+	// It is required for WebDAV remote connection.
+	WebDAVURL string `json:"webdav_url,omitempty" mapstructure:"webdav_url,omitempty" survey:"webdav_url"`
+
+	// The username of WebDAV server's basic authentication.
+	WebDAVUsername string `json:"webdav_username,omitempty" mapstructure:"webdav_username,omitempty" survey:"webdav_username"`
+
+	// The password (or app password) of WebDAV server's basic authentication.
+	// If it's empty, password is taken from [WebDAVPasswordEnv] environment variable.
+	WebDAVPassword string `json:"webdav_password,omitempty" mapstructure:"webdav_password,omitempty" survey:"webdav_password"`
+
+	// The base URL of notya server (started via `notya serve`), like: "https://notes.example.com".
+	//
+	// It is required for HTTP remote connection.
+	HTTPURL string `json:"http_url,omitempty" mapstructure:"http_url,omitempty" survey:"http_url"`
+
+	// The access token of notya server.
+	// If it's empty, token is taken from [HTTPTokenEnv] environment variable.
+	HTTPToken string `json:"http_token,omitempty" mapstructure:"http_token,omitempty" survey:"http_token"`
 }
 
 // CopyWith updates pointed settings with a new data.
@@ -170,6 +295,60 @@ func (s *Settings) FirePath() string {
 	return DefaultAppName
 }
 
+// S3Path returns valid key prefix of nodes at S3 bucket.
+func (s *Settings) S3Path() string {
+	if prefix := strings.Trim(s.S3Prefix, "/"); len(prefix) > 0 {
+		return prefix
+	} else if len(s.Name) > 0 {
+		return s.Name
+	}
+
+	return DefaultAppName
+}
+
+// SFTPAddress returns the "host:port" address of SSH server.
+// Hosts without port are connected via [DefaultSSHPort].
+func (s *Settings) SFTPAddress() string {
+	if _, _, err := net.SplitHostPort(s.SFTPHost); err == nil {
+		return s.SFTPHost
+	}
+
+	return net.JoinHostPort(strings.Trim(s.SFTPHost, "[]"), DefaultSSHPort)
+}
+
+// TrashPath returns the firebase collection name of trash.
+//
+//	FirePath: "notya" ─▶ "notya-trash"
+func (s *Settings) TrashPath() string {
+	return s.FirePath() + "-trash"
+}
+
+// Encrypts checks if bodies of notes have to be encrypted at service.
+// [remote] decides whether service is a remote service or not.
+func (s *Settings) Encrypts(remote bool) bool {
+	return s.Encryption == EncryptAll || (remote && s.Encryption == EncryptRemote)
+}
+
+// WithoutSecrets returns a copy of settings, without credentials of remote connections.
+// Secrets are kept only at local settings (or environment), so they're stripped before
+// settings are written to remote services.
+func (s Settings) WithoutSecrets() Settings {
+	s.WebDAVPassword, s.HTTPToken = "", ""
+
+	return s
+}
+
+// VaultOf returns the vault, that node at [title] is inside of, or nil if it isn't inside of a vault.
+func (s *Settings) VaultOf(title string) *Vault {
+	for i, v := range s.Vaults {
+		if v.Contains(title) {
+			return &s.Vaults[i]
+		}
+	}
+
+	return nil
+}
+
 // IsValid checks validness of settings structure.
 func (s *Settings) IsValid() bool {
 	return len(s.Name) > 0 && len(s.Editor) > 0 && len(s.NotesPath) > 0
@@ -147,6 +147,81 @@ func TestFirePath(t *testing.T) {
 	}
 }
 
+func TestS3Path(t *testing.T) {
+	tests := []struct {
+		model    models.Settings
+		expected string
+	}{
+		{
+			model:    models.Settings{},
+			expected: "notya",
+		},
+		{
+			model:    models.Settings{Name: "my-notya"},
+			expected: "my-notya",
+		},
+		{
+			model:    models.Settings{S3Prefix: "/work/notes/", Name: "notya"},
+			expected: "work/notes",
+		},
+	}
+
+	for _, td := range tests {
+		got := td.model.S3Path()
+
+		if got != td.expected {
+			t.Errorf("S3Path's sum was different: Want: %v | Got: %v", td.expected, got)
+		}
+	}
+}
+
+func TestSFTPAddress(t *testing.T) {
+	tests := []struct {
+		model    models.Settings
+		expected string
+	}{
+		{
+			model:    models.Settings{SFTPHost: "dev.example.com"},
+			expected: "dev.example.com:22",
+		},
+		{
+			model:    models.Settings{SFTPHost: "dev.example.com:2222"},
+			expected: "dev.example.com:2222",
+		},
+		{
+			model:    models.Settings{SFTPHost: "::1"},
+			expected: "[::1]:22",
+		},
+	}
+
+	for _, td := range tests {
+		got := td.model.SFTPAddress()
+
+		if got != td.expected {
+			t.Errorf("SFTPAddress's sum was different: Want: %v | Got: %v", td.expected, got)
+		}
+	}
+}
+
+func TestEncrypts(t *testing.T) {
+	tests := []struct {
+		model         models.Settings
+		local, remote bool
+	}{
+		{model: models.Settings{}},
+		{model: models.Settings{Encryption: models.EncryptAll}, local: true, remote: true},
+		{model: models.Settings{Encryption: models.EncryptRemote}, remote: true},
+	}
+
+	for _, td := range tests {
+		local, remote := td.model.Encrypts(false), td.model.Encrypts(true)
+
+		if local != td.local || remote != td.remote {
+			t.Errorf("Encrypts sum was different for %v: Want: %v, %v | Got: %v, %v", td.model.Encryption, td.local, td.remote, local, remote)
+		}
+	}
+}
+
 func TestIsValid(t *testing.T) {
 	tests := []struct {
 		testname string
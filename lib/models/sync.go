@@ -0,0 +1,91 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package models
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// SnapshotsName is the name of file that keeps the base snapshots of sync.
+const SnapshotsName = ".snapshots.json"
+
+// FolderHash is the hash value that represents folders in snapshots.
+// Folders don't have a body, so their existence is the only thing to compare.
+const FolderHash = "/"
+
+// Snapshots is a collection of base snapshots of each synced service pair.
+// A base snapshot maps titles of nodes to the hash of their bodies, at the
+// moment of last successful sync.
+//
+//	Example:
+//
+// ╭────────────────────────────────────────────────╮
+// │ FIREBASE|LOCAL:                                │
+// │    todo.md  ─▶ 2c26b46b68ffc68ff99b453c1d30... │
+// │    ideas/   ─▶ /                               │
+// ╰────────────────────────────────────────────────╯
+type Snapshots map[string]map[string]string
+
+// SnapshotKey generates an order independent key for services pair.
+// So, syncing [a] with [b], and [b] with [a] would use the same base snapshot.
+func SnapshotKey(a, b string) string {
+	pair := []string{a, b}
+	sort.Strings(pair)
+
+	return strings.Join(pair, "|")
+}
+
+// Get returns the base snapshot of provided key.
+// If snapshot doesn't exists, an empty snapshot would be returned.
+func (s Snapshots) Get(key string) map[string]string {
+	if base, ok := s[key]; ok && base != nil {
+		return base
+	}
+
+	return map[string]string{}
+}
+
+// ToString converts snapshots to a formatted JSON string.
+func (s Snapshots) ToString() string {
+	jsonBytes, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return ""
+	}
+
+	return string(jsonBytes)
+}
+
+// DecodeSnapshots converts string(map) value to [Snapshots].
+func DecodeSnapshots(value string) Snapshots {
+	s := Snapshots{}
+	_ = json.Unmarshal([]byte(value), &s)
+
+	return s
+}
+
+// SyncReport is the result of three-way synchronization of two services.
+type SyncReport struct {
+	// Pulled are those nodes, that were updated or created at current service.
+	Pulled []Node `json:"pulled"`
+
+	// Pushed are those nodes, that were updated or created at remote service.
+	Pushed []Node `json:"pushed"`
+
+	// Removed are those nodes, that were deleted on one side
+	// and so removed from the other side.
+	Removed []Node `json:"removed"`
+
+	// Conflicts are those notes, that were changed on both sides.
+	Conflicts []Node `json:"conflicts"`
+}
+
+// IsEmpty checks if report has any change or not.
+func (r *SyncReport) IsEmpty() bool {
+	return len(r.Pulled) == 0 && len(r.Pushed) == 0 && len(r.Removed) == 0 && len(r.Conflicts) == 0
+}
@@ -0,0 +1,45 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package models_test
+
+import (
+	"testing"
+
+	"github.com/insolite-dev/notya/lib/models"
+)
+
+func TestSnapshotKey(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected string
+	}{
+		{a: "LOCAL", b: "FIREBASE", expected: "FIREBASE|LOCAL"},
+		{a: "FIREBASE", b: "LOCAL", expected: "FIREBASE|LOCAL"},
+	}
+
+	for _, td := range tests {
+		got := models.SnapshotKey(td.a, td.b)
+		if got != td.expected {
+			t.Errorf("SnapshotKey sum was different: Want: %v | Got: %v", td.expected, got)
+		}
+	}
+}
+
+func TestDecodeSnapshots(t *testing.T) {
+	snapshots := models.Snapshots{"FIREBASE|LOCAL": {"todo.md": "hash", "ideas/": models.FolderHash}}
+
+	got := models.DecodeSnapshots(snapshots.ToString())
+	base := got.Get("FIREBASE|LOCAL")
+
+	if len(base) != 2 || base["todo.md"] != "hash" || base["ideas/"] != models.FolderHash {
+		t.Errorf("DecodeSnapshots sum was different: Want: %v | Got: %v", snapshots, got)
+	}
+
+	if empty := got.Get("undefined"); empty == nil || len(empty) != 0 {
+		t.Errorf("Get should return an empty snapshot for undefined key, Got: %v", empty)
+	}
+}
@@ -0,0 +1,146 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package models
+
+import (
+	"path/filepath"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+)
+
+// TemplatesName is the name of hidden folder, that keeps note templates.
+// It's located at the notes root of each service, so local and remote
+// services keep their own templates.
+const TemplatesName = ".templates"
+
+// Built-in variables of templates, those are filled without asking.
+const (
+	TemplateDate    = "Date"
+	TemplateTime    = "Time"
+	TemplateTitle   = "Title"
+	TemplateUser    = "User"
+	TemplateService = "Service"
+)
+
+// TemplateBuiltins generates built-in variables of template, for the note of [title].
+// [title] is used without its extension and parent folders.
+//
+//	╭──────────────────────────────────────╮
+//	│ Date: 2022-01-02                     │
+//	│ Time: 15:04                          │
+//	│ Title: standup-2022-01-02            │
+//	│ User: random-user                    │
+//	│ Service: LOCAL                       │
+//	╰──────────────────────────────────────╯
+func TemplateBuiltins(title, user, service string, now time.Time) map[string]string {
+	name := filepath.Base(title)
+
+	return map[string]string{
+		TemplateDate:    now.Format("2006-01-02"),
+		TemplateTime:    now.Format("15:04"),
+		TemplateTitle:   strings.TrimSuffix(name, filepath.Ext(name)),
+		TemplateUser:    user,
+		TemplateService: service,
+	}
+}
+
+// TemplateVars parses [body] as a template, and returns names of its
+// variables those aren't built-in, in order of their first appearance.
+//
+//	# {{.Title}} - {{.Date}}   ─▶   [Project, Attendees]
+//	Project: {{.Project}}
+//	Attendees: {{.Attendees}}
+func TemplateVars(body string) ([]string, error) {
+	tmpl, err := template.New("").Parse(body)
+	if err != nil {
+		return nil, err
+	}
+
+	builtins := TemplateBuiltins("", "", "", time.Time{})
+
+	vars := []string{}
+	seen := map[string]bool{}
+
+	for _, name := range templateFields(tmpl.Tree.Root) {
+		if _, ok := builtins[name]; ok || seen[name] {
+			continue
+		}
+
+		seen[name] = true
+		vars = append(vars, name)
+	}
+
+	return vars, nil
+}
+
+// RenderTemplate executes [body] as a Go text/template with [vars].
+// Missing variables are rendered as empty strings.
+func RenderTemplate(body string, vars map[string]string) (string, error) {
+	tmpl, err := template.New("").Option("missingkey=zero").Parse(body)
+	if err != nil {
+		return "", err
+	}
+
+	var res strings.Builder
+	if err := tmpl.Execute(&res, vars); err != nil {
+		return "", err
+	}
+
+	return res.String(), nil
+}
+
+// templateFields collects names of top-level fields (like {{.Name}}) used at [node].
+func templateFields(node parse.Node) []string {
+	fields := []string{}
+
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return fields
+		}
+
+		for _, sub := range n.Nodes {
+			fields = append(fields, templateFields(sub)...)
+		}
+	case *parse.ActionNode:
+		fields = append(fields, templateFields(n.Pipe)...)
+	case *parse.PipeNode:
+		if n == nil {
+			return fields
+		}
+
+		for _, cmd := range n.Cmds {
+			fields = append(fields, templateFields(cmd)...)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			fields = append(fields, templateFields(arg)...)
+		}
+	case *parse.FieldNode:
+		fields = append(fields, n.Ident[0])
+	case *parse.IfNode:
+		fields = append(fields, templateBranchFields(&n.BranchNode)...)
+	case *parse.RangeNode:
+		fields = append(fields, templateBranchFields(&n.BranchNode)...)
+	case *parse.WithNode:
+		fields = append(fields, templateBranchFields(&n.BranchNode)...)
+	case *parse.TemplateNode:
+		fields = append(fields, templateFields(n.Pipe)...)
+	}
+
+	return fields
+}
+
+// templateBranchFields collects fields of if, range and with nodes.
+func templateBranchFields(n *parse.BranchNode) []string {
+	fields := templateFields(n.Pipe)
+	fields = append(fields, templateFields(n.List)...)
+
+	return append(fields, templateFields(n.ElseList)...)
+}
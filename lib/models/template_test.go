@@ -0,0 +1,106 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package models_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/insolite-dev/notya/lib/models"
+)
+
+func TestTemplateBuiltins(t *testing.T) {
+	now := time.Date(2022, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	got := models.TemplateBuiltins("meetings/standup-2022-01-02.md", "random-user", "LOCAL", now)
+	expected := map[string]string{
+		"Date":    "2022-01-02",
+		"Time":    "15:04",
+		"Title":   "standup-2022-01-02",
+		"User":    "random-user",
+		"Service": "LOCAL",
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("TemplateBuiltins sum was different: Want: %v | Got: %v", expected, got)
+	}
+}
+
+func TestTemplateVars(t *testing.T) {
+	tests := []struct {
+		testName string
+		body     string
+		expected []string
+		err      bool
+	}{
+		{
+			testName: "should skip built-in variables",
+			body:     "# {{.Title}} - {{.Date}} {{.Time}}\nby {{.User}} at {{.Service}}",
+			expected: []string{},
+		},
+		{
+			testName: "should collect each variable once, in order",
+			body:     "Project: {{.Project}}\n{{if .Attendees}}{{.Attendees}}{{else}}{{.Project}}{{end}}",
+			expected: []string{"Project", "Attendees"},
+		},
+		{
+			testName: "should collect variables of pipes and ranges",
+			body:     "{{.Topic | printf \"%q\"}}{{range .Items}}-{{end}}",
+			expected: []string{"Topic", "Items"},
+		},
+		{
+			testName: "should fail for invalid templates",
+			body:     "{{.Project",
+			err:      true,
+		},
+	}
+
+	for _, td := range tests {
+		t.Run(td.testName, func(t *testing.T) {
+			got, err := models.TemplateVars(td.body)
+			if (err != nil) != td.err {
+				t.Fatalf("TemplateVars error was different: Want error: %v | Got: %v", td.err, err)
+			}
+
+			if !td.err && !reflect.DeepEqual(got, td.expected) {
+				t.Errorf("TemplateVars sum was different: Want: %v | Got: %v", td.expected, got)
+			}
+		})
+	}
+}
+
+func TestRenderTemplate(t *testing.T) {
+	tests := []struct {
+		testName string
+		body     string
+		vars     map[string]string
+		expected string
+	}{
+		{
+			testName: "should render provided variables",
+			body:     "# {{.Title}}\nProject: {{.Project}}",
+			vars:     map[string]string{"Title": "standup", "Project": "notya"},
+			expected: "# standup\nProject: notya",
+		},
+		{
+			testName: "should render missing variables as empty",
+			body:     "Project: {{.Project}}",
+			vars:     map[string]string{},
+			expected: "Project: ",
+		},
+	}
+
+	for _, td := range tests {
+		t.Run(td.testName, func(t *testing.T) {
+			got, err := models.RenderTemplate(td.body, td.vars)
+			if err != nil || got != td.expected {
+				t.Errorf("RenderTemplate sum was different: Want: %q | Got: %q (%v)", td.expected, got, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// TombstonesName is the name of hidden note, that keeps tombstones of service.
+const TombstonesName = ".tombstones.json"
+
+// Tombstone is a record of removed node.
+// Used to propagate removals between services, on fetch and push.
+//
+//	Example:
+//
+// ╭──────────────────────────────────────────╮
+// │ Title: ideas/todo.md                     │
+// │ Service: LOCAL                           │
+// │ Deleted At: 2022-01-02T15:04:05Z         │
+// ╰──────────────────────────────────────────╯
+type Tombstone struct {
+	Title     string    `json:"title"`
+	Service   string    `json:"service"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// Tombstones is a list of [Tombstone]s.
+type Tombstones []Tombstone
+
+// Add appends a new tombstone to list, or updates
+// the existing tombstone that has the same title.
+func (t Tombstones) Add(title, service string, deletedAt time.Time) Tombstones {
+	tombstone := Tombstone{Title: title, Service: service, DeletedAt: deletedAt}
+
+	for i, ts := range t {
+		if ts.Title == title {
+			t[i] = tombstone
+			return t
+		}
+	}
+
+	return append(t, tombstone)
+}
+
+// Remove drops the tombstone that has the same title, i.e the node at [title] was created again.
+func (t Tombstones) Remove(title string) Tombstones {
+	title = strings.TrimSuffix(title, "/")
+
+	res := Tombstones{}
+	for _, ts := range t {
+		if strings.TrimSuffix(ts.Title, "/") != title {
+			res = append(res, ts)
+		}
+	}
+
+	return res
+}
+
+// Covers checks if node at [title] was removed.
+// Either directly, or by removing one of its parent folders.
+func (t Tombstones) Covers(title string) bool {
+	_, covered := t.DeletedAt(title)
+	return covered
+}
+
+// DeletedAt returns the latest removal time of node at [title], among the tombstones that cover it.
+// If node wasn't removed, it returns false as second value.
+func (t Tombstones) DeletedAt(title string) (time.Time, bool) {
+	title = strings.TrimSuffix(title, "/")
+
+	var deletedAt time.Time
+	covered := false
+
+	for _, ts := range t {
+		removed := strings.TrimSuffix(ts.Title, "/")
+		if title != removed && !strings.HasPrefix(title, removed+"/") {
+			continue
+		}
+
+		if !covered || ts.DeletedAt.After(deletedAt) {
+			deletedAt = ts.DeletedAt
+		}
+
+		covered = true
+	}
+
+	return deletedAt, covered
+}
+
+// ToString converts tombstones to a formatted JSON string.
+func (t Tombstones) ToString() string {
+	jsonBytes, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return ""
+	}
+
+	return string(jsonBytes)
+}
+
+// DecodeTombstones converts string(list) value to [Tombstones].
+func DecodeTombstones(value string) Tombstones {
+	t := Tombstones{}
+	_ = json.Unmarshal([]byte(value), &t)
+
+	return t
+}
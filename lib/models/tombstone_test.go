@@ -0,0 +1,77 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/insolite-dev/notya/lib/models"
+)
+
+func TestTombstonesAdd(t *testing.T) {
+	first, second := time.Unix(0, 0).UTC(), time.Unix(60, 0).UTC()
+
+	got := models.Tombstones{}.Add("todo.md", "LOCAL", first).Add("ideas/", "LOCAL", first).Add("todo.md", "FIREBASE", second)
+
+	if len(got) != 2 || got[0].Service != "FIREBASE" || !got[0].DeletedAt.Equal(second) {
+		t.Errorf("Tombstones.Add sum was different, Got: %v", got)
+	}
+
+	decoded := models.DecodeTombstones(got.ToString())
+	if len(decoded) != 2 || decoded[1].Title != "ideas/" || !decoded[1].DeletedAt.Equal(first) {
+		t.Errorf("DecodeTombstones sum was different: Want: %v | Got: %v", got, decoded)
+	}
+}
+
+func TestTombstonesCovers(t *testing.T) {
+	tombstones := models.Tombstones{{Title: "todo.md"}, {Title: "ideas/"}}
+
+	tests := []struct {
+		title    string
+		expected bool
+	}{
+		{title: "todo.md", expected: true},
+		{title: "ideas", expected: true},
+		{title: "ideas/new.md", expected: true},
+		{title: "ideas-old/new.md", expected: false},
+		{title: "todo.md.bak", expected: false},
+	}
+
+	for _, td := range tests {
+		got := tombstones.Covers(td.title)
+		if got != td.expected {
+			t.Errorf("Covers sum was different for %v: Want: %v | Got: %v", td.title, td.expected, got)
+		}
+	}
+}
+
+func TestTombstonesDeletedAt(t *testing.T) {
+	first, second := time.Unix(1, 0).UTC(), time.Unix(2, 0).UTC()
+	tombstones := models.Tombstones{}.Add("ideas/", "LOCAL", first).Add("ideas/todo.md", "LOCAL", second)
+
+	tests := []struct {
+		title    string
+		expected time.Time
+		covered  bool
+	}{
+		{title: "ideas/todo.md", expected: second, covered: true},
+		{title: "ideas/new.md", expected: first, covered: true},
+		{title: "todo.md", covered: false},
+	}
+
+	for _, td := range tests {
+		got, covered := tombstones.DeletedAt(td.title)
+		if !got.Equal(td.expected) || covered != td.covered {
+			t.Errorf("DeletedAt sum was different for %v: Want: %v, %v | Got: %v, %v", td.title, td.expected, td.covered, got, covered)
+		}
+	}
+
+	if removed := tombstones.Remove("ideas"); len(removed) != 1 || removed.Covers("ideas/new.md") {
+		t.Errorf("Remove should drop the tombstone of same title, Got: %v", removed)
+	}
+}
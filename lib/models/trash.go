@@ -0,0 +1,149 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TrashName is the name of hidden folder, that keeps removed nodes of local services.
+// Remote services keep them at a separate collection, see [Settings.TrashPath].
+const TrashName = ".trash"
+
+// TrashEntry is one removal, that was moved to trash.
+// Removing a folder moves the whole folder (with its sub nodes) as one entry.
+//
+//	Example:
+//
+// ╭──────────────────────────────────────────╮
+// │ ID: 1641135845000000000                  │
+// │ Title: ideas/                            │
+// │ Service: LOCAL                           │
+// │ Deleted At: 2022-01-02T15:04:05Z         │
+// │ Nodes: [ideas/, ideas/todo.md]           │
+// ╰──────────────────────────────────────────╯
+type TrashEntry struct {
+	ID string `json:"id"`
+
+	// Title is the original title of removed node.
+	// Titles of folders always end with a slash.
+	Title string `json:"title"`
+
+	Service   string    `json:"service"`
+	DeletedAt time.Time `json:"deleted_at"`
+
+	// Nodes are the removed node itself and its sub nodes,
+	// at their original titles and with their bodies.
+	Nodes []Node `json:"nodes,omitempty"`
+}
+
+// NewTrashEntry generates a trash entry of [nodes], removed at [deletedAt].
+// Paths and pretties of nodes are dropped, since they're restored by titles.
+func NewTrashEntry(title, service string, nodes []Node, deletedAt time.Time) TrashEntry {
+	entry := TrashEntry{
+		ID:        fmt.Sprint(deletedAt.UnixNano()),
+		Title:     title,
+		Service:   service,
+		DeletedAt: deletedAt,
+	}
+
+	for _, n := range nodes {
+		n.Path, n.Pretty = nil, nil
+		entry.Nodes = append(entry.Nodes, n)
+	}
+
+	return entry
+}
+
+// IsFolder checks if entry was a removed folder.
+func (e *TrashEntry) IsFolder() bool {
+	return strings.HasSuffix(e.Title, "/")
+}
+
+// ToJSON converts trash entry to map value.
+func (e *TrashEntry) ToJSON() map[string]interface{} {
+	b, _ := json.Marshal(&e)
+
+	var m map[string]interface{}
+	_ = json.Unmarshal(b, &m)
+
+	return m
+}
+
+// FromJson converts provided map data to [TrashEntry] structure.
+func (e *TrashEntry) FromJson(data map[string]interface{}) error {
+	jsonBytes, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(jsonBytes, &e)
+}
+
+// ToString converts trash entry to a formatted JSON string.
+func (e *TrashEntry) ToString() string {
+	jsonBytes, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return ""
+	}
+
+	return string(jsonBytes)
+}
+
+// DecodeTrashEntry converts string(map) value to [TrashEntry].
+func DecodeTrashEntry(value string) (*TrashEntry, error) {
+	e := TrashEntry{}
+	if err := json.Unmarshal([]byte(value), &e); err != nil {
+		return nil, err
+	}
+
+	return &e, nil
+}
+
+// Trash is a list of [TrashEntry]s.
+type Trash []TrashEntry
+
+// Sort orders trash entries from the latest removed to the earliest.
+func (t Trash) Sort() Trash {
+	sort.SliceStable(t, func(i, j int) bool { return t[i].DeletedAt.After(t[j].DeletedAt) })
+	return t
+}
+
+// Find returns the latest removed entry, that has [title] (or ID).
+// Trailing slashes of titles are ignored, so "ideas" finds "ideas/".
+func (t Trash) Find(title string) *TrashEntry {
+	title = strings.TrimSuffix(title, "/")
+
+	var found *TrashEntry
+	for i, e := range t {
+		if e.ID != title && strings.TrimSuffix(e.Title, "/") != title {
+			continue
+		}
+
+		if found == nil || e.DeletedAt.After(found.DeletedAt) {
+			found = &t[i]
+		}
+	}
+
+	return found
+}
+
+// OlderThan filters those entries, that were removed more than [age] ago from [now].
+func (t Trash) OlderThan(age time.Duration, now time.Time) Trash {
+	res := Trash{}
+	for _, e := range t {
+		if now.Sub(e.DeletedAt) >= age {
+			res = append(res, e)
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,90 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/insolite-dev/notya/lib/models"
+)
+
+func TestNewTrashEntry(t *testing.T) {
+	deletedAt := time.Unix(60, 0).UTC()
+	nodes := []models.Node{
+		{Type: models.FOLDER, Title: "ideas/", Path: map[string]string{"LOCAL": "/notya/ideas/"}},
+		{Type: models.FILE, Title: "ideas/todo.md", Body: "todo", Pretty: []string{"", "todo.md"}},
+	}
+
+	got := models.NewTrashEntry("ideas/", "LOCAL", nodes, deletedAt)
+	if got.ID != "60000000000" || !got.IsFolder() || len(got.Nodes) != 2 {
+		t.Fatalf("NewTrashEntry sum was different, Got: %v", got)
+	}
+
+	if got.Nodes[0].Path != nil || got.Nodes[1].Pretty != nil {
+		t.Errorf("Paths and pretties of trashed nodes should be dropped, Got: %v", got.Nodes)
+	}
+
+	decoded, err := models.DecodeTrashEntry(got.ToString())
+	if err != nil || decoded.Title != "ideas/" || !decoded.DeletedAt.Equal(deletedAt) || decoded.Nodes[1].Body != "todo" {
+		t.Errorf("DecodeTrashEntry sum was different: Want: %v | Got: %v (%v)", got, decoded, err)
+	}
+
+	var fromJSON models.TrashEntry
+	if err := fromJSON.FromJson(got.ToJSON()); err != nil || fromJSON.ID != got.ID || len(fromJSON.Nodes) != 2 {
+		t.Errorf("TrashEntry JSON sum was different: Want: %v | Got: %v (%v)", got, fromJSON, err)
+	}
+}
+
+func TestTrashFind(t *testing.T) {
+	trash := models.Trash{
+		{ID: "1", Title: "todo.md", DeletedAt: time.Unix(1, 0)},
+		{ID: "3", Title: "todo.md", DeletedAt: time.Unix(3, 0)},
+		{ID: "2", Title: "ideas/", DeletedAt: time.Unix(2, 0)},
+	}
+
+	tests := []struct {
+		title    string
+		expected string
+	}{
+		{title: "todo.md", expected: "3"},
+		{title: "ideas", expected: "2"},
+		{title: "ideas/", expected: "2"},
+		{title: "1", expected: "1"},
+		{title: "done.md", expected: ""},
+	}
+
+	for _, td := range tests {
+		got := ""
+		if e := trash.Find(td.title); e != nil {
+			got = e.ID
+		}
+
+		if got != td.expected {
+			t.Errorf("Find sum was different for %v: Want: %v | Got: %v", td.title, td.expected, got)
+		}
+	}
+}
+
+func TestTrashSortAndOlderThan(t *testing.T) {
+	now := time.Unix(100*24*60*60, 0)
+	trash := models.Trash{
+		{ID: "old", DeletedAt: now.Add(-40 * 24 * time.Hour)},
+		{ID: "new", DeletedAt: now.Add(-time.Hour)},
+		{ID: "mid", DeletedAt: now.Add(-30 * 24 * time.Hour)},
+	}
+
+	sorted := trash.Sort()
+	if sorted[0].ID != "new" || sorted[2].ID != "old" {
+		t.Errorf("Sort sum was different, Got: %v", sorted)
+	}
+
+	old := trash.OlderThan(30*24*time.Hour, now)
+	if len(old) != 2 || old[0].ID != "mid" || old[1].ID != "old" {
+		t.Errorf("OlderThan sum was different, Got: %v", old)
+	}
+}
@@ -0,0 +1,111 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// Vault is a folder, which notes are encrypted under its own passphrase.
+//
+//	Example:
+//
+// ╭──────────────────────────────────────────╮
+// │ Folder: secrets/                         │
+// │ Salt: 5eFZl9u6Hk1Q8v0b3yq2Ow==           │
+// │ Check: notya:vault:...                   │
+// ╰──────────────────────────────────────────╯
+type Vault struct {
+	// Folder is the title of vault folder, always ends with a slash.
+	Folder string `json:"folder" mapstructure:"folder"`
+
+	// The base64 salt of passphrase's key derivation.
+	Salt string `json:"salt" mapstructure:"salt"`
+
+	// A known value sealed by vault key, that used to verify the passphrase.
+	Check string `json:"check" mapstructure:"check"`
+}
+
+// VaultFolder normalizes [title] to a vault folder title.
+//
+//	VaultFolder("/secrets") ─▶ "secrets/"
+func VaultFolder(title string) string {
+	return strings.Trim(title, "/ ") + "/"
+}
+
+// Contains checks if node at [title] is inside the vault (including vault folder itself).
+func (v *Vault) Contains(title string) bool {
+	return strings.HasPrefix(title, v.Folder) || title == strings.TrimSuffix(v.Folder, "/")
+}
+
+// VaultSession is an unlocked vault, which key is cached until [Expires].
+type VaultSession struct {
+	Folder string `json:"folder"`
+
+	// Key is the base64 encoded derived key of vault.
+	Key string `json:"key"`
+
+	Expires time.Time `json:"expires"`
+}
+
+// VaultSessions is the list of unlocked vaults.
+type VaultSessions []VaultSession
+
+// Find returns the session of vault at [folder], that isn't expired at [now].
+func (s VaultSessions) Find(folder string, now time.Time) *VaultSession {
+	for i, session := range s {
+		if session.Folder == folder && session.Expires.After(now) {
+			return &s[i]
+		}
+	}
+
+	return nil
+}
+
+// Active returns sessions, which aren't expired at [now].
+func (s VaultSessions) Active(now time.Time) VaultSessions {
+	active := VaultSessions{}
+	for _, session := range s {
+		if session.Expires.After(now) {
+			active = append(active, session)
+		}
+	}
+
+	return active
+}
+
+// Without returns sessions, except the session of vault at [folder].
+func (s VaultSessions) Without(folder string) VaultSessions {
+	res := VaultSessions{}
+	for _, session := range s {
+		if session.Folder != folder {
+			res = append(res, session)
+		}
+	}
+
+	return res
+}
+
+// ToString converts sessions to a formatted JSON string.
+func (s VaultSessions) ToString() string {
+	jsonBytes, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return ""
+	}
+
+	return string(jsonBytes)
+}
+
+// DecodeVaultSessions converts string(list) value to [VaultSessions].
+func DecodeVaultSessions(value string) VaultSessions {
+	s := VaultSessions{}
+	_ = json.Unmarshal([]byte(value), &s)
+
+	return s
+}
@@ -0,0 +1,84 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/insolite-dev/notya/lib/models"
+)
+
+func TestVaultFolder(t *testing.T) {
+	tests := []struct {
+		title, expected string
+	}{
+		{title: "secrets", expected: "secrets/"},
+		{title: "/secrets/", expected: "secrets/"},
+		{title: "a/b", expected: "a/b/"},
+	}
+
+	for _, td := range tests {
+		if got := models.VaultFolder(td.title); got != td.expected {
+			t.Errorf("VaultFolder sum was different: Want: %v | Got: %v", td.expected, got)
+		}
+	}
+}
+
+func TestVaultOf(t *testing.T) {
+	settings := models.Settings{Vaults: []models.Vault{{Folder: "secrets/"}, {Folder: "a/b/"}}}
+
+	tests := []struct {
+		title, expected string
+	}{
+		{title: "secrets", expected: "secrets/"},
+		{title: "secrets/keys.md", expected: "secrets/"},
+		{title: "a/b/c/d.md", expected: "a/b/"},
+		{title: "a/c.md"},
+		{title: "secrets.md"},
+	}
+
+	for _, td := range tests {
+		var got string
+		if v := settings.VaultOf(td.title); v != nil {
+			got = v.Folder
+		}
+
+		if got != td.expected {
+			t.Errorf("VaultOf sum was different for %v: Want: %v | Got: %v", td.title, td.expected, got)
+		}
+	}
+}
+
+func TestVaultSessions(t *testing.T) {
+	now := time.Now()
+	sessions := models.VaultSessions{
+		{Folder: "secrets/", Key: "a", Expires: now.Add(time.Minute)},
+		{Folder: "old/", Key: "b", Expires: now.Add(-time.Minute)},
+	}
+
+	if got := sessions.Find("secrets/", now); got == nil || got.Key != "a" {
+		t.Errorf("Find sum was different: Want: %v | Got: %v", "a", got)
+	}
+
+	if got := sessions.Find("old/", now); got != nil {
+		t.Errorf("Find should skip expired sessions, Got: %v", got)
+	}
+
+	if got := sessions.Active(now); len(got) != 1 || got[0].Folder != "secrets/" {
+		t.Errorf("Active sum was different: Got: %v", got)
+	}
+
+	if got := sessions.Without("secrets/"); len(got) != 1 || got[0].Folder != "old/" {
+		t.Errorf("Without sum was different: Got: %v", got)
+	}
+
+	decoded := models.DecodeVaultSessions(sessions.ToString())
+	if len(decoded) != 2 || decoded[0].Key != "a" || !decoded[0].Expires.Equal(sessions[0].Expires) {
+		t.Errorf("DecodeVaultSessions sum was different: Want: %v | Got: %v", sessions, decoded)
+	}
+}
@@ -0,0 +1,111 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/insolite-dev/notya/lib/models"
+)
+
+// archiveStore is a storage of hidden JSON files, like entries of trash and histories of notes.
+// Implemented by services, which keep files at a remote storage, so [archive] works on top of them.
+//
+// Titles are relative to the notes folder of service, like: ".trash/<id>.json".
+type archiveStore interface {
+	// readArchive reads the file at [title], or returns [errArchiveNotFound] if it doesn't exist.
+	readArchive(title string) (string, error)
+
+	// readArchives reads all files of the [folder]. Missing folder has no files.
+	readArchives(folder string) ([]string, error)
+
+	// writeArchive overwrites the file at [title], creating its parent folders.
+	writeArchive(title, body string) error
+
+	// removeArchive removes the file at [title]. Missing files aren't reported.
+	removeArchive(title string) error
+}
+
+// errArchiveNotFound is returned by [archiveStore] for missing files.
+var errArchiveNotFound = errors.New("archive file not found")
+
+// archive implements [Trasher] and [Versioner] over an [archiveStore].
+//
+//	<notes folder>/.trash/<id>.json       entry of trash
+//	<notes folder>/.history/<title>.json  history of note
+type archive struct {
+	store archiveStore
+}
+
+// trashTitle returns the title of trash entry file of [id].
+func trashTitle(id string) string {
+	return models.TrashName + "/" + id + ".json"
+}
+
+// historyTitle returns the title of history file of note at [title].
+func historyTitle(title string) string {
+	return models.HistoryName + "/" + strings.Trim(title, "/") + ".json"
+}
+
+// PutTrash saves [entry] as a JSON file at trash folder.
+func (a archive) PutTrash(entry models.TrashEntry) error {
+	return a.store.writeArchive(trashTitle(entry.ID), entry.ToString())
+}
+
+// TrashEntries reads all entry files of trash folder.
+// Files that cannot be decoded as entries are skipped.
+func (a archive) TrashEntries() (models.Trash, error) {
+	bodies, err := a.store.readArchives(models.TrashName)
+	if err != nil {
+		return nil, err
+	}
+
+	trash := models.Trash{}
+	for _, body := range bodies {
+		if entry, err := models.DecodeTrashEntry(body); err == nil {
+			trash = append(trash, *entry)
+		}
+	}
+
+	return trash, nil
+}
+
+// DropTrash removes the entry file of [id] from trash folder.
+func (a archive) DropTrash(id string) error {
+	return a.store.removeArchive(trashTitle(id))
+}
+
+// Versions reads the history file of note at [title].
+// Notes that were never changed, have an empty history.
+func (a archive) Versions(title string) (models.History, error) {
+	body, err := a.store.readArchive(historyTitle(title))
+	if err == errArchiveNotFound {
+		return models.History{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return models.DecodeHistory(body), nil
+}
+
+// DropVersions removes the history file of note at [title].
+func (a archive) DropVersions(title string) error {
+	return a.store.removeArchive(historyTitle(title))
+}
+
+// SaveVersion appends [version] to the history file of its note.
+func (a archive) SaveVersion(version models.Version) error {
+	history, err := a.Versions(version.Title)
+	if err != nil {
+		return err
+	}
+
+	version.Rev = history.NextRev()
+
+	return a.store.writeArchive(historyTitle(version.Title), append(history, version).ToString())
+}
@@ -0,0 +1,34 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services
+
+// Batch starts batches of both tombstones of [s] and search indexes, and returns their shared flush function.
+// Used by operations, that change many nodes at once. See [BatchTombstones] and [BatchIndexes].
+func Batch(s ServiceRepo) func() error {
+	flushTombstones, flushIndexes := BatchTombstones(s), BatchIndexes()
+
+	return func() error {
+		err := flushTombstones()
+		if indexErr := flushIndexes(); err == nil {
+			err = indexErr
+		}
+
+		return err
+	}
+}
+
+// batched runs [fn] in a batch of [s] (see [Batch]).
+func batched(s ServiceRepo, fn func() error) error {
+	flush := Batch(s)
+
+	err := fn()
+	if flushErr := flush(); err == nil {
+		err = flushErr
+	}
+
+	return err
+}
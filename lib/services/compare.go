@@ -0,0 +1,115 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/pkg"
+)
+
+// Compare collects differences of [current] service's nodes from [against] service's nodes.
+// If [path] is provided, only the note at path or nodes under the folder at path are compared.
+//
+// Nodes that exist on only one side are listed by their side, and notes
+// that exist on both sides with different bodies, include a unified diff.
+func Compare(current, against ServiceRepo, path string) (models.Comparison, error) {
+	comparison := models.Comparison{Current: current.Type(), Against: against.Type(), Nodes: []models.NodeDiff{}}
+
+	currentNodes, err := compareNodes(current, path)
+	if err != nil {
+		return comparison, err
+	}
+
+	againstNodes, err := compareNodes(against, path)
+	if err != nil {
+		return comparison, err
+	}
+
+	scope := strings.Trim(path, "/")
+	inScope := func(title string) bool {
+		t := strings.TrimSuffix(title, "/")
+		return len(scope) == 0 || t == scope || strings.HasPrefix(t, scope+"/")
+	}
+
+	set := keys(currentNodes)
+	for t := range againstNodes {
+		set[t] = true
+	}
+
+	// Alphabetical order keeps the tree order, so sub nodes come right after their folders.
+	titles := []string{}
+	for t := range set {
+		titles = append(titles, t)
+	}
+	sort.Strings(titles)
+
+	for _, t := range titles {
+		if !inScope(t) {
+			continue
+		}
+
+		c, cok := currentNodes[t]
+		a, aok := againstNodes[t]
+
+		switch {
+		case cok && !aok:
+			comparison.Nodes = append(comparison.Nodes, models.NodeDiff{Title: t, Type: c.Type, Side: models.CURRENT})
+		case aok && !cok:
+			comparison.Nodes = append(comparison.Nodes, models.NodeDiff{Title: t, Type: a.Type, Side: models.AGAINST})
+		case !c.IsFolder() && c.Body != a.Body:
+			comparison.Nodes = append(comparison.Nodes, models.NodeDiff{
+				Title: t,
+				Type:  c.Type,
+				Side:  models.BOTH,
+				Diff:  pkg.UnifiedDiff(against.Type()+"/"+t, current.Type()+"/"+t, a.Body, c.Body),
+			})
+		}
+	}
+
+	return comparison, nil
+}
+
+// compareNodes collects nodes of [s] at [path], as a map of normalized titles.
+// Empty path collects all nodes of service. Otherwise only the note at path is viewed,
+// or nodes under the folder at path are listed. Missing path results an empty map.
+func compareNodes(s ServiceRepo, path string) (map[string]models.Node, error) {
+	scope := strings.Trim(path, "/")
+	if len(scope) == 0 {
+		return syncNodes(s)
+	}
+
+	note, viewErr := s.View(models.Note{Title: scope})
+	if viewErr == nil && !strings.HasSuffix(path, "/") {
+		n := note.ToNode()
+		n.Title = scope
+
+		return map[string]models.Node{scope: n}, nil
+	}
+
+	folder := models.Folder{Title: scope + "/"}
+	if exists, err := s.IsNodeExists(folder.ToNode()); err != nil {
+		return nil, err
+	} else if !exists {
+		return map[string]models.Node{}, nil
+	}
+
+	nodes, err := folderNodes(s, scope)
+	if err != nil {
+		// Node exists, but it's neither viewable nor listable (like a note of locked vault).
+		if viewErr != nil {
+			return nil, viewErr
+		}
+
+		return nil, err
+	}
+
+	nodes[folder.Title] = folder.ToNode()
+	return nodes, nil
+}
@@ -0,0 +1,74 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services_test
+
+import (
+	"testing"
+
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/lib/services"
+)
+
+func TestCompare(t *testing.T) {
+	local, remote := newTestLocalService(t), newTestLocalService(t)
+
+	for _, s := range []*services.LocalService{local, remote} {
+		s.Mkdir(models.Folder{Title: "ideas/"})
+		s.Create(models.Note{Title: "ideas/same.md", Body: "same\n"})
+		s.Create(models.Note{Title: "todo.md", Body: "first\n"})
+	}
+
+	local.Edit(models.Note{Title: "todo.md", Body: "second\n"})
+	local.Create(models.Note{Title: "ideas/new.md"})
+	remote.Mkdir(models.Folder{Title: "old/"})
+	remote.Create(models.Note{Title: "old/a.md"})
+
+	tests := []struct {
+		path     string
+		expected map[string]models.Side
+	}{
+		{
+			path: "",
+			expected: map[string]models.Side{
+				"ideas/new.md": models.CURRENT,
+				"old/":         models.AGAINST,
+				"old/a.md":     models.AGAINST,
+				"todo.md":      models.BOTH,
+			},
+		},
+		{path: "ideas", expected: map[string]models.Side{"ideas/new.md": models.CURRENT}},
+		{path: "todo.md", expected: map[string]models.Side{"todo.md": models.BOTH}},
+		{path: "ideas/same.md", expected: map[string]models.Side{}},
+		{path: "old", expected: map[string]models.Side{"old/": models.AGAINST, "old/a.md": models.AGAINST}},
+		{path: "old/a.md", expected: map[string]models.Side{"old/a.md": models.AGAINST}},
+		{path: "missing.md", expected: map[string]models.Side{}},
+	}
+
+	for _, td := range tests {
+		got, err := services.Compare(local, remote, td.path)
+		if err != nil {
+			t.Fatalf("Compare returned an error: %v", err)
+		}
+
+		if len(got.Nodes) != len(td.expected) {
+			t.Errorf("Compare sum was different for %q: Want: %v | Got: %v", td.path, td.expected, got.Nodes)
+			continue
+		}
+
+		for _, n := range got.Nodes {
+			if td.expected[n.Title] != n.Side {
+				t.Errorf("Side of %v was different: Want: %v | Got: %v", n.Title, td.expected[n.Title], n.Side)
+			}
+		}
+	}
+
+	got, _ := services.Compare(local, remote, "todo.md")
+	expected := "--- LOCAL/todo.md\n+++ LOCAL/todo.md\n@@ -1 +1 @@\n-first\n+second\n"
+	if got.Nodes[0].Diff != expected {
+		t.Errorf("Diff of changed note was different: Want: %q | Got: %q", expected, got.Nodes[0].Diff)
+	}
+}
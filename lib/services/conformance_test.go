@@ -0,0 +1,236 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/lib/services"
+)
+
+// conformanceBackends are remote services, that are checked by the same conformance cases.
+// Each one creates a new service, connected to an in-process server of its backend.
+var conformanceBackends = []struct {
+	name       string
+	newService func(t *testing.T) services.ServiceRepo
+}{
+	{"S3", func(t *testing.T) services.ServiceRepo { return newTestS3Service(t) }},
+	{"WebDAV", func(t *testing.T) services.ServiceRepo { return newTestWebDAVService(t) }},
+	{"SFTP", func(t *testing.T) services.ServiceRepo { return newTestSFTPService(t) }},
+}
+
+// conformanceCases are behaviours, that each remote service should have the same as local service.
+var conformanceCases = []struct {
+	name string
+	run  func(t *testing.T, s services.ServiceRepo)
+}{
+	{"Nodes", conformNodes},
+	{"Rename", conformRename},
+	{"Migrate", conformMigrate},
+	{"Trash", conformTrash},
+	{"Secrets", conformSecrets},
+}
+
+func TestServiceConformance(t *testing.T) {
+	for _, backend := range conformanceBackends {
+		for _, td := range conformanceCases {
+			backend, td := backend, td
+			t.Run(backend.name+"/"+td.name, func(t *testing.T) {
+				td.run(t, backend.newService(t))
+			})
+		}
+	}
+}
+
+func conformNodes(t *testing.T, s services.ServiceRepo) {
+	if _, err := s.Create(models.Note{Title: "ideas/a.md"}); err == nil {
+		t.Errorf("Create should fail, if parent folder doesn't exist")
+	}
+
+	s.Mkdir(models.Folder{Title: "ideas"})
+	s.Mkdir(models.Folder{Title: "ideas/old/"})
+	s.Create(models.Note{Title: "ideas/a.md", Body: "---\ntags: [work]\n---\nA"})
+	s.Create(models.Note{Title: "ideas/old/b.md", Body: "B"})
+	s.Create(models.Note{Title: "c.md", Body: "C"})
+
+	if _, err := s.Create(models.Note{Title: "c.md"}); err == nil {
+		t.Errorf("Create should fail for existing notes")
+	}
+
+	if _, err := s.Mkdir(models.Folder{Title: "ideas"}); err == nil {
+		t.Errorf("Mkdir should fail for existing folders")
+	}
+
+	nodes, _, err := s.GetAll("", "", models.NotyaIgnoreFiles)
+	expected := "[c.md ideas/ ideas/a.md ideas/old/ ideas/old/b.md]"
+	if err != nil || fmt.Sprint(titles(nodes)) != expected {
+		t.Fatalf("GetAll sum was different: Want: %v | Got: %v (%v)", expected, titles(nodes), err)
+	}
+
+	if a := nodes[2]; a.Body != "---\ntags: [work]\n---\nA" || fmt.Sprint(a.Tags) != "[work]" || a.Created.IsZero() {
+		t.Errorf("GetAll should fill metadata of notes, Got: %v", a)
+	}
+
+	files, _, err := s.GetAll("ideas", "file", models.NotyaIgnoreFiles)
+	if err != nil || fmt.Sprint(titles(files)) != "[a.md old/b.md]" {
+		t.Errorf("GetAll of folder sum was different: Got: %v (%v)", titles(files), err)
+	}
+
+	if _, err := s.Edit(models.Note{Title: "ideas"}); err == nil {
+		t.Errorf("Edit should fail for folders")
+	}
+
+	if _, err := s.Edit(models.Note{Title: "c.md", Body: "C2"}); err != nil {
+		t.Fatalf("Edit returned an error: %v", err)
+	}
+
+	if c, err := s.View(models.Note{Title: "c.md"}); err != nil || c.Body != "C2" {
+		t.Errorf("View sum was different: Got: %v (%v)", c, err)
+	}
+
+	if history, err := services.History(s, "c.md"); err != nil || len(history) != 1 || history[0].Body != "C" {
+		t.Errorf("Edit should keep the previous body at history, Got: %v (%v)", history, err)
+	}
+
+	if err := s.Remove(models.Node{Title: "ideas/"}); err != nil {
+		t.Fatalf("Remove returned an error: %v", err)
+	}
+
+	nodes, _, _ = s.GetAll("", "", models.NotyaIgnoreFiles)
+	if fmt.Sprint(titles(nodes)) != "[c.md]" {
+		t.Errorf("Remove should remove sub nodes of folder, Got: %v", titles(nodes))
+	}
+
+	tombstones, _ := services.Tombstones(s)
+	if !tombstones.Covers("ideas/old/b.md") {
+		t.Errorf("Removed folder should be recorded as removed, Got: %v", tombstones)
+	}
+}
+
+func conformRename(t *testing.T, s services.ServiceRepo) {
+	s.Mkdir(models.Folder{Title: "ideas/"})
+	s.Mkdir(models.Folder{Title: "archive/"})
+	s.Create(models.Note{Title: "ideas/a.md", Body: "A"})
+	s.Create(models.Note{Title: "c.md", Body: "C"})
+	s.Edit(models.Note{Title: "ideas/a.md", Body: "A2"})
+
+	rename := models.EditNode{Current: models.Node{Title: "ideas"}, New: models.Node{Title: "archive/ideas"}}
+	if err := s.Rename(rename); err != nil {
+		t.Fatalf("Rename returned an error: %v", err)
+	}
+
+	if a, err := s.View(models.Note{Title: "archive/ideas/a.md"}); err != nil || a.Body != "A2" {
+		t.Errorf("Rename should move sub nodes of folder, Got: %v (%v)", a, err)
+	}
+
+	if exists, _ := s.IsNodeExists(models.Node{Title: "ideas/"}); exists {
+		t.Errorf("Rename should remove the old folder")
+	}
+
+	if history, _ := services.History(s, "archive/ideas/a.md"); len(history) != 1 {
+		t.Errorf("Rename should move history of sub nodes, Got: %v", history)
+	}
+
+	note := models.EditNode{Current: models.Node{Title: "archive/ideas/a.md"}, New: models.Node{Title: "b.md"}}
+	if err := s.Rename(note); err != nil {
+		t.Fatalf("Rename returned an error: %v", err)
+	}
+
+	if history, _ := services.History(s, "b.md"); len(history) != 1 {
+		t.Errorf("Rename should move history of note, Got: %v", history)
+	}
+
+	existing := models.EditNode{Current: models.Node{Title: "b.md"}, New: models.Node{Title: "c.md"}}
+	if err := s.Rename(existing); err == nil {
+		t.Errorf("Rename should not overwrite existing nodes")
+	}
+
+	invalid := models.EditNode{Current: models.Node{Title: "archive"}, New: models.Node{Title: "archive/ideas/archive"}}
+	if err := s.Rename(invalid); err == nil {
+		t.Errorf("Rename should fail for moving folder into itself")
+	}
+}
+
+func conformMigrate(t *testing.T, s services.ServiceRepo) {
+	local := newTestLocalService(t)
+
+	local.Mkdir(models.Folder{Title: "ideas/"})
+	local.Create(models.Note{Title: "ideas/a.md", Body: "A"})
+	s.Create(models.Note{Title: "b.md", Body: "B"})
+
+	migrated, errs := local.Migrate(s)
+	if len(errs) > 0 || len(migrated) != 2 {
+		t.Fatalf("Migrate sum was different: Migrated: %v | Errors: %v", titles(migrated), errs)
+	}
+
+	if plan, _ := services.PlanTransfer("push", local, s, false); len(plan.Changes) != 0 {
+		t.Errorf("Service should be same as local after migrating, Got: %v", planSummary(plan))
+	}
+
+	local.Create(models.Note{Title: "c.md", Body: "C"})
+	if pushed, errs := local.Push(s); len(errs) > 0 || len(pushed) != 1 {
+		t.Errorf("Push sum was different: Pushed: %v | Errors: %v", titles(pushed), errs)
+	}
+
+	other := newTestLocalService(t)
+	if fetched, errs := other.Fetch(s); len(errs) > 0 || len(fetched) != 3 {
+		t.Errorf("Fetch sum was different: Fetched: %v | Errors: %v", titles(fetched), errs)
+	}
+
+	cleared, errs := s.ClearNodes()
+	if len(errs) > 0 || len(cleared) != 3 {
+		t.Errorf("ClearNodes sum was different: Cleared: %v | Errors: %v", titles(cleared), errs)
+	}
+}
+
+func conformTrash(t *testing.T, s services.ServiceRepo) {
+	s.Mkdir(models.Folder{Title: "ideas/"})
+	s.Create(models.Note{Title: "ideas/a.md", Body: "A"})
+	s.Create(models.Note{Title: "b.md", Body: "B"})
+
+	for _, title := range []string{"ideas/", "b.md"} {
+		if err := services.MoveToTrash(s, models.Node{Title: title}); err != nil {
+			t.Fatalf("MoveToTrash returned an error: %v", err)
+		}
+	}
+
+	if trash, _ := services.TrashList(s); len(trash) != 2 {
+		t.Errorf("TrashList should list trashed nodes, Got: %v", trash)
+	}
+
+	if _, err := services.RestoreTrash(s, "ideas/"); err != nil {
+		t.Fatalf("RestoreTrash returned an error: %v", err)
+	}
+
+	if a, err := s.View(models.Note{Title: "ideas/a.md"}); err != nil || a.Body != "A" {
+		t.Errorf("Restored note sum was different: Got: %v (%v)", a, err)
+	}
+
+	if trash, _ := services.TrashList(s); len(trash) != 1 || trash[0].Title != "b.md" {
+		t.Errorf("Restored entry should be dropped from trash, Got: %v", trash)
+	}
+}
+
+func conformSecrets(t *testing.T, s services.ServiceRepo) {
+	settings := s.StateConfig()
+	settings.WebDAVPassword, settings.HTTPToken = "secret", "secret"
+
+	if err := s.WriteSettings(settings); err != nil {
+		t.Fatalf("WriteSettings returned an error: %v", err)
+	}
+
+	got, err := s.Settings(nil)
+	if err != nil {
+		t.Fatalf("Settings returned an error: %v", err)
+	}
+
+	if len(got.WebDAVPassword) > 0 || len(got.HTTPToken) > 0 {
+		t.Errorf("Settings of remote shouldn't keep secrets, Got: %v", got.ToString())
+	}
+}
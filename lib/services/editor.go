@@ -0,0 +1,86 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/pkg"
+)
+
+// editViaTemp writes [body] to a temporary file (with [ext] extension), opens it via editor,
+// and returns the edited body.
+//
+// The temporary file is readable only by current user, and removed right after editing.
+func editViaTemp(body, ext string, stdargs models.StdArgs, settings models.Settings) (string, error) {
+	tmp, err := os.CreateTemp("", "notya-*"+ext)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.WriteString(body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	if err := pkg.OpenViaEditor(tmp.Name(), stdargs, settings); err != nil {
+		return "", err
+	}
+
+	edited, err := pkg.ReadBody(tmp.Name())
+	if err != nil {
+		return "", err
+	}
+
+	return *edited, nil
+}
+
+// openViaTemp opens the body of note (viewed via [s]) via editor,
+// and saves the edited body back via [s], if it was changed.
+//
+// Used by services, which don't keep notes as local files.
+func openViaTemp(s ServiceRepo, stdargs models.StdArgs, node models.Node) error {
+	note, err := s.View(node.ToNote())
+	if err != nil {
+		return err
+	}
+
+	edited, err := editViaTemp(note.Body, filepath.Ext(note.Title), stdargs, s.StateConfig())
+	if err != nil || edited == note.Body {
+		return err
+	}
+
+	_, err = s.Edit(models.Note{Title: note.Title, Body: edited})
+	return err
+}
+
+// openSettingsViaTemp opens settings of [s] via editor,
+// and overwrites settings of [s], if they were updated.
+func openSettingsViaTemp(s ServiceRepo, stdargs models.StdArgs) error {
+	settings, err := s.Settings(nil)
+	if err != nil {
+		return err
+	}
+
+	edited, err := editViaTemp(settings.ToString(), filepath.Ext(models.SettingsName), stdargs, s.StateConfig())
+	if err != nil {
+		return err
+	}
+
+	if updated := models.DecodeSettings(edited); pkg.IsSettingsUpdated(*settings, updated) {
+		return s.WriteSettings(updated)
+	}
+
+	return nil
+}
@@ -0,0 +1,638 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	"github.com/insolite-dev/notya/assets"
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/pkg"
+)
+
+// EncryptedService is a wrapper of service repo, that encrypts bodies of notes
+// before they reach the wrapped service, and decrypts them on reading.
+//
+//	╭─────────────────────╮  plain  ╭───────────────────╮  sealed  ╭─────────────────╮
+//	│ Interface Commands  │ ──────▶ │ Encrypted Service │ ───────▶ │ Wrapped Service │
+//	╰─────────────────────╯         ╰───────────────────╯          ╰─────────────────╯
+//
+// Hidden notes (like tombstones) are kept plain, and plain bodies of notes written before
+// enabling encryption are read as they are, until they're encrypted by `notya encrypt --all`.
+//
+// Search index isn't kept for encrypted notes, since it'd leak their contents.
+type EncryptedService struct {
+	ServiceRepo
+
+	Stdargs models.StdArgs
+
+	// Unlock returns the cipher of encryption. It's called only when a body
+	// has to be encrypted or decrypted, so passphrase is asked only if it's needed.
+	Unlock func() (*pkg.Cipher, error)
+
+	cipher *pkg.Cipher
+}
+
+// Mark [EncryptedService] as [ServiceRepo], [Trasher], [Versioner], [Committer], [Indexer] and [FullTextSearcher].
+var (
+	_ ServiceRepo      = &EncryptedService{}
+	_ Trasher          = &EncryptedService{}
+	_ Versioner        = &EncryptedService{}
+	_ Committer        = &EncryptedService{}
+	_ Indexer          = &EncryptedService{}
+	_ FullTextSearcher = &EncryptedService{}
+)
+
+// NewEncryptedService wraps [s] by encryption, that's unlocked via [unlock].
+func NewEncryptedService(s ServiceRepo, stdargs models.StdArgs, unlock func() (*pkg.Cipher, error)) *EncryptedService {
+	return &EncryptedService{ServiceRepo: s, Stdargs: stdargs, Unlock: unlock}
+}
+
+// Cipher unlocks the encryption once, and caches its cipher.
+func (e *EncryptedService) Cipher() (*pkg.Cipher, error) {
+	if e.cipher != nil {
+		return e.cipher, nil
+	}
+
+	c, err := e.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	e.cipher = c
+	return c, nil
+}
+
+// Encrypt seals [body] of note at [title]. Hidden notes are kept plain.
+// Bodies given to encrypt are always plain, so they're encrypted even if they look like encrypted ones.
+func (e *EncryptedService) Encrypt(title, body string) (string, error) {
+	if pkg.IsIgnorable(filepath.Base(title), models.NotyaIgnoreFiles) {
+		return body, nil
+	}
+
+	c, err := e.Cipher()
+	if err != nil {
+		return "", err
+	}
+
+	return c.Encrypt(title, body)
+}
+
+// Decrypt opens stored [body] of note at [title], if it's encrypted.
+func (e *EncryptedService) Decrypt(title, body string) (string, error) {
+	if !pkg.IsEncrypted(body) {
+		return body, nil
+	}
+
+	c, err := e.Cipher()
+	if err != nil {
+		return "", err
+	}
+
+	return c.Decrypt(title, body)
+}
+
+// isSealed checks if stored [body] of note at [title] is sealed, by opening it.
+// So plain bodies, that only look like encrypted ones, aren't taken as sealed.
+func (e *EncryptedService) isSealed(title, body string) bool {
+	if strings.HasPrefix(body, pkg.VaultPrefix) {
+		return true
+	}
+
+	_, err := e.Decrypt(title, body)
+	return pkg.IsEncrypted(body) && err == nil
+}
+
+// Open decrypts the note to a temporary file, opens it via editor,
+// and saves the edited body back encrypted. Folders are opened as they are.
+func (e *EncryptedService) Open(node models.Node) error {
+	if node.IsFolder() || strings.HasSuffix(node.Title, "/") {
+		return e.ServiceRepo.Open(node)
+	}
+
+	return openViaTemp(e, e.Stdargs, node)
+}
+
+// renameSealed renames the node via wrapped service [r] of wrapper [w], and re-seals its [nodes]
+// (collected via [nodesAt]), since sealed bodies are bound to titles of their notes.
+//
+// Bodies are opened via [open] at their current titles, and sealed via [seal] at their new titles
+// before anything is moved, so a note that can't be re-sealed fails the whole rename.
+// History of re-sealed notes is re-saved via [w], at their new titles.
+func renameSealed(w, r ServiceRepo, editNode models.EditNode, nodes []models.Node, open, seal func(title, body string) (string, error)) error {
+	current, renamed := strings.Trim(editNode.Current.Title, "/"), strings.Trim(editNode.New.Title, "/")
+	versioner, _ := w.(Versioner)
+
+	resealed := []models.Note{}
+	histories := map[string]models.History{}
+
+	for _, n := range nodes {
+		if n.IsFolder() {
+			continue
+		}
+
+		body, err := open(n.Title, n.Body)
+		if err != nil {
+			return assets.CannotDoSth("decrypt", n.Title, err)
+		}
+
+		title := renamed + strings.TrimPrefix(n.Title, current)
+
+		sealed, err := seal(title, body)
+		if err != nil {
+			return err
+		} else if sealed == n.Body {
+			continue // kept plain, like hidden notes and notes out of vaults.
+		}
+
+		resealed = append(resealed, models.Note{Title: title, Body: sealed})
+		if versioner == nil {
+			continue
+		}
+
+		if history, err := versioner.Versions(n.Title); err == nil {
+			histories[title] = history
+		}
+	}
+
+	if err := r.Rename(editNode); err != nil {
+		return err
+	}
+
+	for _, note := range resealed {
+		if _, err := r.Edit(note); err != nil {
+			return assets.CannotDoSth("re-seal", note.Title, err)
+		}
+
+		history, ok := histories[note.Title]
+		if !ok {
+			continue
+		}
+
+		for i := range history {
+			history[i].Title = note.Title
+		}
+
+		if err := rewriteHistory(versioner, note.Title, history); err != nil {
+			return assets.CannotDoSth("re-seal history of", note.Title, err)
+		}
+	}
+
+	return nil
+}
+
+// Rename renames the node at wrapped service, and re-encrypts its notes by their new titles.
+func (e *EncryptedService) Rename(editNode models.EditNode) error {
+	_, nodes, err := nodesAt(e.ServiceRepo, editNode.Current)
+	if err != nil {
+		return err
+	}
+
+	return renameSealed(e, e.ServiceRepo, editNode, nodes, e.Decrypt, e.Encrypt)
+}
+
+// Create encrypts body of [note], and creates it at wrapped service.
+func (e *EncryptedService) Create(note models.Note) (*models.Note, error) {
+	body := note.Body
+
+	sealed, err := e.Encrypt(note.Title, note.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	note.Body = sealed
+	created, err := e.ServiceRepo.Create(note)
+	if err != nil {
+		return nil, err
+	}
+
+	created.Body = body
+	return created, nil
+}
+
+// View reads the note from wrapped service, and decrypts its body.
+func (e *EncryptedService) View(note models.Note) (*models.Note, error) {
+	n, err := e.ServiceRepo.View(note)
+	if err != nil {
+		return nil, err
+	}
+
+	if n.Body, err = e.Decrypt(note.Title, n.Body); err != nil {
+		return nil, assets.CannotDoSth("view", note.Title, err)
+	}
+
+	return n, nil
+}
+
+// Edit encrypts body of [note], and overwrites it at wrapped service.
+//
+// Each encryption uses a random nonce, so if the decrypted body isn't changed,
+// its current sealed body is re-used. Then, wrapped service doesn't see a change.
+func (e *EncryptedService) Edit(note models.Note) (*models.Note, error) {
+	body := note.Body
+
+	sealed, err := e.Encrypt(note.Title, note.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if current, err := e.ServiceRepo.View(note); err == nil && pkg.IsEncrypted(current.Body) {
+		if plain, err := e.Decrypt(note.Title, current.Body); err == nil && plain == body {
+			sealed = current.Body
+		}
+	}
+
+	note.Body = sealed
+	edited, err := e.ServiceRepo.Edit(note)
+	if err != nil {
+		return nil, err
+	}
+
+	edited.Body = body
+	return edited, nil
+}
+
+// Copy writes decrypted body of note to machine's clipboard.
+func (e *EncryptedService) Copy(note models.Note) error {
+	n, err := e.View(note)
+	if err != nil {
+		return err
+	}
+
+	return clipboard.WriteAll(n.Body)
+}
+
+// Cut writes decrypted body of note to machine's clipboard, and removes the note.
+func (e *EncryptedService) Cut(note models.Note) (*models.Note, error) {
+	n, err := e.View(note)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := clipboard.WriteAll(n.Body); err != nil {
+		return nil, err
+	}
+
+	return n, e.ServiceRepo.Remove(note.ToNode())
+}
+
+// GetAll gets all nodes of wrapped service, with decrypted bodies.
+// Size, hash and tags of notes are re-generated from their decrypted bodies.
+func (e *EncryptedService) GetAll(additional, typ string, ignore []string) ([]models.Node, []string, error) {
+	nodes, titles, err := e.ServiceRepo.GetAll(additional, typ, ignore)
+	if err != nil {
+		return nodes, titles, err
+	}
+
+	for i, n := range nodes {
+		if !pkg.IsEncrypted(n.Body) {
+			continue
+		}
+
+		title := joinTitle(additional, n.Title)
+
+		body, err := e.Decrypt(title, n.Body)
+		if err != nil {
+			return nil, nil, assets.CannotDoSth("decrypt", title, err)
+		}
+
+		nodes[i].Body = body
+		nodes[i].Size, nodes[i].Hash = int64(len(body)), pkg.HashBody(body)
+		nodes[i].FillTags()
+	}
+
+	return nodes, titles, nil
+}
+
+// Fetch clones nodes from [remote] service, by encrypting them.
+func (e *EncryptedService) Fetch(remote ServiceRepo) ([]models.Node, []error) {
+	return transfer("fetch", remote, e)
+}
+
+// Push uploads decrypted nodes to [remote] service.
+func (e *EncryptedService) Push(remote ServiceRepo) ([]models.Node, []error) {
+	return transfer("push", e, remote)
+}
+
+// Migrate overwrites all notes of [remote] service with decrypted nodes.
+func (e *EncryptedService) Migrate(remote ServiceRepo) ([]models.Node, []error) {
+	return Migrate(e, remote)
+}
+
+// EncryptAll re-writes each plain note of wrapped service as encrypted.
+// Earlier versions of notes and entries of trash are re-written encrypted too,
+// so no plain body is left behind.
+func (e *EncryptedService) EncryptAll() ([]models.Node, []error) {
+	nodes, _, err := e.ServiceRepo.GetAll("", "file", models.NotyaIgnoreFiles)
+	if err != nil && err != assets.EmptyWorkingDirectory {
+		return nil, []error{err}
+	}
+
+	var res []models.Node
+	var errs []error
+
+	for _, n := range nodes {
+		history, _ := e.Versions(n.Title)
+
+		if !e.isSealed(n.Title, n.Body) {
+			if _, err := e.Edit(n.ToNote()); err != nil {
+				errs = append(errs, assets.CannotDoSth("encrypt", n.Title, err))
+				continue
+			}
+
+			res = append(res, n)
+		}
+
+		// Re-write the history as it was before encrypting, so
+		// the plain version kept by the edit above is dropped too.
+		if err := rewriteHistory(e, n.Title, history); err != nil {
+			errs = append(errs, assets.CannotDoSth("encrypt history of", n.Title, err))
+		}
+	}
+
+	if err := e.rewriteTrash(); err != nil {
+		errs = append(errs, assets.CannotDoSth("encrypt", "trash", err))
+	}
+
+	return res, errs
+}
+
+// rewriteHistory replaces history of note at [title] with [history], by re-saving its versions via [v].
+func rewriteHistory(v Versioner, title string, history models.History) error {
+	if err := v.DropVersions(title); err != nil {
+		return err
+	}
+
+	sort.Slice(history, func(i, j int) bool { return history[i].Rev < history[j].Rev })
+	for _, version := range history {
+		if err := v.SaveVersion(version); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rewriteTrash re-puts those entries of trash, that include plain bodies.
+// Entries are re-put decrypted, so encrypted bodies of them aren't encrypted twice.
+func (e *EncryptedService) rewriteTrash() error {
+	trasher, ok := e.ServiceRepo.(Trasher)
+	if !ok {
+		return nil
+	}
+
+	trash, err := trasher.TrashEntries()
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range trash {
+		plain := false
+		for i, n := range entry.Nodes {
+			if !n.IsFolder() && len(n.Body) > 0 && !e.isSealed(n.Title, n.Body) {
+				plain = true
+				continue
+			}
+
+			if entry.Nodes[i].Body, err = e.Decrypt(n.Title, n.Body); err != nil {
+				return assets.CannotDoSth("decrypt", n.Title, err)
+			}
+		}
+
+		if !plain {
+			continue
+		}
+
+		if err := trasher.DropTrash(entry.ID); err != nil {
+			return err
+		}
+
+		if err := e.PutTrash(entry); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PutTrash encrypts bodies of [entry]'s notes, and keeps it at trash of wrapped service.
+func (e *EncryptedService) PutTrash(entry models.TrashEntry) error {
+	trasher, ok := e.ServiceRepo.(Trasher)
+	if !ok {
+		return assets.TrashNotAvailable
+	}
+
+	nodes := make([]models.Node, len(entry.Nodes))
+	for i, n := range entry.Nodes {
+		if !n.IsFolder() {
+			sealed, err := e.Encrypt(n.Title, n.Body)
+			if err != nil {
+				return err
+			}
+
+			n.Body, n.Hash, n.Tags = sealed, "", nil
+		}
+
+		nodes[i] = n
+	}
+
+	entry.Nodes = nodes
+	return trasher.PutTrash(entry)
+}
+
+// TrashEntries reads entries of wrapped service's trash, with decrypted bodies.
+func (e *EncryptedService) TrashEntries() (models.Trash, error) {
+	trasher, ok := e.ServiceRepo.(Trasher)
+	if !ok {
+		return nil, assets.TrashNotAvailable
+	}
+
+	trash, err := trasher.TrashEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range trash {
+		for j, n := range trash[i].Nodes {
+			if trash[i].Nodes[j].Body, err = e.Decrypt(n.Title, n.Body); err != nil {
+				return nil, assets.CannotDoSth("decrypt", n.Title, err)
+			}
+		}
+	}
+
+	return trash, nil
+}
+
+// DropTrash removes the entry of [id] from wrapped service's trash.
+func (e *EncryptedService) DropTrash(id string) error {
+	trasher, ok := e.ServiceRepo.(Trasher)
+	if !ok {
+		return assets.TrashNotAvailable
+	}
+
+	return trasher.DropTrash(id)
+}
+
+// SaveVersion encrypts body of [version], and saves it at wrapped service's history.
+func (e *EncryptedService) SaveVersion(version models.Version) error {
+	versioner, ok := e.ServiceRepo.(Versioner)
+	if !ok {
+		return assets.HistoryNotAvailable
+	}
+
+	sealed, err := e.Encrypt(version.Title, version.Body)
+	if err != nil {
+		return err
+	}
+
+	version.Body, version.Hash = sealed, ""
+	return versioner.SaveVersion(version)
+}
+
+// DropVersions removes the whole history of note at [title] from wrapped service.
+func (e *EncryptedService) DropVersions(title string) error {
+	versioner, ok := e.ServiceRepo.(Versioner)
+	if !ok {
+		return assets.HistoryNotAvailable
+	}
+
+	return versioner.DropVersions(title)
+}
+
+// Versions reads history of note at [title] from wrapped service, with decrypted bodies.
+// Versions are saved by wrapped service as they were stored, so their size and hash are
+// re-generated from decrypted bodies.
+func (e *EncryptedService) Versions(title string) (models.History, error) {
+	versioner, ok := e.ServiceRepo.(Versioner)
+	if !ok {
+		return nil, assets.HistoryNotAvailable
+	}
+
+	history, err := versioner.Versions(title)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, v := range history {
+		body, err := e.Decrypt(v.Title, v.Body)
+		if err != nil {
+			return nil, assets.CannotDoSth("decrypt", title, err)
+		}
+
+		history[i].Body, history[i].Size, history[i].Hash = body, int64(len(body)), pkg.HashBody(body)
+	}
+
+	return history, nil
+}
+
+// EncryptionCheckValue is the known value, that's sealed by encryption key to verify passphrases.
+// It's sealed as the body of [models.SettingsName], since it's kept at settings.
+const EncryptionCheckValue = "notya"
+
+// EnableEncryption sets up encryption of [mode] at [settings], with a key derived from [passphrase].
+// If encryption was set up before, passphrase is verified instead of generating a new salt.
+func EnableEncryption(settings models.Settings, mode, passphrase string) (models.Settings, *pkg.Cipher, error) {
+	if mode != models.EncryptAll && mode != models.EncryptRemote {
+		return settings, nil, assets.InvalidEncryption
+	}
+
+	if len(settings.EncryptionSalt) > 0 {
+		c, err := UnlockEncryption(settings, passphrase)
+		if err != nil {
+			return settings, nil, err
+		}
+
+		settings.Encryption = mode
+		return settings, c, nil
+	}
+
+	if len(passphrase) == 0 {
+		return settings, nil, assets.EmptyPassphrase
+	}
+
+	salt, err := pkg.NewSalt()
+	if err != nil {
+		return settings, nil, err
+	}
+
+	key, err := pkg.DeriveKey(passphrase, salt)
+	if err != nil {
+		return settings, nil, err
+	}
+
+	c, err := pkg.NewCipher(key)
+	if err != nil {
+		return settings, nil, err
+	}
+
+	check, err := c.Encrypt(models.SettingsName, EncryptionCheckValue)
+	if err != nil {
+		return settings, nil, err
+	}
+
+	settings.Encryption, settings.EncryptionSalt, settings.EncryptionCheck = mode, salt, check
+	return settings, c, nil
+}
+
+// UnlockEncryption derives the encryption key of [settings] from [passphrase],
+// and verifies it by the sealed check value of settings.
+func UnlockEncryption(settings models.Settings, passphrase string) (*pkg.Cipher, error) {
+	key, err := pkg.DeriveKey(passphrase, settings.EncryptionSalt)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := pkg.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if check, err := c.Decrypt(models.SettingsName, settings.EncryptionCheck); err != nil || check != EncryptionCheckValue {
+		return nil, assets.WrongPassphrase
+	}
+
+	return c, nil
+}
+
+// CommitPlan encrypts bodies of notes of [plan], and commits it via wrapped service.
+// Snapshot of delete changes is encrypted as well, so a rollback never writes plain bodies.
+func (e *EncryptedService) CommitPlan(plan models.Plan) ([]models.Node, error) {
+	return commitSealed(e.ServiceRepo, plan, func(n models.Node) (string, error) { return e.Encrypt(n.Title, n.Body) })
+}
+
+// RebuildIndex re-indexes all notes of wrapped service from scratch.
+func (e *EncryptedService) RebuildIndex() (*models.Index, error) {
+	indexer, ok := e.ServiceRepo.(Indexer)
+	if !ok {
+		return nil, assets.IndexNotAvailable
+	}
+
+	return indexer.RebuildIndex()
+}
+
+// RefreshIndex re-indexes changed notes of wrapped service.
+func (e *EncryptedService) RefreshIndex() (*models.Index, error) {
+	indexer, ok := e.ServiceRepo.(Indexer)
+	if !ok {
+		return nil, assets.IndexNotAvailable
+	}
+
+	return indexer.RefreshIndex()
+}
+
+// FullTextSearch ranks notes of [query] via search engine of wrapped service.
+func (e *EncryptedService) FullTextSearch(query string) ([]models.IndexHit, error) {
+	searcher, ok := e.ServiceRepo.(FullTextSearcher)
+	if !ok {
+		return nil, assets.IndexNotAvailable
+	}
+
+	return searcher.FullTextSearch(query)
+}
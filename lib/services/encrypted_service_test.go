@@ -0,0 +1,196 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/insolite-dev/notya/assets"
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/lib/services"
+	"github.com/insolite-dev/notya/pkg"
+)
+
+func newTestEncryptedService(t *testing.T) (*services.EncryptedService, *services.LocalService) {
+	local := newTestLocalService(t)
+
+	encrypted := services.NewEncryptedService(local, models.StdArgs{}, func() (*pkg.Cipher, error) {
+		return pkg.NewCipher(bytes.Repeat([]byte{1}, pkg.KeyLength))
+	})
+
+	return encrypted, local
+}
+
+func TestEncryptedService(t *testing.T) {
+	encrypted, local := newTestEncryptedService(t)
+
+	encrypted.Mkdir(models.Folder{Title: "ideas/"})
+	if _, err := encrypted.Create(models.Note{Title: "ideas/todo.md", Body: "first #work\n"}); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, _ := local.View(models.Note{Title: "ideas/todo.md"})
+	if !pkg.IsEncrypted(stored.Body) {
+		t.Errorf("Create should keep the body encrypted, Got: %v", stored.Body)
+	}
+
+	viewed, err := encrypted.View(models.Note{Title: "ideas/todo.md"})
+	if err != nil || viewed.Body != "first #work\n" {
+		t.Errorf("View sum was different: Want: %v | Got: %v (%v)", "first #work\n", viewed, err)
+	}
+
+	// Unchanged bodies don't change the stored (sealed) body.
+	encrypted.Edit(models.Note{Title: "ideas/todo.md", Body: "first #work\n"})
+	if again, _ := local.View(models.Note{Title: "ideas/todo.md"}); again.Body != stored.Body {
+		t.Errorf("Edit should re-use the sealed body of unchanged note: Want: %v | Got: %v", stored.Body, again.Body)
+	}
+
+	encrypted.Edit(models.Note{Title: "ideas/todo.md", Body: "second\n"})
+
+	history, err := services.History(encrypted, "ideas/todo.md")
+	if err != nil || len(history) != 1 || history[0].Body != "first #work\n" {
+		t.Errorf("History sum was different: Got: %v (%v)", history, err)
+	}
+
+	if raw, _ := local.Versions("ideas/todo.md"); len(raw) != 1 || !pkg.IsEncrypted(raw[0].Body) {
+		t.Errorf("Versions should be kept encrypted, Got: %v", raw)
+	}
+
+	nodes, _, err := encrypted.GetAll("", "file", models.NotyaIgnoreFiles)
+	if err != nil || len(nodes) != 1 || nodes[0].Body != "second\n" || nodes[0].Size != 7 {
+		t.Errorf("GetAll should decrypt bodies, Got: %v (%v)", nodes, err)
+	}
+
+	if err := services.MoveToTrash(encrypted, models.Node{Title: "ideas/todo.md"}); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, _ := local.TrashEntries()
+	if len(raw) != 1 || !pkg.IsEncrypted(raw[0].Nodes[0].Body) {
+		t.Errorf("Trash should keep bodies encrypted, Got: %v", raw)
+	}
+
+	if _, err := services.RestoreTrash(encrypted, "ideas/todo.md"); err != nil {
+		t.Fatal(err)
+	}
+
+	if restored, err := encrypted.View(models.Note{Title: "ideas/todo.md"}); err != nil || restored.Body != "second\n" {
+		t.Errorf("RestoreTrash sum was different: Want: %v | Got: %v (%v)", "second\n", restored, err)
+	}
+}
+
+func TestEncryptedRename(t *testing.T) {
+	encrypted, local := newTestEncryptedService(t)
+
+	encrypted.Mkdir(models.Folder{Title: "ideas/"})
+	encrypted.Create(models.Note{Title: "ideas/todo.md", Body: "first\n"})
+	encrypted.Edit(models.Note{Title: "ideas/todo.md", Body: "second\n"})
+	encrypted.Create(models.Note{Title: "look.md", Body: pkg.EncryptedPrefix + "plain"})
+
+	// Plain bodies are encrypted, even if they look like encrypted ones.
+	if stored, _ := local.View(models.Note{Title: "look.md"}); stored.Body == pkg.EncryptedPrefix+"plain" {
+		t.Errorf("Create should encrypt plain bodies, Got: %v", stored.Body)
+	}
+
+	// Sealed bodies are bound to titles of their notes.
+	stored, _ := local.View(models.Note{Title: "ideas/todo.md"})
+	local.Edit(models.Note{Title: "look.md", Body: stored.Body})
+	if _, err := encrypted.View(models.Note{Title: "look.md"}); err == nil {
+		t.Errorf("View should fail for a sealed body of another note")
+	}
+
+	err := encrypted.Rename(models.EditNode{Current: models.Node{Title: "ideas"}, New: models.Node{Title: "notes"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if viewed, err := encrypted.View(models.Note{Title: "notes/todo.md"}); err != nil || viewed.Body != "second\n" {
+		t.Errorf("Rename should re-seal moved notes: Want: %v | Got: %v (%v)", "second\n", viewed, err)
+	}
+
+	if history, err := encrypted.Versions("notes/todo.md"); err != nil || len(history) != 1 || history[0].Body != "first\n" {
+		t.Errorf("Rename should re-seal history of moved notes, Got: %v (%v)", history, err)
+	}
+}
+
+func TestEncryptAll(t *testing.T) {
+	encrypted, local := newTestEncryptedService(t)
+
+	local.Create(models.Note{Title: "plain.md", Body: "first\n"})
+	local.Edit(models.Note{Title: "plain.md", Body: "second\n"})
+	local.Create(models.Note{Title: "removed.md", Body: "removed\n"})
+	services.MoveToTrash(local, models.Node{Title: "removed.md"})
+	encrypted.Create(models.Note{Title: "sealed.md", Body: "sealed\n"})
+
+	res, errs := encrypted.EncryptAll()
+	if len(errs) != 0 || len(res) != 1 || res[0].Title != "plain.md" {
+		t.Fatalf("EncryptAll sum was different: Got: %v (%v)", res, errs)
+	}
+
+	for _, title := range []string{"plain.md", "sealed.md"} {
+		if stored, _ := local.View(models.Note{Title: title}); !pkg.IsEncrypted(stored.Body) {
+			t.Errorf("EncryptAll should encrypt %v, Got: %v", title, stored.Body)
+		}
+	}
+
+	raw, _ := local.Versions("plain.md")
+	if len(raw) != 1 || raw[0].Rev != 1 || !pkg.IsEncrypted(raw[0].Body) {
+		t.Errorf("EncryptAll should re-write history encrypted, Got: %v", raw)
+	}
+
+	if history, _ := encrypted.Versions("plain.md"); len(history) != 1 || history[0].Body != "first\n" {
+		t.Errorf("Versions sum was different: Want: %v | Got: %v", "first\n", history)
+	}
+
+	trash, _ := local.TrashEntries()
+	if len(trash) != 1 || !pkg.IsEncrypted(trash[0].Nodes[0].Body) {
+		t.Errorf("EncryptAll should re-write trash encrypted, Got: %v", trash)
+	}
+}
+
+func TestEnableEncryption(t *testing.T) {
+	settings := models.InitSettings(t.TempDir())
+
+	if _, _, err := services.EnableEncryption(settings, "local", "secret"); err != assets.InvalidEncryption {
+		t.Errorf("EnableEncryption error was different: Want: %v | Got: %v", assets.InvalidEncryption, err)
+	}
+
+	if _, _, err := services.EnableEncryption(settings, models.EncryptAll, ""); err != assets.EmptyPassphrase {
+		t.Errorf("EnableEncryption error was different: Want: %v | Got: %v", assets.EmptyPassphrase, err)
+	}
+
+	enabled, c, err := services.EnableEncryption(settings, models.EncryptAll, "secret")
+	if err != nil || c == nil || enabled.Encryption != models.EncryptAll || len(enabled.EncryptionSalt) == 0 {
+		t.Fatalf("EnableEncryption sum was different: Got: %v (%v)", enabled, err)
+	}
+
+	sealed, _ := c.Encrypt("todo.md", "body")
+
+	unlocked, err := services.UnlockEncryption(enabled, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if body, err := unlocked.Decrypt("todo.md", sealed); err != nil || body != "body" {
+		t.Errorf("UnlockEncryption should derive the same key: Want: %v | Got: %v (%v)", "body", body, err)
+	}
+
+	if _, err := services.UnlockEncryption(enabled, "wrong"); err != assets.WrongPassphrase {
+		t.Errorf("UnlockEncryption error was different: Want: %v | Got: %v", assets.WrongPassphrase, err)
+	}
+
+	// Changing the mode keeps the salt, and verifies the passphrase.
+	if _, _, err := services.EnableEncryption(enabled, models.EncryptRemote, "wrong"); err != assets.WrongPassphrase {
+		t.Errorf("EnableEncryption error was different: Want: %v | Got: %v", assets.WrongPassphrase, err)
+	}
+
+	remote, _, err := services.EnableEncryption(enabled, models.EncryptRemote, "secret")
+	if err != nil || remote.Encryption != models.EncryptRemote || remote.EncryptionSalt != enabled.EncryptionSalt {
+		t.Errorf("EnableEncryption sum was different: Got: %v (%v)", remote, err)
+	}
+}
@@ -8,6 +8,7 @@ package services
 
 import (
 	"context"
+	"fmt"
 	"sort"
 	"strings"
 	"time"
@@ -41,8 +42,14 @@ type FirebaseService struct {
 	FireStore *firestore.Client
 }
 
-// Mark [FirebaseService] as [ServiceRepo].
-var _ ServiceRepo = &FirebaseService{}
+// Mark [FirebaseService] as [ServiceRepo] and [Committer].
+var (
+	_ ServiceRepo = &FirebaseService{}
+	_ Committer   = &FirebaseService{}
+)
+
+// MaxBatchWrites is the maximum amount of writes, that a single firestore batch can include.
+const MaxBatchWrites = 500
 
 // NewFirebaseService creates new firebase service by given arguments.
 func NewFirebaseService(stdargs models.StdArgs, ls ServiceRepo) *FirebaseService {
@@ -189,9 +196,38 @@ func (s *FirebaseService) Init(settings *models.Settings) error {
 
 	s.Config = *config // set remote settigns data instead of local.
 
+	// Tags of old notes are filled once. If it fails, it's tried again at next run,
+	// and tags are queried on client side until then.
+	if !s.Config.FirebaseTagsIndexed {
+		_ = s.IndexTags()
+	}
+
 	return nil
 }
 
+// IndexTags fills "tags" field of notes, that were written before tags were stored at documents,
+// and marks the settings as indexed, so [NodesByTag] queries could be run on server side.
+func (s *FirebaseService) IndexTags() error {
+	nodes, _, err := s.GetAll("", "file", models.NotyaIgnoreFiles)
+	if err != nil {
+		return err
+	}
+
+	for _, n := range nodes {
+		if len(n.Tags) > 0 || len(n.FillTags().Tags) == 0 {
+			continue
+		}
+
+		doc, _ := s.GenerateDoc(nil, n)
+		if _, err := doc.Update(s.Ctx, []firestore.Update{{Path: "tags", Value: n.Tags}}); err != nil {
+			return err
+		}
+	}
+
+	s.Config.FirebaseTagsIndexed = true
+	return s.WriteSettings(s.Config)
+}
+
 // Initializes firebase services as [s.FireApp], [s.FireAuth], and [s.FireStore].
 func (s *FirebaseService) InitFirebase() error {
 	opts := option.WithCredentialsFile(s.Config.FirebaseAccountKey)
@@ -237,12 +273,14 @@ func (s *FirebaseService) Settings(p *string) (*models.Settings, error) {
 	return &settings, nil
 }
 
-// WriteSettings overwrites settings data by given settings model.
+// WriteSettings overwrites settings data by given settings model, without its secrets.
 func (s *FirebaseService) WriteSettings(settings models.Settings) error {
 	if !settings.IsValid() {
 		return assets.InvalidSettingsData
 	}
 
+	settings = settings.WithoutSecrets()
+
 	collection := s.FireStore.Collection(s.Config.Name)
 	if _, err := collection.Doc(models.SettingsName).Set(s.Ctx, settings.ToJSON()); err != nil {
 		return err
@@ -279,7 +317,8 @@ func (s *FirebaseService) OpenSettings(settings models.Settings) error {
 		Title: title,
 		Body:  prevSettings.ToString(),
 	}
-	if _, err := s.LS.Create(note); err != nil {
+	cached, err := s.LS.Create(note)
+	if err != nil {
 		return err
 	}
 
@@ -294,8 +333,8 @@ func (s *FirebaseService) OpenSettings(settings models.Settings) error {
 		return err
 	}
 
-	// Clear cache, and skip error.
-	_ = s.LS.Remove(note.ToNode())
+	// Clear cache (without leaving a tombstone), and skip error.
+	_ = pkg.Delete(cached.GetPath(s.LS.Type()))
 
 	if pkg.IsSettingsUpdated(*prevSettings, *updatedSettings) {
 		return s.WriteSettings(*updatedSettings)
@@ -314,12 +353,14 @@ func (s *FirebaseService) Open(node models.Node) error {
 
 	splitted := strings.Split(data.Title, "/")
 	note := models.Note{Title: splitted[len(splitted)-1] + time.Now().String(), Body: data.Body}
-	if _, err := s.LS.Create(note); err != nil {
+	cached, err := s.LS.Create(note)
+	if err != nil {
 		return err
 	}
 
-	// Open via editor to edit.
-	openErr := s.LS.Open(note.ToNode())
+	// Open via editor to edit. Editor is opened directly, so local
+	// service doesn't keep versions of the temporary copy.
+	openErr := pkg.OpenViaEditor(cached.GetPath(s.LS.Type()), s.Stdargs, s.LS.StateConfig())
 	if openErr != nil {
 		return openErr
 	}
@@ -330,8 +371,8 @@ func (s *FirebaseService) Open(node models.Node) error {
 		return err
 	}
 
-	// Clear cache, and skip error.
-	_ = s.LS.Remove(updatedNote.ToNode())
+	// Clear cache (without leaving a tombstone), and skip error.
+	_ = pkg.Delete(updatedNote.GetPath(s.LS.Type()))
 
 	note = models.Note{Title: data.Title, Path: data.Path, Body: updatedNote.Body}
 	if _, err := s.Edit(note); err != nil {
@@ -361,11 +402,17 @@ func (s *FirebaseService) Remove(node models.Node) error {
 		return err
 	}
 
-	return nil
+	return RecordTombstone(s, n.Title)
 }
 
 // Rename changes reference ID of document.
+// Tombstones of moved documents are recorded at once.
 func (s *FirebaseService) Rename(editNode models.EditNode) error {
+	return batched(s, func() error { return s.rename(editNode) })
+}
+
+// rename is the implementation of [Rename], that runs in a tombstone batch.
+func (s *FirebaseService) rename(editNode models.EditNode) error {
 	current, err := s.GetDoc(editNode.Current)
 	if err != nil {
 		return err
@@ -412,7 +459,7 @@ func (s *FirebaseService) Rename(editNode models.EditNode) error {
 			newN := n
 			newN = *newN.RebuildParent(*current, updated, s.Type(), s.Config)
 
-			if err := s.Rename(models.EditNode{Current: n, New: newN}); err != nil {
+			if err := s.rename(models.EditNode{Current: n, New: newN}); err != nil {
 				// TODO: shouldn't cut the whole action for one error.
 				return err
 			}
@@ -456,6 +503,7 @@ func (s *FirebaseService) ClearNodes() ([]models.Node, []error) {
 	var res []models.Node
 	var errs []error
 
+	flush := Batch(s)
 	for _, n := range nodes {
 		if err := s.Remove(n); err != nil {
 			errs = append(errs, assets.CannotDoSth("remove", n.Title, err))
@@ -465,6 +513,10 @@ func (s *FirebaseService) ClearNodes() ([]models.Node, []error) {
 		res = append(res, n)
 	}
 
+	if err := flush(); err != nil {
+		errs = append(errs, err)
+	}
+
 	return res, errs
 }
 
@@ -482,7 +534,17 @@ func (s *FirebaseService) GetAll(additional, typ string, ignore []string) ([]mod
 		}
 	}
 
-	return s.ListDir(&collection, typ, ignore, 0)
+	nodes, titles, err := s.ListDir(&collection, typ, ignore, 0)
+
+	// Documents keep full titles of nodes, but titles are relative to [additional] folder.
+	if scope := strings.Trim(additional, "/"); len(scope) > 0 {
+		for i := range nodes {
+			nodes[i].Title = strings.TrimPrefix(nodes[i].Title, scope+"/")
+			titles[i] = nodes[i].Title
+		}
+	}
+
+	return nodes, titles, err
 }
 
 // ListDir retrieves the documents and sub-collections from a specified Firebase CollectionRef.
@@ -553,6 +615,7 @@ func (s *FirebaseService) Create(note models.Note) (*models.Note, error) {
 
 	path, _ := s.GeneratePath(nil, noteNode)
 	noteNode.UpdatePath(s.Type(), path)
+	stamp(&noteNode, time.Now().UTC())
 
 	noteDoc, _ := s.GenerateDoc(nil, noteNode)
 	if _, err := noteDoc.Create(s.Ctx, noteNode.ToJSON()); err != nil {
@@ -564,7 +627,7 @@ func (s *FirebaseService) Create(note models.Note) (*models.Note, error) {
 	}
 
 	modifiedNote := noteNode.ToNote()
-	return &modifiedNote, nil
+	return &modifiedNote, ClearTombstone(s, modifiedNote.Title)
 }
 
 // View, gets the note document from note's path.
@@ -587,6 +650,11 @@ func (s *FirebaseService) View(note models.Note) (*models.Note, error) {
 		return nil, err
 	}
 
+	// Folders are kept as documents too, but they can't be viewed as notes.
+	if docSnapshot.Data()["typ"] == string(models.FOLDER) {
+		return nil, assets.NotExists(path, "File")
+	}
+
 	var model models.Note
 	mapstructure.Decode(docSnapshot.Data(), &model)
 
@@ -602,6 +670,18 @@ func (s *FirebaseService) Edit(note models.Note) (*models.Note, error) {
 	path, _ := s.GeneratePath(nil, noteNode)
 	noteNode.UpdatePath(s.Type(), path)
 
+	// Keep the creation time and the previous body of existing document.
+	var previous *models.Version
+	if current, err := s.GetDoc(noteNode); err == nil {
+		noteNode.Created = current.Created
+
+		if current.IsFile() {
+			version := NewVersion(note.Title, current.Body, current.Updated)
+			previous = &version
+		}
+	}
+	stamp(&noteNode, time.Now().UTC())
+
 	noteDoc, _ := s.GenerateDoc(nil, noteNode)
 	if _, err := noteDoc.Set(s.Ctx, noteNode.ToJSON()); err != nil {
 		if status, ok := status.FromError(err); ok && status.Code() == codes.NotFound {
@@ -611,6 +691,10 @@ func (s *FirebaseService) Edit(note models.Note) (*models.Note, error) {
 		return nil, err
 	}
 
+	if previous != nil {
+		keepVersion(s, *previous, noteNode.Body)
+	}
+
 	modifiedNote := noteNode.ToNote()
 	return &modifiedNote, nil
 }
@@ -641,7 +725,7 @@ func (s *FirebaseService) Cut(note models.Note) (*models.Note, error) {
 		return nil, err
 	}
 
-	return n, nil
+	return n, RecordTombstone(s, note.Title)
 }
 
 // Mkdir creates a document in provided folder path(from dir.Path)
@@ -653,6 +737,7 @@ func (s *FirebaseService) Mkdir(dir models.Folder) (*models.Folder, error) {
 
 	path, _ := s.GeneratePath(nil, dirNode)
 	dirNode.UpdatePath(s.Type(), path)
+	stamp(&dirNode, time.Now().UTC())
 
 	folderDoc, _ := s.GenerateDoc(nil, dirNode)
 	if _, err := folderDoc.Create(s.Ctx, dirNode.ToJSON()); err != nil {
@@ -664,7 +749,7 @@ func (s *FirebaseService) Mkdir(dir models.Folder) (*models.Folder, error) {
 	}
 
 	modifiedDir := dirNode.ToFolder()
-	return &modifiedDir, nil
+	return &modifiedDir, ClearTombstone(s, modifiedDir.Title)
 }
 
 // MoveNote moves all notes from "CURRENT" firebase collection
@@ -696,111 +781,321 @@ func (s *FirebaseService) MoveNotes(settings models.Settings) error {
 // Fetch creates a clone of nodes(that doesn't exists on
 // [s](firebase-service)) from given [remote] service.
 func (s *FirebaseService) Fetch(remote ServiceRepo) ([]models.Node, []error) {
-	nodes, _, err := remote.GetAll("", "", models.NotyaIgnoreFiles)
-	if err != nil {
-		return nil, []error{err}
-	}
-
-	fetched := []models.Node{}
-	errors := []error{}
+	return transfer("fetch", remote, s)
+}
 
-	for _, node := range nodes {
-		exists, _ := s.IsNodeExists(node)
-		if exists && !node.IsFolder() {
-			local, err := s.View(node.ToNote())
-			if err != nil {
-				errors = append(errors, assets.CannotDoSth("fetch", node.Title, err))
-				continue
-			}
+// Push uploads nodes(that doesn't exists on given remote) from [s](current) to given [remote].
+func (s *FirebaseService) Push(remote ServiceRepo) ([]models.Node, []error) {
+	return transfer("push", s, remote)
+}
 
-			if local.Body != node.Body {
-				local.Body = node.Body
-				if _, err := s.Edit(*local); err != nil {
-					errors = append(errors, assets.CannotDoSth("fetch", node.Title, err))
-					continue
-				}
+// Migrate overwrites all notes of given [remote] service with [s](firebase-service).
+// If migration fails halfway, [remote] is restored back to its previous state.
+func (s *FirebaseService) Migrate(remote ServiceRepo) ([]models.Node, []error) {
+	return Migrate(s, remote)
+}
 
-				fetched = append(fetched, node)
-			}
+// firestoreWrite is a single write of batched commit, which deletes the document if [data] is nil.
+type firestoreWrite struct {
+	doc  *firestore.DocumentRef
+	data map[string]interface{}
+}
 
+// CommitPlan applies all changes of [plan] via firestore batched writes.
+//
+// Batched writes are atomic, but firestore limits a batch to [MaxBatchWrites] writes.
+// So, larger plans are committed as consecutive batches, and if any batch fails,
+// already committed batches are rolled back to the snapshot of plan (its delete changes).
+// So, the collection is never left half-changed.
+func (s *FirebaseService) CommitPlan(plan models.Plan) ([]models.Node, error) {
+	writes := []firestoreWrite{}
+	snapshot := map[string]models.Node{}
+	applied := []models.Node{}
+	now := time.Now().UTC()
+
+	// Documents can be written only once per batch. So, write non-delete changes
+	// first and skip deletes of those documents, as they'd be overwritten anyway.
+	for _, c := range plan.Changes {
+		if c.Action == models.DELETE {
+			doc, _ := s.GenerateDoc(nil, c.Node)
+			snapshot[doc.Path] = c.Node
 			continue
 		}
 
-		if node.IsFolder() && !exists {
-			if _, err := s.Mkdir(node.ToFolder()); err != nil {
-				errors = append(errors, assets.CannotDoSth("fetch", node.Title, err))
-			} else {
-				fetched = append(fetched, node)
-			}
+		note, folder := models.Note{Title: c.Title, Body: c.Node.Body}, models.Folder{Title: c.Title}
+
+		node := note.ToNode()
+		if c.Type == models.FOLDER {
+			node = folder.ToNode()
+		}
+
+		path, _ := s.GeneratePath(nil, node)
+		node.UpdatePath(s.Type(), path)
+		stamp(&node, now)
 
+		doc, _ := s.GenerateDoc(nil, node)
+		writes = append(writes, firestoreWrite{doc: doc, data: node.ToJSON()})
+		applied = append(applied, c.Node)
+	}
+
+	written := map[string]bool{}
+	for _, w := range writes {
+		written[w.doc.Path] = true
+	}
+
+	for _, c := range plan.Changes {
+		if c.Action != models.DELETE {
 			continue
 		}
 
-		if exists {
+		doc, _ := s.GenerateDoc(nil, c.Node)
+		if written[doc.Path] {
 			continue
 		}
 
-		if _, err := s.Create(node.ToNote()); err != nil {
-			errors = append(errors, assets.CannotDoSth("fetch", node.Title, err))
+		writes = append(writes, firestoreWrite{doc: doc})
+		applied = append(applied, c.Node)
+	}
+
+	committed, err := s.commitWrites(writes)
+	if err == nil {
+		return applied, nil
+	}
+
+	// Restore documents of committed batches: re-write the snapshot of existing ones, and delete new ones.
+	restore := []firestoreWrite{}
+	for _, w := range writes[:committed] {
+		if node, ok := snapshot[w.doc.Path]; ok {
+			restore = append(restore, firestoreWrite{doc: w.doc, data: node.ToJSON()})
 		} else {
-			fetched = append(fetched, node)
+			restore = append(restore, firestoreWrite{doc: w.doc})
 		}
 	}
 
-	return fetched, errors
-}
-
-// Push uploads nodes(that doesn't exists on given remote) from [s](current) to given [remote].
-func (s *FirebaseService) Push(remote ServiceRepo) ([]models.Node, []error) {
-	nodes, _, err := s.GetAll("", "", models.NotyaIgnoreFiles)
-	if err != nil {
-		return nil, []error{err}
+	if _, rollbackErr := s.commitWrites(restore); rollbackErr != nil {
+		return nil, assets.CannotDoSth("roll back", s.Type(), rollbackErr)
 	}
 
-	errors := []error{}
-	pushed := []models.Node{}
+	return nil, err
+}
 
-	for _, node := range nodes {
-		exists, _ := remote.IsNodeExists(node)
+// commitWrites commits [writes] via batches of [MaxBatchWrites] writes, by their order.
+// Returns the amount of writes, that were committed before the first failing batch.
+func (s *FirebaseService) commitWrites(writes []firestoreWrite) (int, error) {
+	for start := 0; start < len(writes); start += MaxBatchWrites {
+		end := start + MaxBatchWrites
+		if end > len(writes) {
+			end = len(writes)
+		}
 
-		if node.IsFolder() && !exists {
-			if _, err := remote.Mkdir(node.ToFolder()); err != nil {
-				errors = append(errors, assets.CannotDoSth("push", node.Title, err))
+		batch := s.FireStore.Batch()
+		for _, w := range writes[start:end] {
+			if w.data == nil {
+				batch.Delete(w.doc)
 			} else {
-				pushed = append(pushed, node)
+				batch.Set(w.doc, w.data)
 			}
+		}
 
-			continue
+		if _, err := batch.Commit(s.Ctx); err != nil {
+			return start, err
 		}
+	}
 
-		r, _ := remote.View(node.ToNote())
-		if !exists {
-			if _, err := remote.Create(node.ToNote()); err != nil {
-				errors = append(errors, assets.CannotDoSth("push", node.Title, err))
-			} else {
-				pushed = append(pushed, node)
+	return len(writes), nil
+}
+
+// TrashCollection generates the firestore collection reference of trash.
+// Each trash entry is a document of it, and nodes of entry are kept at its "nodes" sub collection.
+func (s *FirebaseService) TrashCollection() *firestore.CollectionRef {
+	return s.FireStore.Collection(s.Config.TrashPath())
+}
+
+// PutTrash saves [entry] and its nodes at trash collection.
+// Nodes are written via batches of [MaxBatchWrites] writes.
+func (s *FirebaseService) PutTrash(entry models.TrashEntry) error {
+	doc := s.TrashCollection().Doc(entry.ID)
+
+	nodes := entry.Nodes
+	entry.Nodes = nil
+
+	batch, writes := s.FireStore.Batch(), 0
+	for i, n := range nodes {
+		batch.Set(doc.Collection("nodes").Doc(fmt.Sprintf("%06d", i)), n.ToJSON())
+
+		if writes++; writes == MaxBatchWrites {
+			if _, err := batch.Commit(s.Ctx); err != nil {
+				return err
 			}
 
-			continue
+			batch, writes = s.FireStore.Batch(), 0
 		}
+	}
 
-		if r.Body != node.Body {
-			if _, err := remote.Edit(node.ToNote()); err != nil {
-				errors = append(errors, assets.CannotDoSth("push", node.Title, err))
-			} else {
-				pushed = append(pushed, node)
-			}
+	// Entry document is written last, so it's never listed without its nodes.
+	batch.Set(doc, entry.ToJSON())
+	_, err := batch.Commit(s.Ctx)
+
+	return err
+}
+
+// TrashEntries reads all entries of trash collection, with their nodes.
+func (s *FirebaseService) TrashEntries() (models.Trash, error) {
+	trash := models.Trash{}
+
+	iter := s.TrashCollection().Documents(s.Ctx)
+	defer iter.Stop()
+
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
 		}
+
+		if err != nil {
+			return trash, err
+		}
+
+		var entry models.TrashEntry
+		entry.FromJson(doc.Data())
+
+		nodeDocs, err := doc.Ref.Collection("nodes").Documents(s.Ctx).GetAll()
+		if err != nil {
+			return trash, err
+		}
+
+		for _, nd := range nodeDocs {
+			var node models.Node
+			node.FromJson(nd.Data())
+
+			entry.Nodes = append(entry.Nodes, node)
+		}
+
+		trash = append(trash, entry)
 	}
 
-	return pushed, errors
+	return trash, nil
 }
 
-// Migrate overwrites all notes of given [remote] service with [s](firebase-service).
-func (s *FirebaseService) Migrate(remote ServiceRepo) ([]models.Node, []error) {
-	if _, err := remote.ClearNodes(); err != nil {
+// DropTrash deletes the entry document of [id], with its nodes.
+func (s *FirebaseService) DropTrash(id string) error {
+	doc := s.TrashCollection().Doc(id)
+
+	nodeDocs, err := doc.Collection("nodes").DocumentRefs(s.Ctx).GetAll()
+	if err != nil {
+		return err
+	}
+
+	for _, nd := range nodeDocs {
+		if _, err := nd.Delete(s.Ctx); err != nil {
+			return err
+		}
+	}
+
+	_, err = doc.Delete(s.Ctx)
+	return err
+}
+
+// VersionsCollection generates the "versions" sub collection reference of note at [title].
+func (s *FirebaseService) VersionsCollection(title string) *firestore.CollectionRef {
+	note := models.Note{Title: title}
+	node := note.ToNode()
+
+	path, _ := s.GeneratePath(nil, node)
+	node.UpdatePath(s.Type(), path)
+
+	doc, _ := s.GenerateDoc(nil, node)
+	return doc.Collection("versions")
+}
+
+// SaveVersion writes [version] as a new document of its note's versions collection.
+func (s *FirebaseService) SaveVersion(version models.Version) error {
+	history, err := s.Versions(version.Title)
+	if err != nil {
+		return err
+	}
+
+	version.Rev = history.NextRev()
+
+	doc := s.VersionsCollection(version.Title).Doc(fmt.Sprintf("%06d", version.Rev))
+	_, err = doc.Set(s.Ctx, version.ToJSON())
+
+	return err
+}
+
+// DropVersions deletes all documents of note's versions collection.
+func (s *FirebaseService) DropVersions(title string) error {
+	docs, err := s.VersionsCollection(title).DocumentRefs(s.Ctx).GetAll()
+	if err != nil {
+		return err
+	}
+
+	for _, doc := range docs {
+		if _, err := doc.Delete(s.Ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Versions reads all documents of note's versions collection.
+func (s *FirebaseService) Versions(title string) (models.History, error) {
+	docs, err := s.VersionsCollection(title).Documents(s.Ctx).GetAll()
+	if err != nil {
 		return nil, err
 	}
 
-	return s.Push(remote)
+	history := models.History{}
+	for _, doc := range docs {
+		var version models.Version
+		version.FromJson(doc.Data())
+
+		history = append(history, version)
+	}
+
+	return history, nil
+}
+
+// NodesByTag queries the notes, that include provided [tag] at their front matter.
+// Tags are stored as an array field of documents, so they're queried on server side.
+//
+// Notes of sub folders are queried via "sub" collection group, which requires
+// a collection group index of "tags" field. If it isn't enabled, the query would fail.
+//
+// Notes written before tags were stored at documents, haven't the "tags" field, so
+// [assets.TagsNotIndexed] is returned until they're filled via [IndexTags].
+func (s *FirebaseService) NodesByTag(tag string) ([]models.Node, error) {
+	if !s.Config.FirebaseTagsIndexed {
+		return nil, assets.TagsNotIndexed
+	}
+
+	collection := s.NotyaCollection()
+	values := []string{strings.TrimPrefix(tag, "#"), "#" + strings.TrimPrefix(tag, "#")}
+
+	queries := []firestore.Query{
+		collection.Where("tags", "array-contains-any", values),
+		s.FireStore.CollectionGroup("sub").Where("tags", "array-contains-any", values),
+	}
+
+	res := []models.Node{}
+	for _, q := range queries {
+		docs, err := q.Documents(s.Ctx).GetAll()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, doc := range docs {
+			// Collection group includes "sub" collections of other notya collections too.
+			if !strings.HasPrefix(doc.Ref.Path, collection.Path+"/") {
+				continue
+			}
+
+			var node models.Node
+			node.FromJson(doc.Data())
+			res = append(res, node)
+		}
+	}
+
+	return res, nil
 }
@@ -0,0 +1,266 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/insolite-dev/notya/assets"
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/pkg"
+)
+
+// GitExcludes are those files of notes folder,
+// that shouldn't be tracked by git repository.
+var GitExcludes []string = []string{
+	models.SettingsName,
+	models.SnapshotsName,
+	models.IndexName,
+	models.TrashName,
+	models.HistoryName,
+	models.SQLiteName,
+	models.SQLiteName + "-journal",
+	".DS_Store",
+}
+
+// GitService is a class implementation of service repo.
+// Which is built on top of [LocalService], and keeps the notes folder
+// as a git repository. Each modification of nodes is recorded as a commit.
+//
+//	╭───────────────╮     ╭─────────────╮     ╭────────────────────╮
+//	│ Git Service   │ ──▶ │ Local Notes │ ──▶ │ git add && commit  │
+//	╰───────────────╯     ╰─────────────╯     ╰────────────────────╯
+type GitService struct {
+	LocalService
+}
+
+// Set [GitService] as [ServiceRepo].
+var _ ServiceRepo = &GitService{}
+
+// NewGitService creates new git service by given arguments.
+func NewGitService(stdargs models.StdArgs) *GitService {
+	return &GitService{LocalService: *NewLocalService(stdargs)}
+}
+
+// Type returns type of GitService - GIT
+func (g *GitService) Type() string {
+	return GIT.ToStr()
+}
+
+// Init setups the local service, and initializes a git repository
+// at notes folder, if it isn't initialized yet.
+func (g *GitService) Init(settings *models.Settings) error {
+	if err := g.LocalService.Init(settings); err != nil {
+		return err
+	}
+
+	if _, err := exec.LookPath("git"); err != nil {
+		return assets.GitNotInstalled
+	}
+
+	if _, err := g.git("rev-parse", "--is-inside-work-tree"); err != nil {
+		if _, err := g.git("init", "--quiet"); err != nil {
+			return err
+		}
+	}
+
+	return g.exclude()
+}
+
+// exclude appends missing [GitExcludes] to the repository's exclude file.
+// Uses [.git/info/exclude] instead of [.gitignore], to keep notes folder clean.
+//
+// Notes folder could be placed inside a bigger repository, so patterns are anchored
+// to the notes folder, and same-named files of other folders are left untouched.
+func (g *GitService) exclude() error {
+	excludePath, err := g.git("rev-parse", "--git-path", "info/exclude")
+	if err != nil {
+		return err
+	}
+
+	prefix, err := g.git("rev-parse", "--show-prefix")
+	if err != nil {
+		return err
+	}
+
+	if !filepath.IsAbs(excludePath) {
+		excludePath = filepath.Join(g.Config.NotesPath, excludePath)
+	}
+
+	body := ""
+	if data, err := pkg.ReadBody(excludePath); err == nil {
+		body = *data
+	}
+
+	lines := strings.Split(body, "\n")
+	for _, e := range GitExcludes {
+		e = "/" + prefix + "**/" + e
+		if pkg.IsIgnorable(e, lines) {
+			continue
+		}
+
+		if len(body) > 0 && !strings.HasSuffix(body, "\n") {
+			body += "\n"
+		}
+
+		body += e + "\n"
+	}
+
+	return pkg.WriteNote(excludePath, body)
+}
+
+// git runs git command with provided [args] at notes folder,
+// and returns trimmed output of command.
+func (g *GitService) git(args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", g.Config.NotesPath}, args...)...)
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		msg := strings.TrimSpace(string(out))
+		if len(msg) == 0 {
+			msg = err.Error()
+		}
+
+		return "", assets.CannotDoSth("run git", args[0], errors.New(msg))
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+// Commit stages all changes of notes folder, and commits them with
+// a message that generated from [act] and [titles].
+// If there is nothing to commit, it skips committing.
+//
+// Notes folder could be placed inside a bigger repository, so each command is
+// limited to the notes folder, and other changes of work tree are left untouched.
+func (g *GitService) Commit(act string, titles ...string) error {
+	if _, err := g.git("add", "--all", "--", "."); err != nil {
+		return err
+	}
+
+	if status, err := g.git("status", "--porcelain", "--", "."); err != nil || len(status) == 0 {
+		return err
+	}
+
+	_, err := g.git("commit", "--quiet", "-m", CommitMessage(act, titles...), "--", ".")
+	return err
+}
+
+// CommitMessage generates a git commit message for given action.
+//
+//	CommitMessage("create", "todo.md")           ─▶ "notya: create todo.md"
+//	CommitMessage("rename", "old.md", "new.md")  ─▶ "notya: rename old.md -> new.md"
+func CommitMessage(act string, titles ...string) string {
+	if len(titles) == 0 {
+		return fmt.Sprintf("notya: %v", act)
+	}
+
+	return fmt.Sprintf("notya: %v %v", act, strings.Join(titles, " -> "))
+}
+
+// Open opens given node via editor, and commits the changes.
+func (g *GitService) Open(node models.Node) error {
+	if err := g.LocalService.Open(node); err != nil {
+		return err
+	}
+
+	return g.Commit("edit", node.Title)
+}
+
+// Remove deletes given node, and commits the removal.
+func (g *GitService) Remove(node models.Node) error {
+	if err := g.LocalService.Remove(node); err != nil {
+		return err
+	}
+
+	return g.Commit("remove", node.Title)
+}
+
+// Rename changes given file's or folder's name, and commits the movement.
+func (g *GitService) Rename(editNode models.EditNode) error {
+	if err := g.LocalService.Rename(editNode); err != nil {
+		return err
+	}
+
+	return g.Commit("rename", editNode.Current.Title, editNode.New.Title)
+}
+
+// ClearNodes removes all nodes from notes folder, and commits the removal.
+func (g *GitService) ClearNodes() ([]models.Node, []error) {
+	nodes, errs := g.LocalService.ClearNodes()
+	if len(nodes) == 0 {
+		return nodes, errs
+	}
+
+	if err := g.Commit("clear", fmt.Sprintf("%v nodes", len(nodes))); err != nil {
+		errs = append(errs, err)
+	}
+
+	return nodes, errs
+}
+
+// Create creates new note file, and commits it.
+func (g *GitService) Create(note models.Note) (*models.Note, error) {
+	n, err := g.LocalService.Create(note)
+	if err != nil {
+		return nil, err
+	}
+
+	return n, g.Commit("create", note.Title)
+}
+
+// Edit overwrites exiting file's content-body, and commits the change.
+func (g *GitService) Edit(note models.Note) (*models.Note, error) {
+	n, err := g.LocalService.Edit(note)
+	if err != nil {
+		return nil, err
+	}
+
+	return n, g.Commit("edit", note.Title)
+}
+
+// Cut copies note data to clipboard, removes it and commits the removal.
+func (g *GitService) Cut(note models.Note) (*models.Note, error) {
+	n, err := g.LocalService.Cut(note)
+	if err != nil {
+		return nil, err
+	}
+
+	return n, g.Commit("cut", note.Title)
+}
+
+// Mkdir creates a new working directory, and commits it.
+//
+// Note: git doesn't track empty directories, so a commit
+// would be recorded whenever the directory gets a note.
+func (g *GitService) Mkdir(dir models.Folder) (*models.Folder, error) {
+	f, err := g.LocalService.Mkdir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return f, g.Commit("mkdir", dir.Title)
+}
+
+// Fetch clones nodes from given [remote] service to notes folder,
+// and commits all fetched nodes in one commit.
+func (g *GitService) Fetch(remote ServiceRepo) ([]models.Node, []error) {
+	fetched, errs := g.LocalService.Fetch(remote)
+	if len(fetched) == 0 {
+		return fetched, errs
+	}
+
+	if err := g.Commit("fetch", fmt.Sprintf("%v nodes from %v", len(fetched), remote.Type())); err != nil {
+		errs = append(errs, err)
+	}
+
+	return fetched, errs
+}
@@ -0,0 +1,137 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/lib/services"
+)
+
+func TestCommitMessage(t *testing.T) {
+	tests := []struct {
+		act      string
+		titles   []string
+		expected string
+	}{
+		{act: "clear", expected: "notya: clear"},
+		{act: "create", titles: []string{"todo.md"}, expected: "notya: create todo.md"},
+		{act: "rename", titles: []string{"old.md", "new.md"}, expected: "notya: rename old.md -> new.md"},
+	}
+
+	for _, td := range tests {
+		got := services.CommitMessage(td.act, td.titles...)
+		if got != td.expected {
+			t.Errorf("CommitMessage sum was different: Want: %v | Got: %v", td.expected, got)
+		}
+	}
+}
+
+func TestGitServiceCommits(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	for _, env := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(env, "notya")
+	}
+	for _, env := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(env, "notya@example.com")
+	}
+
+	dir := t.TempDir() + "/"
+
+	g := services.NewGitService(models.StdArgs{})
+	g.Config = models.InitSettings(dir)
+	if err := g.Init(nil); err != nil {
+		t.Fatalf("Init returned an error: %v", err)
+	}
+
+	if _, err := g.Create(models.Note{Title: "todo.md", Body: "- [ ] test"}); err != nil {
+		t.Fatalf("Create returned an error: %v", err)
+	}
+
+	if err := g.Rename(models.EditNode{Current: models.Node{Title: "todo.md"}, New: models.Node{Title: "done.md"}}); err != nil {
+		t.Fatalf("Rename returned an error: %v", err)
+	}
+
+	out, err := exec.Command("git", "-C", dir, "log", "--format=%s").Output()
+	if err != nil {
+		t.Fatalf("Couldn't read git log: %v", err)
+	}
+
+	got := strings.Split(strings.TrimSpace(string(out)), "\n")
+	expected := []string{"notya: rename todo.md -> done.md", "notya: create todo.md"}
+
+	if strings.Join(got, "|") != strings.Join(expected, "|") {
+		t.Errorf("Git log sum was different: Want: %v | Got: %v", expected, got)
+	}
+
+	tracked, _ := exec.Command("git", "-C", dir, "ls-files").Output()
+	if strings.Contains(string(tracked), models.SettingsName) {
+		t.Errorf("Settings file shouldn't be tracked, Got: %v", string(tracked))
+	}
+}
+
+func TestGitServiceNestedNotes(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	for _, env := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(env, "notya")
+	}
+	for _, env := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(env, "notya@example.com")
+	}
+
+	repo := t.TempDir()
+	if out, err := exec.Command("git", "-C", repo, "init", "--quiet").CombinedOutput(); err != nil {
+		t.Fatalf("Couldn't init git repository: %v (%s)", err, out)
+	}
+
+	// Dirty files of bigger repository, one untracked and one staged.
+	os.WriteFile(filepath.Join(repo, "main.go"), []byte("package main"), 0600)
+	os.WriteFile(filepath.Join(repo, "README.md"), []byte("# readme"), 0600)
+	exec.Command("git", "-C", repo, "add", "README.md").Run()
+
+	// A file of bigger repository, with the same name as a hidden file of notes folder.
+	os.WriteFile(filepath.Join(repo, models.IndexName), []byte("{}"), 0600)
+
+	dir := filepath.Join(repo, "notes") + "/"
+	os.Mkdir(dir, 0750)
+
+	g := services.NewGitService(models.StdArgs{})
+	g.Config = models.InitSettings(dir)
+	if err := g.Init(nil); err != nil {
+		t.Fatalf("Init returned an error: %v", err)
+	}
+
+	if _, err := g.Create(models.Note{Title: "todo.md", Body: "- [ ] test"}); err != nil {
+		t.Fatalf("Create returned an error: %v", err)
+	}
+
+	if err := g.Remove(models.Node{Title: "todo.md"}); err != nil {
+		t.Fatalf("Remove returned an error: %v", err)
+	}
+
+	out, _ := exec.Command("git", "-C", repo, "log", "--name-status", "--format=%s").Output()
+	expected := "notya: remove todo.md\n\nA\tnotes/" + models.TombstonesName + "\nD\tnotes/todo.md\nnotya: create todo.md\n\nA\tnotes/todo.md"
+	if got := strings.TrimSpace(string(out)); got != expected {
+		t.Errorf("Git log sum was different: Want: %q | Got: %q", expected, got)
+	}
+
+	status, _ := exec.Command("git", "-C", repo, "status", "--porcelain").Output()
+	if expected := "A  README.md\n?? " + models.IndexName + "\n?? main.go\n"; string(status) != expected {
+		t.Errorf("Other changes of repository should be left untouched: Want: %q | Got: %q", expected, string(status))
+	}
+}
@@ -0,0 +1,237 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/insolite-dev/notya/assets"
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/pkg"
+)
+
+// Versioner is implemented by those services, that keep earlier versions of notes.
+type Versioner interface {
+	// SaveVersion appends [version] to the history of its note.
+	// Revision of version is assigned by service.
+	SaveVersion(version models.Version) error
+
+	// Versions reads the history of note at [title].
+	Versions(title string) (models.History, error)
+
+	// DropVersions removes the whole history of note at [title].
+	DropVersions(title string) error
+}
+
+// Mark [LocalService] and [FirebaseService] as [Versioner].
+var (
+	_ Versioner = &LocalService{}
+	_ Versioner = &FirebaseService{}
+)
+
+// NewVersion generates a version of note [title] with its [body], that was written at [updated].
+func NewVersion(title, body string, updated time.Time) models.Version {
+	return models.Version{
+		Title:   title,
+		Body:    body,
+		Size:    int64(len(body)),
+		Hash:    pkg.HashBody(body),
+		Updated: updated.UTC(),
+	}
+}
+
+// ParseRev converts revision string to its number. Both "3" and "r3" are accepted.
+func ParseRev(rev string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(rev), "r"))
+	if err != nil || n < 1 {
+		return 0, assets.InvalidRevision(rev)
+	}
+
+	return n, nil
+}
+
+// History returns versions of note at [title], from the latest to the earliest.
+func History(s ServiceRepo, title string) (models.History, error) {
+	versioner, ok := s.(Versioner)
+	if !ok {
+		return nil, assets.HistoryNotAvailable
+	}
+
+	history, err := versioner.Versions(title)
+	if err != nil {
+		return nil, err
+	}
+
+	return history.Sort(), nil
+}
+
+// Version finds the version of [rev] at the history of note [title].
+// Zero [rev] finds the latest version.
+func Version(s ServiceRepo, title string, rev int) (*models.Version, error) {
+	history, err := History(s, title)
+	if err != nil {
+		return nil, err
+	}
+
+	version := history.Find(rev)
+	if version == nil {
+		return nil, assets.VersionNotExists(title, rev)
+	}
+
+	return version, nil
+}
+
+// DiffVersion generates the unified diff of note [title], from its version of [rev] to its current body.
+// Zero [rev] compares with the latest version.
+func DiffVersion(s ServiceRepo, title string, rev int) (string, error) {
+	note, err := s.View(models.Note{Title: title})
+	if err != nil {
+		return "", err
+	}
+
+	version, err := Version(s, title, rev)
+	if err != nil {
+		return "", err
+	}
+
+	return pkg.UnifiedDiff(title+"@r"+strconv.Itoa(version.Rev), title, version.Body, note.Body), nil
+}
+
+// RestoreVersion overwrites note [title] with its version of [rev].
+// Current body of note is kept as a new version by edit, so restoring could be undone too.
+func RestoreVersion(s ServiceRepo, title string, rev int) (*models.Version, error) {
+	version, err := Version(s, title, rev)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.Edit(models.Note{Title: title, Body: version.Body}); err != nil {
+		return nil, err
+	}
+
+	return version, nil
+}
+
+// HistoryPath returns the path of history file of note at [title].
+func (l *LocalService) HistoryPath(title string) string {
+	return l.NotyaPath + models.HistoryName + "/" + strings.Trim(title, "/") + ".json"
+}
+
+// Versions reads the history file of note at [title].
+// Notes that were never changed, have an empty history.
+func (l *LocalService) Versions(title string) (models.History, error) {
+	path := l.HistoryPath(title)
+	if !pkg.FileExists(path) {
+		return models.History{}, nil
+	}
+
+	body, err := pkg.ReadBody(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.DecodeHistory(*body), nil
+}
+
+// DropVersions removes the history file of note at [title].
+func (l *LocalService) DropVersions(title string) error {
+	if path := l.HistoryPath(title); pkg.FileExists(path) {
+		return pkg.Delete(path)
+	}
+
+	return nil
+}
+
+// SaveVersion appends [version] to the history file of its note.
+func (l *LocalService) SaveVersion(version models.Version) error {
+	history, err := l.Versions(version.Title)
+	if err != nil {
+		return err
+	}
+
+	version.Rev = history.NextRev()
+
+	path := l.HistoryPath(version.Title)
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+
+	return pkg.WriteNote(path, append(history, version).ToString())
+}
+
+// currentVersion reads the body of note at [path], before it gets changed.
+// Returns nil for missing or hidden notes, which's versions aren't kept.
+func (l *LocalService) currentVersion(path string) *models.Version {
+	title := l.indexTitle(path)
+	if pkg.IsIgnorable(filepath.Base(title), models.NotyaIgnoreFiles) {
+		return nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return nil
+	}
+
+	body, err := pkg.ReadBody(path)
+	if err != nil {
+		return nil
+	}
+
+	version := NewVersion(title, *body, info.ModTime())
+	return &version
+}
+
+// keepVersion saves [previous] version of note at [v], if its body was changed to [body].
+// Versions of hidden notes, like settings, aren't kept.
+//
+// History is a safety net of edits, so its errors never fail the edit itself.
+func keepVersion(v Versioner, previous models.Version, body string) {
+	names := splitTitle(previous.Title)
+	if previous.Body == body || len(names) == 0 || pkg.IsIgnorable(names[len(names)-1], models.NotyaIgnoreFiles) {
+		return
+	}
+
+	previous.Title = strings.Join(names, "/")
+	_ = v.SaveVersion(previous)
+}
+
+// renameHistory moves the history of node renamed from [from] to [to], ignoring errors as [keepVersion] does.
+// [mkdirAll] creates the parent folder of moved history, and [move] moves it, via the storage of service.
+func renameHistory(s ServiceRepo, from, to string, folder bool, mkdirAll func(title string) error, move func(from, to string) error) {
+	fromHistory, toHistory := models.HistoryName+"/"+from, models.HistoryName+"/"+to
+	if !folder {
+		fromHistory, toHistory = historyTitle(from), historyTitle(to)
+	}
+
+	if exists, _ := s.IsNodeExists(models.Node{Title: fromHistory}); !exists {
+		return
+	}
+
+	if err := mkdirAll(parentTitle(toHistory)); err == nil {
+		_ = move(fromHistory, toHistory)
+	}
+}
+
+// moveHistory moves the history of renamed node, from [current] to [edited] path.
+func (l *LocalService) moveHistory(current, edited string) {
+	from, to := l.HistoryPath(l.indexTitle(current)), l.HistoryPath(l.indexTitle(edited))
+	if pkg.IsDir(edited) {
+		from, to = strings.TrimSuffix(from, ".json"), strings.TrimSuffix(to, ".json")
+	}
+
+	if !pkg.FileExists(from) {
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(to), 0o750); err == nil {
+		_ = os.Rename(from, to)
+	}
+}
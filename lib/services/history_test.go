@@ -0,0 +1,89 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services_test
+
+import (
+	"testing"
+
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/lib/services"
+)
+
+func TestParseRev(t *testing.T) {
+	tests := []struct {
+		rev      string
+		expected int
+		fails    bool
+	}{
+		{rev: "3", expected: 3},
+		{rev: "r12", expected: 12},
+		{rev: "R1", expected: 1},
+		{rev: "0", fails: true},
+		{rev: "latest", fails: true},
+	}
+
+	for _, td := range tests {
+		got, err := services.ParseRev(td.rev)
+		if got != td.expected || (err != nil) != td.fails {
+			t.Errorf("ParseRev sum was different for %v: Want: %v | Got: %v (%v)", td.rev, td.expected, got, err)
+		}
+	}
+}
+
+func TestLocalHistory(t *testing.T) {
+	local := newTestLocalService(t)
+
+	local.Mkdir(models.Folder{Title: "ideas/"})
+	local.Create(models.Note{Title: "ideas/todo.md", Body: "first\n"})
+	local.Edit(models.Note{Title: "ideas/todo.md", Body: "second\n"})
+	local.Edit(models.Note{Title: "ideas/todo.md", Body: "second\n"}) // unchanged bodies aren't kept.
+	local.Edit(models.Note{Title: "ideas/todo.md", Body: "third\n"})
+
+	history, err := services.History(local, "ideas/todo.md")
+	if err != nil || len(history) != 2 {
+		t.Fatalf("History sum was different: Got: %v (%v)", history, err)
+	}
+
+	if history[0].Rev != 2 || history[0].Body != "second\n" || history[1].Body != "first\n" || history[1].Size != 6 {
+		t.Errorf("History should be ordered from the latest version, Got: %v", history)
+	}
+
+	diff, err := services.DiffVersion(local, "ideas/todo.md", 1)
+	expected := "--- ideas/todo.md@r1\n+++ ideas/todo.md\n@@ -1 +1 @@\n-first\n+third\n"
+	if err != nil || diff != expected {
+		t.Errorf("DiffVersion sum was different: Want: %q | Got: %q (%v)", expected, diff, err)
+	}
+
+	if _, err := services.DiffVersion(local, "ideas/todo.md", 5); err == nil {
+		t.Errorf("DiffVersion should fail for missing revisions")
+	}
+
+	if _, err := services.RestoreVersion(local, "ideas/todo.md", 1); err != nil {
+		t.Fatalf("RestoreVersion returned an error: %v", err)
+	}
+
+	note, _ := local.View(models.Note{Title: "ideas/todo.md"})
+	if note.Body != "first\n" {
+		t.Errorf("Restored body was different: Want: %q | Got: %q", "first\n", note.Body)
+	}
+
+	// Restoring keeps the replaced body as a new version.
+	if latest, _ := services.Version(local, "ideas/todo.md", 0); latest == nil || latest.Rev != 3 || latest.Body != "third\n" {
+		t.Errorf("Restoring should keep the replaced body, Got: %v", latest)
+	}
+
+	// History moves together with renamed nodes.
+	local.Rename(models.EditNode{Current: models.Node{Title: "ideas"}, New: models.Node{Title: "archive"}})
+	if moved, _ := services.History(local, "archive/todo.md"); len(moved) != 3 {
+		t.Errorf("History of renamed folder's note should be moved, Got: %v", moved)
+	}
+
+	// Hidden notes, like tombstones, don't keep versions.
+	if hidden, _ := services.History(local, models.TombstonesName); len(hidden) != 0 {
+		t.Errorf("Hidden notes shouldn't keep versions, Got: %v", hidden)
+	}
+}
@@ -0,0 +1,269 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/insolite-dev/notya/assets"
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/pkg"
+)
+
+// APIPrefix is the path prefix of versioned REST API of notya server.
+const APIPrefix = "/api/v1"
+
+// MaxHTTPBody is the maximum size of request bodies of REST API, in bytes.
+const MaxHTTPBody = 16 << 20
+
+// HTTPHandler serves a service over REST API of notya server.
+// Every request has to be authorized via "Authorization: Bearer <token>" header.
+//
+//	GET    /api/v1/nodes?folder=&type=           list nodes
+//	DELETE /api/v1/nodes                         clear nodes (to trash)
+//	HEAD   /api/v1/nodes/{title}                 check node existence
+//	GET    /api/v1/nodes/{title}                 view note
+//	POST   /api/v1/nodes/{title}                 create note ({"body"}), or folder if title ends with a slash
+//	PUT    /api/v1/nodes/{title}                 edit note ({"body"})
+//	PATCH  /api/v1/nodes/{title}                 rename node ({"title"})
+//	DELETE /api/v1/nodes/{title}                 remove node (to trash)
+//	GET    /api/v1/settings                      read shared settings ({"name", "editor", ...})
+//	PUT    /api/v1/settings                      write shared settings
+//
+// Only a few harmless fields of settings are shared (see [httpSettings]),
+// so paths and credentials of server's services are never read or written over API.
+// Hidden files of notya (see [models.NotyaIgnoreFiles]) aren't served as nodes either.
+//
+// Removed nodes are kept at trash of service, unless the service doesn't keep a trash.
+//
+// Failed requests are responded with {"error"} body.
+type HTTPHandler struct {
+	Service ServiceRepo
+	Token   string
+
+	// Services aren't safe for concurrent use, so requests are handled one by one.
+	mu sync.Mutex
+}
+
+// httpError is the body of failed API responses.
+type httpError struct {
+	Error string `json:"error"`
+}
+
+// httpNodeRequest is the body of create, edit and rename API requests.
+type httpNodeRequest struct {
+	Title string `json:"title,omitempty"`
+	Body  string `json:"body,omitempty"`
+}
+
+// httpSettings are those fields of settings, that are shared over REST API.
+type httpSettings struct {
+	Name            string `json:"name"`
+	Editor          string `json:"editor"`
+	JournalPattern  string `json:"journal_pattern,omitempty"`
+	JournalTemplate string `json:"journal_template,omitempty"`
+}
+
+// httpClearResponse is the body of clear nodes API response.
+type httpClearResponse struct {
+	Nodes  []models.Node `json:"nodes"`
+	Errors []string      `json:"errors,omitempty"`
+}
+
+// NewHTTPHandler creates new handler, that serves [s] for requests of [token].
+func NewHTTPHandler(s ServiceRepo, token string) *HTTPHandler {
+	return &HTTPHandler{Service: s, Token: token}
+}
+
+// ServeHTTP authorizes the request, and routes it to the appropriate service action.
+func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if len(h.Token) == 0 || subtle.ConstantTimeCompare([]byte(token), []byte(h.Token)) != 1 {
+		respond(w, http.StatusUnauthorized, httpError{Error: assets.InvalidHTTPToken.Error()})
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	r.Body = http.MaxBytesReader(w, r.Body, MaxHTTPBody)
+
+	switch path := r.URL.Path; {
+	case path == APIPrefix+"/nodes":
+		h.serveNodes(w, r)
+	case strings.HasPrefix(path, APIPrefix+"/nodes/"):
+		h.serveNode(w, r, strings.TrimPrefix(path, APIPrefix+"/nodes/"))
+	case path == APIPrefix+"/settings":
+		h.serveSettings(w, r)
+	default:
+		respond(w, http.StatusNotFound, httpError{Error: http.StatusText(http.StatusNotFound)})
+	}
+}
+
+// serveNodes lists or clears all nodes of service.
+func (h *HTTPHandler) serveNodes(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		query := r.URL.Query()
+		if !isSafeTitle(query.Get("folder")) {
+			respond(w, http.StatusBadRequest, httpError{Error: assets.InvalidPathForAct.Error()})
+			return
+		}
+
+		nodes, _, err := h.Service.GetAll(query.Get("folder"), query.Get("type"), models.NotyaIgnoreFiles)
+		if err == assets.EmptyWorkingDirectory {
+			nodes, err = []models.Node{}, nil
+		}
+
+		respondWith(w, http.StatusOK, nodes, err)
+	case http.MethodDelete:
+		nodes, errs := TrashAll(h.Service)
+		if len(errs) == 1 && errs[0] == assets.TrashNotAvailable {
+			nodes, errs = h.Service.ClearNodes()
+		}
+
+		res := httpClearResponse{Nodes: nodes}
+		for _, err := range errs {
+			res.Errors = append(res.Errors, err.Error())
+		}
+
+		respond(w, http.StatusOK, res)
+	default:
+		respond(w, http.StatusMethodNotAllowed, httpError{Error: http.StatusText(http.StatusMethodNotAllowed)})
+	}
+}
+
+// serveNode runs the action of request on node at [title].
+func (h *HTTPHandler) serveNode(w http.ResponseWriter, r *http.Request, title string) {
+	if !isSafeTitle(title) {
+		respond(w, http.StatusBadRequest, httpError{Error: assets.InvalidPathForAct.Error()})
+		return
+	}
+
+	var req httpNodeRequest
+	if r.Method == http.MethodPost || r.Method == http.MethodPut || r.Method == http.MethodPatch {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respond(w, http.StatusBadRequest, httpError{Error: err.Error()})
+			return
+		}
+	}
+
+	switch r.Method {
+	case http.MethodHead:
+		if exists, err := h.Service.IsNodeExists(models.Node{Title: title}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		} else if !exists {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	case http.MethodGet:
+		note, err := h.Service.View(models.Note{Title: title})
+		respondWith(w, http.StatusOK, note, err)
+	case http.MethodPost:
+		if strings.HasSuffix(title, "/") {
+			folder, err := h.Service.Mkdir(models.Folder{Title: title})
+			respondWith(w, http.StatusCreated, folder, err)
+			return
+		}
+
+		note, err := h.Service.Create(models.Note{Title: title, Body: req.Body})
+		respondWith(w, http.StatusCreated, note, err)
+	case http.MethodPut:
+		note, err := h.Service.Edit(models.Note{Title: title, Body: req.Body})
+		respondWith(w, http.StatusOK, note, err)
+	case http.MethodPatch:
+		if !isSafeTitle(req.Title) {
+			respond(w, http.StatusBadRequest, httpError{Error: assets.InvalidPathForAct.Error()})
+			return
+		}
+
+		err := h.Service.Rename(models.EditNode{Current: models.Node{Title: title}, New: models.Node{Title: req.Title}})
+		respondWith(w, http.StatusNoContent, nil, err)
+	case http.MethodDelete:
+		err := MoveToTrash(h.Service, models.Node{Title: title})
+		if err == assets.TrashNotAvailable {
+			err = h.Service.Remove(models.Node{Title: title})
+		}
+
+		respondWith(w, http.StatusNoContent, nil, err)
+	default:
+		respond(w, http.StatusMethodNotAllowed, httpError{Error: http.StatusText(http.StatusMethodNotAllowed)})
+	}
+}
+
+// serveSettings reads or writes shared fields of settings of service.
+func (h *HTTPHandler) serveSettings(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPut {
+		respond(w, http.StatusMethodNotAllowed, httpError{Error: http.StatusText(http.StatusMethodNotAllowed)})
+		return
+	}
+
+	settings, err := h.Service.Settings(nil)
+	if err != nil {
+		respondWith(w, http.StatusOK, nil, err)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		respond(w, http.StatusOK, httpSettings{
+			Name:            settings.Name,
+			Editor:          settings.Editor,
+			JournalPattern:  settings.JournalPattern,
+			JournalTemplate: settings.JournalTemplate,
+		})
+	case http.MethodPut:
+		var shared httpSettings
+		if err := json.NewDecoder(r.Body).Decode(&shared); err != nil {
+			respond(w, http.StatusBadRequest, httpError{Error: err.Error()})
+			return
+		}
+
+		settings.Name, settings.Editor = shared.Name, shared.Editor
+		settings.JournalPattern, settings.JournalTemplate = shared.JournalPattern, shared.JournalTemplate
+
+		respondWith(w, http.StatusNoContent, nil, h.Service.WriteSettings(*settings))
+	}
+}
+
+// isSafeTitle checks if [title] stays in the notes folder of service, and isn't a hidden file of notya.
+// i.e it has no ".." elements, or elements from [models.NotyaIgnoreFiles], like settings or trash.
+func isSafeTitle(title string) bool {
+	for _, name := range strings.Split(title, "/") {
+		if name == ".." || pkg.IsIgnorable(name, models.NotyaIgnoreFiles) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// respondWith responds [body] with [status], or the [err] of service.
+// Errors of service are responded with 422 status.
+func respondWith(w http.ResponseWriter, status int, body interface{}, err error) {
+	if err != nil {
+		respond(w, http.StatusUnprocessableEntity, httpError{Error: err.Error()})
+		return
+	}
+
+	respond(w, status, body)
+}
+
+// respond writes [body] as JSON with [status].
+func respond(w http.ResponseWriter, status int, body interface{}) {
+	if status == http.StatusNoContent {
+		w.WriteHeader(status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,368 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+	"github.com/insolite-dev/notya/assets"
+	"github.com/insolite-dev/notya/lib/models"
+)
+
+// HTTPService is a class implementation of service repo.
+// Which works on the service of another notya instance, via REST API of `notya serve`.
+//
+//	╭──────────────╮     ╭──────────────╮     ╭────────────────────╮
+//	│ HTTP Service │ ──▶ │ notya serve  │ ──▶ │ Local Service(etc) │
+//	╰──────────────╯     ╰──────────────╯     ╰────────────────────╯
+//
+// See [HTTPHandler] for the endpoints of API.
+type HTTPService struct {
+	LS      ServiceRepo // embedded local service.
+	Stdargs models.StdArgs
+	Config  models.Settings
+
+	// HTTP related.
+	Client *http.Client
+}
+
+// Mark [HTTPService] as [ServiceRepo].
+var _ ServiceRepo = &HTTPService{}
+
+// NewHTTPService creates new HTTP service by given arguments.
+func NewHTTPService(stdargs models.StdArgs, ls ServiceRepo) *HTTPService {
+	return &HTTPService{
+		LS:      ls,
+		Stdargs: stdargs,
+		Client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Type returns type of HTTPService - HTTP.
+func (s *HTTPService) Type() string {
+	return HTTP.ToStr()
+}
+
+// Path returns the URL of notya server, which is both main and notes "folder" of service.
+func (s *HTTPService) Path() (string, string) {
+	return s.Config.HTTPURL, s.Config.HTTPURL
+}
+
+// StateConfig returns current configuration of state i.e [s.Config].
+func (s *HTTPService) StateConfig() models.Settings {
+	return s.Config
+}
+
+// Init checks the connection of notya server, and reads settings of it.
+func (s *HTTPService) Init(settings *models.Settings) error {
+	if settings != nil {
+		s.Config = *settings
+	} else {
+		localConfig, err := s.LS.Settings(nil)
+		if err != nil {
+			return err
+		}
+
+		s.Config = *localConfig // should be re-written later.
+	}
+
+	u, err := url.Parse(s.Config.HTTPURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+		return assets.InvalidHTTPURL
+	}
+
+	config, err := s.Settings(nil)
+	if err != nil {
+		return err
+	}
+
+	// Connection fields are kept only at this side, and notes are kept at the URL of server.
+	config.HTTPURL, config.HTTPToken = s.Config.HTTPURL, s.Config.HTTPToken
+	config.NotesPath = s.Config.HTTPURL
+
+	s.Config = *config // set remote settings data instead of local.
+	return nil
+}
+
+// URL generates the API URL of [endpoint].
+func (s *HTTPService) URL(endpoint string) string {
+	return strings.TrimSuffix(s.Config.HTTPURL, "/") + APIPrefix + endpoint
+}
+
+// nodeEndpoint generates the API endpoint of node at [title].
+// Folder endpoints end with a slash.
+func nodeEndpoint(title string, folder bool) string {
+	names := splitTitle(title)
+	for i, name := range names {
+		names[i] = url.PathEscape(name)
+	}
+
+	endpoint := "/nodes/" + strings.Join(names, "/")
+	if folder {
+		endpoint += "/"
+	}
+
+	return endpoint
+}
+
+// request sends a request of [method] to API [endpoint], with JSON of [in] (if it's not nil).
+// Response is decoded to [out] (if it's not nil), and failed responses are converted to errors.
+func (s *HTTPService) request(method, endpoint string, in, out interface{}) (int, error) {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return 0, err
+		}
+
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, s.URL(endpoint), body)
+	if err != nil {
+		return 0, err
+	}
+
+	token := s.Config.HTTPToken
+	if len(token) == 0 {
+		token = os.Getenv(models.HTTPTokenEnv)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return resp.StatusCode, assets.InvalidHTTPToken
+	case resp.StatusCode >= 400:
+		var failure httpError
+		if err := json.NewDecoder(resp.Body).Decode(&failure); err != nil || len(failure.Error) == 0 {
+			return resp.StatusCode, fmt.Errorf("%v %v", resp.StatusCode, http.StatusText(resp.StatusCode))
+		}
+
+		return resp.StatusCode, errors.New(failure.Error)
+	}
+
+	if out != nil && method != http.MethodHead && resp.StatusCode != http.StatusNoContent {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return resp.StatusCode, err
+		}
+	}
+
+	return resp.StatusCode, nil
+}
+
+// Settings reads shared settings of notya server (see [HTTPHandler]).
+func (s *HTTPService) Settings(p *string) (*models.Settings, error) {
+	var settings models.Settings
+	if _, err := s.request(http.MethodGet, "/settings", nil, &settings); err != nil {
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+// WriteSettings overwrites shared settings of notya server by given settings model.
+// Only shared fields are sent, so secrets of settings never leave this side.
+func (s *HTTPService) WriteSettings(settings models.Settings) error {
+	if !settings.IsValid() {
+		return assets.InvalidSettingsData
+	}
+
+	shared := httpSettings{
+		Name:            settings.Name,
+		Editor:          settings.Editor,
+		JournalPattern:  settings.JournalPattern,
+		JournalTemplate: settings.JournalTemplate,
+	}
+
+	_, err := s.request(http.MethodPut, "/settings", shared, nil)
+	return err
+}
+
+// OpenSettings opens settings of notya server via editor,
+// and overwrites settings of notya server, if they were updated.
+func (s *HTTPService) OpenSettings(settings models.Settings) error {
+	return openSettingsViaTemp(s, s.Stdargs)
+}
+
+// IsNodeExists checks if node at [node.Title] exists at notya server.
+func (s *HTTPService) IsNodeExists(node models.Node) (bool, error) {
+	status, err := s.request(http.MethodHead, nodeEndpoint(node.Title, false), nil, nil)
+	if status == http.StatusNotFound {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// Open opens the note via editor (at a temporary file),
+// and overwrites the note at notya server, after editing.
+func (s *HTTPService) Open(node models.Node) error {
+	return openViaTemp(s, s.Stdargs, node)
+}
+
+// Remove deletes the node at notya server.
+func (s *HTTPService) Remove(node models.Node) error {
+	_, err := s.request(http.MethodDelete, nodeEndpoint(node.Title, false), nil, nil)
+	return err
+}
+
+// Rename renames the node at notya server.
+func (s *HTTPService) Rename(editNode models.EditNode) error {
+	req := httpNodeRequest{Title: editNode.New.Title}
+
+	_, err := s.request(http.MethodPatch, nodeEndpoint(editNode.Current.Title, false), req, nil)
+	return err
+}
+
+// ClearNodes removes all nodes of notya server.
+func (s *HTTPService) ClearNodes() ([]models.Node, []error) {
+	var res httpClearResponse
+	if _, err := s.request(http.MethodDelete, "/nodes", nil, &res); err != nil {
+		return nil, []error{err}
+	}
+
+	var errs []error
+	for _, err := range res.Errors {
+		errs = append(errs, errors.New(err))
+	}
+
+	return res.Nodes, errs
+}
+
+// GetAll lists nodes of notya server, or nodes of [additional] folder.
+// Titles of nodes are relative to [additional] folder.
+//
+// Server always ignores hidden files of notya (see [models.NotyaIgnoreFiles]), instead of [ignore].
+func (s *HTTPService) GetAll(additional, typ string, ignore []string) ([]models.Node, []string, error) {
+	query := url.Values{"folder": {additional}, "type": {typ}}
+
+	nodes := []models.Node{}
+	if _, err := s.request(http.MethodGet, "/nodes?"+query.Encode(), nil, &nodes); err != nil {
+		return nil, nil, err
+	}
+
+	if len(nodes) == 0 {
+		return nil, nil, assets.EmptyWorkingDirectory
+	}
+
+	titles := []string{}
+	for i, n := range nodes {
+		nodes[i].Path = map[string]string{s.Type(): s.URL(nodeEndpoint(additional+"/"+n.Title, n.IsFolder()))}
+		titles = append(titles, n.Title)
+	}
+
+	return nodes, titles, nil
+}
+
+// Create creates a new note at notya server.
+func (s *HTTPService) Create(note models.Note) (*models.Note, error) {
+	return s.write(http.MethodPost, note)
+}
+
+// View reads the note at notya server.
+func (s *HTTPService) View(note models.Note) (*models.Note, error) {
+	var res models.Note
+	if _, err := s.request(http.MethodGet, nodeEndpoint(note.Title, false), nil, &res); err != nil {
+		return nil, err
+	}
+
+	return &models.Note{Title: note.Title, Path: map[string]string{s.Type(): s.URL(nodeEndpoint(note.Title, false))}, Body: res.Body}, nil
+}
+
+// Edit overwrites the existing note at notya server.
+func (s *HTTPService) Edit(note models.Note) (*models.Note, error) {
+	return s.write(http.MethodPut, note)
+}
+
+// write creates (via POST [method]) or edits (via PUT [method]) the note at notya server.
+func (s *HTTPService) write(method string, note models.Note) (*models.Note, error) {
+	endpoint := nodeEndpoint(note.Title, false)
+	if _, err := s.request(method, endpoint, httpNodeRequest{Body: note.Body}, nil); err != nil {
+		return nil, err
+	}
+
+	return &models.Note{Title: note.Title, Path: map[string]string{s.Type(): s.URL(endpoint)}, Body: note.Body}, nil
+}
+
+// Copy writes body of note to machine's clipboard.
+func (s *HTTPService) Copy(note models.Note) error {
+	data, err := s.View(note)
+	if err != nil {
+		return err
+	}
+
+	return clipboard.WriteAll(data.Body)
+}
+
+// Cut, copies note data to machine's clipboard and removes it instantly.
+func (s *HTTPService) Cut(note models.Note) (*models.Note, error) {
+	n, err := s.View(note)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := clipboard.WriteAll(n.Body); err != nil {
+		return nil, err
+	}
+
+	if err := s.Remove(note.ToNode()); err != nil {
+		return nil, err
+	}
+
+	return n, nil
+}
+
+// Mkdir creates a new folder at notya server.
+func (s *HTTPService) Mkdir(dir models.Folder) (*models.Folder, error) {
+	endpoint := nodeEndpoint(dir.Title, true)
+	if _, err := s.request(http.MethodPost, endpoint, httpNodeRequest{}, nil); err != nil {
+		return nil, err
+	}
+
+	return &models.Folder{Title: nodeTitle(dir.Title, models.FOLDER), Path: map[string]string{s.Type(): s.URL(endpoint)}}, nil
+}
+
+// MoveNotes does nothing, notes path is managed by settings of notya server itself.
+func (s *HTTPService) MoveNotes(settings models.Settings) error {
+	return nil
+}
+
+// Fetch creates a clone of nodes(that doesn't exists on [s]) from given [remote] service.
+func (s *HTTPService) Fetch(remote ServiceRepo) ([]models.Node, []error) {
+	return transfer("fetch", remote, s)
+}
+
+// Push uploads nodes(that doesn't exists on given remote) from [s] to given [remote].
+func (s *HTTPService) Push(remote ServiceRepo) ([]models.Node, []error) {
+	return transfer("push", s, remote)
+}
+
+// Migrate overwrites all notes of given [remote] service with [s].
+// If migration fails halfway, [remote] is restored back to its previous state.
+func (s *HTTPService) Migrate(remote ServiceRepo) ([]models.Node, []error) {
+	return Migrate(s, remote)
+}
@@ -0,0 +1,288 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/lib/services"
+)
+
+// newTestHTTPService creates a HTTP service, that's connected to an in-process notya server of [served] service.
+func newTestHTTPService(t *testing.T, served services.ServiceRepo) *services.HTTPService {
+	if err := served.WriteSettings(served.StateConfig()); err != nil {
+		t.Fatalf("Cannot write settings of served service: %v", err)
+	}
+
+	server := httptest.NewServer(services.NewHTTPHandler(served, "secret"))
+	t.Cleanup(server.Close)
+
+	local := newTestLocalService(t)
+
+	settings := local.Config
+	settings.HTTPURL = server.URL
+	settings.HTTPToken = "secret"
+
+	s := services.NewHTTPService(local.Stdargs, local)
+	if err := s.Init(&settings); err != nil {
+		t.Fatalf("HTTPService.Init returned an error: %v", err)
+	}
+
+	return s
+}
+
+func TestHTTPServiceInit(t *testing.T) {
+	served := newTestLocalService(t)
+	s := newTestHTTPService(t, served)
+
+	tests := []struct {
+		settings models.Settings
+	}{
+		{settings: models.Settings{}},
+		{settings: models.Settings{HTTPURL: "ftp://example.com"}},
+		{settings: models.Settings{HTTPURL: s.Config.HTTPURL, HTTPToken: "wrong"}},
+		{settings: models.Settings{HTTPURL: s.Config.HTTPURL}},
+	}
+
+	for _, td := range tests {
+		if err := services.NewHTTPService(s.Stdargs, s.LS).Init(&td.settings); err == nil {
+			t.Errorf("Init should fail for invalid settings: %v", td.settings)
+		}
+	}
+
+	// Token is taken from environment, if it's not kept at settings.
+	t.Setenv(models.HTTPTokenEnv, "secret")
+	settings := models.Settings{HTTPURL: s.Config.HTTPURL}
+	if err := services.NewHTTPService(s.Stdargs, s.LS).Init(&settings); err != nil {
+		t.Errorf("Init should use token of environment, Got: %v", err)
+	}
+
+	if s.Config.Name != served.Config.Name || s.Config.HTTPURL != settings.HTTPURL {
+		t.Errorf("Init should read settings of server, and keep connection fields, Got: %v", s.Config)
+	}
+
+	if s.Config.NotesPath == served.Config.NotesPath {
+		t.Errorf("Init shouldn't read paths of server, Got: %v", s.Config.NotesPath)
+	}
+}
+
+func TestHTTPServiceSettings(t *testing.T) {
+	served := newTestLocalService(t)
+	s := newTestHTTPService(t, served)
+
+	settings := s.StateConfig()
+	settings.Editor = "nano"
+	settings.NotesPath = t.TempDir()
+	settings.SQLitePath = "/tmp/notes.db"
+	settings.WebDAVPassword = "secret"
+
+	if err := s.WriteSettings(settings); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only shared fields are written, and the rest is kept as it was.
+	got, _ := served.Settings(nil)
+	if got.Editor != "nano" || got.NotesPath != served.Config.NotesPath || len(got.SQLitePath) > 0 || len(got.WebDAVPassword) > 0 || len(got.HTTPToken) > 0 {
+		t.Errorf("WriteSettings should write only shared fields, Got: %v", got)
+	}
+}
+
+func TestHTTPServiceNodes(t *testing.T) {
+	served := newTestLocalService(t)
+	s := newTestHTTPService(t, served)
+
+	if _, err := s.Create(models.Note{Title: "ideas/a.md"}); err == nil {
+		t.Errorf("Create should fail, if parent folder doesn't exist")
+	}
+
+	s.Mkdir(models.Folder{Title: "ideas"})
+	s.Mkdir(models.Folder{Title: "ideas/old/"})
+	s.Create(models.Note{Title: "ideas/a.md", Body: "---\ntags: [work]\n---\nA"})
+	s.Create(models.Note{Title: "ideas/old/b.md", Body: "B"})
+	s.Create(models.Note{Title: "c d.md", Body: "C"})
+
+	if _, err := s.Create(models.Note{Title: "c d.md"}); err == nil {
+		t.Errorf("Create should fail for existing notes")
+	}
+
+	if _, err := s.Mkdir(models.Folder{Title: "ideas"}); err == nil {
+		t.Errorf("Mkdir should fail for existing folders")
+	}
+
+	nodes, _, err := s.GetAll("", "", models.NotyaIgnoreFiles)
+	expected := "[c d.md ideas/ ideas/a.md ideas/old/ ideas/old/b.md]"
+	if err != nil || fmt.Sprint(titles(nodes)) != expected {
+		t.Fatalf("GetAll sum was different: Want: %v | Got: %v (%v)", expected, titles(nodes), err)
+	}
+
+	if a := nodes[2]; a.Body != "---\ntags: [work]\n---\nA" || fmt.Sprint(a.Tags) != "[work]" || a.Created.IsZero() {
+		t.Errorf("GetAll should fill metadata of notes, Got: %v", a)
+	}
+
+	files, _, err := s.GetAll("ideas", "file", models.NotyaIgnoreFiles)
+	if err != nil || fmt.Sprint(titles(files)) != "[a.md old/b.md]" {
+		t.Errorf("GetAll of folder sum was different: Got: %v (%v)", titles(files), err)
+	}
+
+	if _, err := s.Edit(models.Note{Title: "c d.md", Body: "C2"}); err != nil {
+		t.Fatalf("Edit returned an error: %v", err)
+	}
+
+	if c, err := served.View(models.Note{Title: "c d.md"}); err != nil || c.Body != "C2" {
+		t.Errorf("Edit should overwrite note of server, Got: %v (%v)", c, err)
+	}
+
+	rename := models.EditNode{Current: models.Node{Title: "ideas/old"}, New: models.Node{Title: "archive"}}
+	if err := s.Rename(rename); err != nil {
+		t.Fatalf("Rename returned an error: %v", err)
+	}
+
+	if b, err := s.View(models.Note{Title: "archive/b.md"}); err != nil || b.Body != "B" {
+		t.Errorf("Rename should move sub nodes of folder, Got: %v (%v)", b, err)
+	}
+
+	if err := s.Remove(models.Node{Title: "ideas/"}); err != nil {
+		t.Fatalf("Remove returned an error: %v", err)
+	}
+
+	if exists, err := s.IsNodeExists(models.Node{Title: "ideas/a.md"}); err != nil || exists {
+		t.Errorf("Remove should remove sub nodes of folder, Got: %v (%v)", exists, err)
+	}
+
+	if trash, _ := services.TrashList(served); len(trash) != 1 || trash[0].Title != "ideas/" {
+		t.Errorf("Remove should move node to trash of server, Got: %v", trash)
+	}
+
+	if _, err := s.View(models.Note{Title: "../" + models.SettingsName}); err == nil {
+		t.Errorf("View should fail for titles out of notes folder")
+	}
+}
+
+func TestHTTPServiceMigrate(t *testing.T) {
+	local, served := newTestLocalService(t), newTestLocalService(t)
+	s := newTestHTTPService(t, served)
+
+	local.Mkdir(models.Folder{Title: "ideas/"})
+	local.Create(models.Note{Title: "ideas/a.md", Body: "A"})
+	served.Create(models.Note{Title: "b.md", Body: "B"})
+
+	migrated, errs := local.Migrate(s)
+	if len(errs) > 0 || len(migrated) != 2 {
+		t.Fatalf("Migrate sum was different: Migrated: %v | Errors: %v", titles(migrated), errs)
+	}
+
+	if plan, _ := services.PlanTransfer("push", local, served, false); len(plan.Changes) != 0 {
+		t.Errorf("Server should be same as local after migrating, Got: %v", planSummary(plan))
+	}
+
+	local.Create(models.Note{Title: "c.md", Body: "C"})
+	if pushed, errs := local.Push(s); len(errs) > 0 || len(pushed) != 1 {
+		t.Errorf("Push sum was different: Pushed: %v | Errors: %v", titles(pushed), errs)
+	}
+
+	other := newTestLocalService(t)
+	if fetched, errs := other.Fetch(s); len(errs) > 0 || len(fetched) != 3 {
+		t.Errorf("Fetch sum was different: Fetched: %v | Errors: %v", titles(fetched), errs)
+	}
+
+	// Server keeps removed nodes at its trash, including the one overwritten by migration.
+	cleared, errs := s.ClearNodes()
+	if len(errs) > 0 || fmt.Sprint(titles(cleared)) != "[c.md ideas/]" {
+		t.Errorf("ClearNodes sum was different: Cleared: %v | Errors: %v", titles(cleared), errs)
+	}
+
+	if trash, _ := services.TrashList(served); len(trash) != 3 {
+		t.Errorf("ClearNodes should move nodes to trash of server, Got: %v", trash)
+	}
+}
+
+func TestHTTPHandler(t *testing.T) {
+	served := newTestLocalService(t)
+	served.WriteSettings(served.StateConfig())
+	served.Create(models.Note{Title: "a.md", Body: "A"})
+
+	server := httptest.NewServer(services.NewHTTPHandler(served, "secret"))
+	defer server.Close()
+
+	tests := []struct {
+		method, path, token, body string
+		expected                  int
+	}{
+		{method: http.MethodGet, path: "/api/v1/nodes", expected: http.StatusUnauthorized},
+		{method: http.MethodGet, path: "/api/v1/nodes", token: "wrong", expected: http.StatusUnauthorized},
+		{method: http.MethodGet, path: "/api/v1/nodes", token: "secret", expected: http.StatusOK},
+		{method: http.MethodGet, path: "/api/v2/nodes", token: "secret", expected: http.StatusNotFound},
+		{method: http.MethodPost, path: "/api/v1/settings", token: "secret", expected: http.StatusMethodNotAllowed},
+		{method: http.MethodGet, path: "/api/v1/nodes/missing.md", token: "secret", expected: http.StatusUnprocessableEntity},
+		{method: http.MethodGet, path: "/api/v1/nodes?folder=..", token: "secret", expected: http.StatusBadRequest},
+
+		// Hidden files of notya aren't served as nodes.
+		{method: http.MethodGet, path: "/api/v1/nodes/" + models.SettingsName, token: "secret", expected: http.StatusBadRequest},
+		{method: http.MethodPut, path: "/api/v1/nodes/" + models.SettingsName, token: "secret", body: `{"body":"{}"}`, expected: http.StatusBadRequest},
+		{method: http.MethodDelete, path: "/api/v1/nodes/" + models.TombstonesName, token: "secret", expected: http.StatusBadRequest},
+		{method: http.MethodGet, path: "/api/v1/nodes/" + models.TrashName + "/a.md", token: "secret", expected: http.StatusBadRequest},
+		{method: http.MethodPost, path: "/api/v1/nodes/ideas/" + models.HistoryName + "/", token: "secret", body: `{}`, expected: http.StatusBadRequest},
+		{method: http.MethodHead, path: "/api/v1/nodes/" + models.IndexName, token: "secret", expected: http.StatusBadRequest},
+		{method: http.MethodGet, path: "/api/v1/nodes?folder=" + models.TemplatesName, token: "secret", expected: http.StatusBadRequest},
+		{method: http.MethodGet, path: "/api/v1/nodes?folder=" + models.SnapshotsName, token: "secret", expected: http.StatusBadRequest},
+		{method: http.MethodPatch, path: "/api/v1/nodes/a.md", token: "secret", body: `{"title":"` + models.SettingsName + `"}`, expected: http.StatusBadRequest},
+	}
+
+	for _, td := range tests {
+		req, _ := http.NewRequest(td.method, server.URL+td.path, strings.NewReader(td.body))
+		if len(td.token) > 0 {
+			req.Header.Set("Authorization", "Bearer "+td.token)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Request returned an error: %v", err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != td.expected {
+			t.Errorf("Status of %v %v was different: Want: %v | Got: %v", td.method, td.path, td.expected, resp.StatusCode)
+		}
+	}
+
+	// Bodies bigger than the limit aren't read.
+	body := `{"body":"` + strings.Repeat("a", services.MaxHTTPBody) + `"}`
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/api/v1/nodes/big.md", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer secret")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Request returned an error: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Status of too big request was different: Want: %v | Got: %v", http.StatusBadRequest, resp.StatusCode)
+	}
+
+	// Hidden files of notya aren't listed, even if other ignored names are asked.
+	req, _ = http.NewRequest(http.MethodGet, server.URL+"/api/v1/nodes?ignore=a.md", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Request returned an error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var nodes []models.Node
+	json.NewDecoder(resp.Body).Decode(&nodes)
+	if fmt.Sprint(titles(nodes)) != "[a.md]" {
+		t.Errorf("Listed nodes were different: Want: [a.md] | Got: %v", titles(nodes))
+	}
+}
@@ -0,0 +1,341 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/insolite-dev/notya/assets"
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/pkg"
+)
+
+// Indexer is implemented by those services, that keep a persistent search index.
+type Indexer interface {
+	// RebuildIndex re-indexes all notes from scratch.
+	RebuildIndex() (*models.Index, error)
+
+	// RefreshIndex re-indexes only those notes, that were changed after last indexing.
+	RefreshIndex() (*models.Index, error)
+}
+
+// Mark [LocalService] as [Indexer].
+var _ Indexer = &LocalService{}
+
+// FullTextSearcher is implemented by those services, that rank notes via a search engine of their own.
+type FullTextSearcher interface {
+	// FullTextSearch ranks notes, that match any term of [query].
+	FullTextSearch(query string) ([]models.IndexHit, error)
+}
+
+// indexBatch keeps the loaded search indexes of running batch, by their paths.
+// Indexes are written to their files, when the batch is flushed.
+type indexBatch struct {
+	depth   int
+	indexes map[string]*models.Index
+	dirty   map[string]bool
+}
+
+// indexes is the running batch of search indexes, if any.
+var (
+	indexMu sync.Mutex
+	indexes *indexBatch
+)
+
+// BatchIndexes starts a batch of search index updates, and returns its flush function.
+// Until flushing, each index is loaded once, updated in memory and written once at the end.
+// Used to update the index once per command, instead of rewriting it for each changed note.
+//
+// Batches could be nested, in that case only the outermost flush writes the indexes.
+func BatchIndexes() func() error {
+	indexMu.Lock()
+	if indexes == nil {
+		indexes = &indexBatch{indexes: map[string]*models.Index{}, dirty: map[string]bool{}}
+	}
+
+	batch := indexes
+	batch.depth++
+	indexMu.Unlock()
+
+	return func() error {
+		indexMu.Lock()
+		batch.depth--
+		if batch.depth > 0 {
+			indexMu.Unlock()
+			return nil
+		}
+
+		indexes = nil
+		indexMu.Unlock()
+
+		for path := range batch.dirty {
+			if err := pkg.WriteNote(path, batch.indexes[path].ToString()); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// RankedSearch looks for [opts.Query] at the search index of provided service,
+// and ranks matched notes via BM25. Matching lines of each note are highlighted
+// by terms of query.
+//
+// If service is a [FullTextSearcher], its own search engine is used instead of the index.
+func RankedSearch(s ServiceRepo, opts models.SearchOptions) ([]models.SearchResult, error) {
+	searcher, isSearcher := s.(FullTextSearcher)
+	indexer, isIndexer := s.(Indexer)
+	if !isSearcher && !isIndexer {
+		return nil, assets.IndexNotAvailable
+	}
+
+	terms := models.Tokenize(opts.Query)
+	if len(terms) == 0 {
+		return nil, assets.EmptySearchQuery
+	}
+
+	// Wrapper services are both, so the search engine of wrapped service is tried first.
+	hits, err := []models.IndexHit{}, assets.IndexNotAvailable
+	if isSearcher {
+		hits, err = searcher.FullTextSearch(opts.Query)
+	}
+
+	if err == assets.IndexNotAvailable && isIndexer {
+		var index *models.Index
+		if index, err = indexer.RefreshIndex(); err == nil {
+			hits = index.Search(opts.Query)
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i, t := range terms {
+		terms[i] = regexp.QuoteMeta(t)
+	}
+
+	matcher := regexp.MustCompile(`(?i)\b(?:` + strings.Join(terms, "|") + `)\b`)
+	scope := ""
+	if len(opts.In) > 0 {
+		scope = strings.Trim(opts.In, "/") + "/"
+	}
+
+	results := []models.SearchResult{}
+	for _, hit := range hits {
+		if !strings.HasPrefix(hit.Title, scope) {
+			continue
+		}
+
+		note, err := s.View(models.Note{Title: hit.Title})
+		if err != nil {
+			continue
+		}
+
+		node := note.ToNode()
+		result := models.MatchNode(node, scope, matcher)
+		if result == nil {
+			result = &models.SearchResult{Node: node}
+		}
+
+		result.Score = hit.Score
+		results = append(results, *result)
+	}
+
+	return results, nil
+}
+
+// IndexPath returns the path of search index file.
+func (l *LocalService) IndexPath() string {
+	return l.NotyaPath + models.IndexName
+}
+
+// LoadIndex reads the search index from its file.
+// If index wasn't built yet, an empty index would be returned.
+//
+// While indexes are batched (see [BatchIndexes]), the index is read once and kept in memory.
+func (l *LocalService) LoadIndex() (*models.Index, error) {
+	indexMu.Lock()
+	defer indexMu.Unlock()
+
+	if indexes != nil && indexes.indexes[l.IndexPath()] != nil {
+		return indexes.indexes[l.IndexPath()], nil
+	}
+
+	index := models.NewIndex()
+	if pkg.FileExists(l.IndexPath()) {
+		data, err := pkg.ReadBody(l.IndexPath())
+		if err != nil {
+			return nil, err
+		}
+
+		index = models.DecodeIndex(*data)
+	}
+
+	if indexes != nil {
+		indexes.indexes[l.IndexPath()] = index
+	}
+
+	return index, nil
+}
+
+// SaveIndex writes given search index to its file.
+// While indexes are batched, it's written when the batch is flushed.
+func (l *LocalService) SaveIndex(index *models.Index) error {
+	indexMu.Lock()
+	defer indexMu.Unlock()
+
+	if indexes != nil {
+		indexes.indexes[l.IndexPath()], indexes.dirty[l.IndexPath()] = index, true
+		return nil
+	}
+
+	return pkg.WriteNote(l.IndexPath(), index.ToString())
+}
+
+// hasIndex checks if the search index was built before, at its file or at the running batch.
+func (l *LocalService) hasIndex() bool {
+	indexMu.Lock()
+	defer indexMu.Unlock()
+
+	return pkg.FileExists(l.IndexPath()) || (indexes != nil && indexes.dirty[l.IndexPath()])
+}
+
+// RebuildIndex re-indexes all notes from scratch, and saves the index.
+func (l *LocalService) RebuildIndex() (*models.Index, error) {
+	index := models.NewIndex()
+	if _, err := l.refreshIndex(index); err != nil {
+		return nil, err
+	}
+
+	return index, l.SaveIndex(index)
+}
+
+// RefreshIndex loads the search index, and re-indexes notes that were
+// created, edited or removed outside of notya, by comparing their modification times.
+func (l *LocalService) RefreshIndex() (*models.Index, error) {
+	index, err := l.LoadIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	changed, err := l.refreshIndex(index)
+	if err != nil || !changed {
+		return index, err
+	}
+
+	return index, l.SaveIndex(index)
+}
+
+// refreshIndex brings [index] up to date with notes on disk.
+// Only bodies of changed notes are read.
+func (l *LocalService) refreshIndex(index *models.Index) (bool, error) {
+	root := l.notesRoot()
+
+	titles, _, err := pkg.ListDir(root, root, "file", models.NotyaIgnoreFiles, 0)
+	if err != nil {
+		return false, err
+	}
+
+	changed := false
+	existing := map[string]bool{}
+
+	for _, title := range titles {
+		existing[title] = true
+
+		info, err := os.Stat(root + title)
+		if err != nil {
+			continue
+		}
+
+		if doc, ok := index.Docs[title]; ok && doc.Updated.Equal(info.ModTime()) {
+			continue
+		}
+
+		body, err := pkg.ReadBody(root + title)
+		if err != nil {
+			continue
+		}
+
+		// Encrypted notes aren't indexed, since index would leak their contents.
+		if pkg.IsEncrypted(*body) {
+			existing[title] = false
+			continue
+		}
+
+		index.Add(title, *body, info.ModTime())
+		changed = true
+	}
+
+	for title := range index.Docs {
+		if !existing[title] {
+			index.Remove(title)
+			changed = true
+		}
+	}
+
+	return changed, nil
+}
+
+// updateIndex applies [update] to the search index, if it was built before.
+// Index is just a cache of notes, so errors are skipped. Missed changes
+// would be caught by modification times at next refresh.
+func (l *LocalService) updateIndex(update func(index *models.Index)) {
+	if !l.hasIndex() {
+		return
+	}
+
+	index, err := l.LoadIndex()
+	if err != nil {
+		return
+	}
+
+	update(index)
+	_ = l.SaveIndex(index)
+}
+
+// indexNote (re-)indexes the note at [path] with its [body].
+func (l *LocalService) indexNote(path, body string) {
+	title := l.indexTitle(path)
+	if pkg.IsIgnorable(filepath.Base(title), models.NotyaIgnoreFiles) {
+		return
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return
+	}
+
+	l.updateIndex(func(index *models.Index) {
+		if pkg.IsEncrypted(body) {
+			index.Remove(title)
+			return
+		}
+
+		index.Add(title, body, info.ModTime())
+	})
+}
+
+// indexTitle converts the full [path] of node, to its title at search index.
+func (l *LocalService) indexTitle(path string) string {
+	return strings.TrimPrefix(path, l.notesRoot())
+}
+
+// notesRoot returns the notes path, which always ends with a slash.
+func (l *LocalService) notesRoot() string {
+	root := l.Config.NotesPath
+	if len(root) == 0 || root[len(root)-1] != '/' {
+		root += "/"
+	}
+
+	return root
+}
@@ -0,0 +1,110 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/lib/services"
+)
+
+// resultTitles collects titles of search results.
+func resultTitles(results []models.SearchResult) []string {
+	titles := []string{}
+	for _, r := range results {
+		titles = append(titles, r.Node.Title)
+	}
+
+	return titles
+}
+
+func TestLocalIndex(t *testing.T) {
+	local := newTestLocalService(t)
+
+	local.Mkdir(models.Folder{Title: "ideas/"})
+	local.Create(models.Note{Title: "ideas/a.md", Body: "milk"})
+
+	index, err := local.RebuildIndex()
+	if err != nil || len(index.Docs) != 1 {
+		t.Fatalf("RebuildIndex sum was different: Index: %v | Error: %v", index, err)
+	}
+
+	// Index should be updated incrementally, after it was built.
+	local.Create(models.Note{Title: "b.md", Body: "milk milk bread"})
+	local.Rename(models.EditNode{Current: models.Node{Title: "ideas"}, New: models.Node{Title: "archive"}})
+	local.Edit(models.Note{Title: "archive/a.md", Body: "coffee"})
+
+	index, _ = local.LoadIndex()
+	if len(index.Docs) != 2 || len(index.Search("coffee")) != 1 || len(index.Search("milk")) != 1 {
+		t.Errorf("Index wasn't updated incrementally: Docs: %v", index.Docs)
+	}
+
+	// Notes edited outside of notya should be caught via modification times.
+	path := local.Config.NotesPath + "b.md"
+	os.WriteFile(path, []byte("tea"), 0o600)
+	os.Chtimes(path, time.Now().Add(time.Hour), time.Now().Add(time.Hour))
+
+	results, err := services.RankedSearch(local, models.SearchOptions{Query: "tea"})
+	if err != nil || len(results) != 1 || results[0].Node.Title != "b.md" || results[0].Score <= 0 {
+		t.Errorf("RankedSearch sum was different: Results: %v | Error: %v", results, err)
+	}
+
+	local.Remove(models.Node{Title: "archive/"})
+	if index, _ = local.LoadIndex(); len(index.Docs) != 1 {
+		t.Errorf("Removed folder should be removed from index: Docs: %v", index.Docs)
+	}
+
+	results, _ = services.RankedSearch(local, models.SearchOptions{Query: "tea", In: "archive"})
+	if len(results) != 0 {
+		t.Errorf("RankedSearch should be scoped, Got: %v", resultTitles(results))
+	}
+}
+
+func TestBatchIndexes(t *testing.T) {
+	local := newTestLocalService(t)
+	local.RebuildIndex()
+
+	flush := services.BatchIndexes()
+	local.Create(models.Note{Title: "a.md", Body: "milk"})
+	local.Create(models.Note{Title: "b.md", Body: "bread"})
+
+	body, _ := os.ReadFile(local.Config.NotesPath + models.IndexName)
+	if stored := models.DecodeIndex(string(body)); len(stored.Docs) != 0 {
+		t.Errorf("Index shouldn't be written until the batch is flushed, Got: %v", stored.Docs)
+	}
+
+	if index, _ := local.LoadIndex(); len(index.Docs) != 2 {
+		t.Errorf("Index of batch should be updated in memory, Got: %v", index.Docs)
+	}
+
+	if err := flush(); err != nil {
+		t.Fatalf("flush returned an error: %v", err)
+	}
+
+	if index, _ := local.LoadIndex(); len(index.Docs) != 2 {
+		t.Errorf("Index should be written at flush, Got: %v", index.Docs)
+	}
+}
+
+func TestRankedSearchWrapped(t *testing.T) {
+	vault, local, _ := newTestVaultService(t)
+	local.Create(models.Note{Title: "tea.md", Body: "green tea"})
+
+	wrapped := services.NewEncryptedService(vault, models.StdArgs{}, nil)
+
+	results, err := services.RankedSearch(wrapped, models.SearchOptions{Query: "tea"})
+	if err != nil || len(results) != 1 || results[0].Node.Title != "tea.md" {
+		t.Errorf("RankedSearch should search via index of wrapped service: Results: %v | Error: %v", resultTitles(results), err)
+	}
+
+	if _, err := wrapped.RebuildIndex(); err != nil {
+		t.Errorf("RebuildIndex should be forwarded to wrapped service, Got: %v", err)
+	}
+}
@@ -0,0 +1,46 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services
+
+import (
+	"time"
+
+	"github.com/insolite-dev/notya/lib/models"
+)
+
+// CreateJournal creates the journal note of [title] with [body],
+// creating its missing parent folders at first.
+func CreateJournal(s ServiceRepo, title, body string) (*models.Note, error) {
+	if err := mkdirParents(s, title); err != nil {
+		return nil, err
+	}
+
+	return s.Create(models.Note{Title: title, Body: body})
+}
+
+// JournalWeek reads existing journal notes of the week of [date], from monday to sunday.
+// Days without a journal note are skipped.
+func JournalWeek(s ServiceRepo, date time.Time) ([]models.Note, error) {
+	settings := s.StateConfig()
+
+	notes := []models.Note{}
+	for _, day := range models.JournalWeek(date) {
+		title := settings.JournalTitle(day)
+		if exists, _ := s.IsNodeExists(models.Node{Title: title}); !exists {
+			continue
+		}
+
+		note, err := s.View(models.Note{Title: title})
+		if err != nil {
+			return nil, err
+		}
+
+		notes = append(notes, *note)
+	}
+
+	return notes, nil
+}
@@ -0,0 +1,54 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/lib/services"
+)
+
+func TestCreateJournal(t *testing.T) {
+	local := newTestLocalService(t)
+	title := local.Config.JournalTitle(time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC))
+
+	if _, err := services.CreateJournal(local, title, "today"); err != nil {
+		t.Fatalf("CreateJournal returned an error: %v", err)
+	}
+
+	for _, folder := range []string{"journal/", "journal/2022/", "journal/2022/01/"} {
+		if exists, _ := local.IsNodeExists(models.Node{Title: folder}); !exists {
+			t.Errorf("CreateJournal should create parent folder %v", folder)
+		}
+	}
+
+	note, err := local.View(models.Note{Title: title})
+	if err != nil || note.Body != "today" {
+		t.Errorf("CreateJournal sum was different: Got: %v (%v)", note, err)
+	}
+
+	if _, err := services.CreateJournal(local, title, "again"); err == nil {
+		t.Errorf("CreateJournal should fail for existing journal notes")
+	}
+}
+
+func TestJournalWeek(t *testing.T) {
+	local := newTestLocalService(t)
+	local.Config.JournalPattern = "days/{yyyy}-{mm}-{dd}.md"
+
+	local.Mkdir(models.Folder{Title: "days/"})
+	for _, day := range []string{"2022-01-02", "2022-01-03", "2022-01-05", "2022-01-10"} {
+		local.Create(models.Note{Title: "days/" + day + ".md", Body: day})
+	}
+
+	notes, err := services.JournalWeek(local, time.Date(2022, 1, 6, 0, 0, 0, 0, time.UTC))
+	if err != nil || len(notes) != 2 || notes[0].Body != "2022-01-03" || notes[1].Body != "2022-01-05" {
+		t.Errorf("JournalWeek sum was different: Got: %v (%v)", notes, err)
+	}
+}
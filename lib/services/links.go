@@ -0,0 +1,76 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services
+
+import (
+	"github.com/insolite-dev/notya/assets"
+	"github.com/insolite-dev/notya/lib/models"
+)
+
+// LinkGraph builds the graph of wiki-style links between notes of provided service.
+func LinkGraph(s ServiceRepo) (*models.LinkGraph, error) {
+	graph, _, err := linkGraph(s)
+	return graph, err
+}
+
+// Links collects the outgoing links of note [title].
+func Links(s ServiceRepo, title string) ([]models.Link, error) {
+	graph, resolver, err := linkGraph(s)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := resolver.Resolve(title)
+	if len(resolved) == 0 {
+		return nil, assets.NotExists(title, "File")
+	}
+
+	return graph.Outgoing[resolved], nil
+}
+
+// Backlinks collects the incoming links of note [title].
+func Backlinks(s ServiceRepo, title string) ([]models.Link, error) {
+	graph, resolver, err := linkGraph(s)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := resolver.Resolve(title)
+	if len(resolved) == 0 {
+		return nil, assets.NotExists(title, "File")
+	}
+
+	return graph.Incoming[resolved], nil
+}
+
+// BrokenLinks collects those links, which's target doesn't exist at provided service.
+func BrokenLinks(s ServiceRepo) ([]models.Link, error) {
+	graph, _, err := linkGraph(s)
+	if err != nil {
+		return nil, err
+	}
+
+	broken := []models.Link{}
+	for _, link := range graph.Unresolved {
+		// Unresolved targets still could be folders, or ignored files.
+		if exists, _ := s.IsNodeExists(models.Node{Title: link.Target}); !exists {
+			broken = append(broken, link)
+		}
+	}
+
+	return broken, nil
+}
+
+// linkGraph builds the link graph and resolver of notes at provided service.
+func linkGraph(s ServiceRepo) (*models.LinkGraph, *models.LinkResolver, error) {
+	nodes, _, err := s.GetAll("", "file", models.NotyaIgnoreFiles)
+	if err != nil && err.Error() != assets.EmptyWorkingDirectory.Error() {
+		return nil, nil, err
+	}
+
+	return models.BuildLinkGraph(nodes), models.NewLinkResolver(nodes), nil
+}
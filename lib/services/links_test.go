@@ -0,0 +1,42 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services_test
+
+import (
+	"testing"
+
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/lib/services"
+)
+
+func TestLinks(t *testing.T) {
+	local := newTestLocalService(t)
+
+	local.Mkdir(models.Folder{Title: "ideas/"})
+	local.Create(models.Note{Title: "ideas/plan.md", Body: "back to [[todo]]"})
+	local.Create(models.Note{Title: "todo.md", Body: "see [[plan]], [[ideas]] and [[missing]]"})
+
+	links, err := services.Links(local, "todo")
+	if err != nil || len(links) != 3 || links[0].Resolved != "ideas/plan.md" {
+		t.Errorf("Links sum was different: Got: %v | Error: %v", links, err)
+	}
+
+	backlinks, err := services.Backlinks(local, "todo.md")
+	if err != nil || len(backlinks) != 1 || backlinks[0].From != "ideas/plan.md" {
+		t.Errorf("Backlinks sum was different: Got: %v | Error: %v", backlinks, err)
+	}
+
+	if _, err := services.Links(local, "missing"); err == nil {
+		t.Errorf("Links of missing note should fail")
+	}
+
+	// Links to existing folders aren't broken.
+	broken, err := services.BrokenLinks(local)
+	if err != nil || len(broken) != 1 || broken[0].Target != "missing" {
+		t.Errorf("BrokenLinks sum was different: Got: %v | Error: %v", broken, err)
+	}
+}
@@ -190,11 +190,28 @@ func (l *LocalService) Open(node models.Node) error {
 		return nil
 	}
 
-	return pkg.OpenViaEditor(path, l.Stdargs, l.Config)
+	previous := l.currentVersion(path)
+	if err := pkg.OpenViaEditor(path, l.Stdargs, l.Config); err != nil {
+		return err
+	}
+
+	if previous != nil {
+		if body, err := pkg.ReadBody(path); err == nil {
+			keepVersion(l, *previous, *body)
+		}
+	}
+
+	return nil
 }
 
 // Remove deletes given node.
+// Tombstones and search index of the node and its sub nodes are written at once.
 func (l *LocalService) Remove(node models.Node) error {
+	return batched(l, func() error { return l.remove(node) })
+}
+
+// remove is the implementation of [Remove], that runs in a tombstone batch.
+func (l *LocalService) remove(node models.Node) error {
 	if nodeExists, _ := l.IsNodeExists(node); !nodeExists {
 		return assets.NotExists(node.Title, "File or Directory")
 	}
@@ -204,8 +221,12 @@ func (l *LocalService) Remove(node models.Node) error {
 		return nil
 	}
 
+	tombstone := node.ToNote().Title
+
 	// Check for directory, to remove sub nodes of it.
 	if pkg.IsDir(nodePath) {
+		tombstone = node.ToFolder().Title
+
 		subNodes, _, err := l.GetAll(pkg.NormalizePath(node.Title), "", []string{})
 		if err != nil && err != assets.EmptyWorkingDirectory {
 			return err
@@ -220,7 +241,7 @@ func (l *LocalService) Remove(node models.Node) error {
 		// Remove all sub nodes of directory that're based at [nodePath].
 		for _, subNode := range subNodes {
 			title := node.ToFolder().Title + subNode.ToNote().Title
-			if err := l.Remove(models.Node{Title: title}); err != nil {
+			if err := l.remove(models.Node{Title: title}); err != nil {
 				return err
 			}
 		}
@@ -230,7 +251,9 @@ func (l *LocalService) Remove(node models.Node) error {
 		return err
 	}
 
-	return nil
+	l.updateIndex(func(index *models.Index) { index.Remove(l.indexTitle(nodePath)) })
+
+	return RecordTombstone(l, tombstone)
 }
 
 // Rename changes given file's or folder's name.
@@ -257,7 +280,16 @@ func (l *LocalService) Rename(editNode models.EditNode) error {
 		return err
 	}
 
-	return nil
+	l.updateIndex(func(index *models.Index) { index.Rename(l.indexTitle(current), l.indexTitle(edited)) })
+	l.moveHistory(current, edited)
+
+	// Old title doesn't exist anymore, so it's recorded as removed.
+	tombstone, created := editNode.Current.ToNote().Title, editNode.New.ToNote().Title
+	if pkg.IsDir(edited) {
+		tombstone, created = editNode.Current.ToFolder().Title, editNode.New.ToFolder().Title
+	}
+
+	return MoveTombstone(l, tombstone, created)
 }
 
 // ClearNodes removes all nodes from local (including folders).
@@ -276,6 +308,7 @@ func (l *LocalService) ClearNodes() ([]models.Node, []error) {
 	var res []models.Node
 	var errs []error
 
+	flush := Batch(l)
 	for _, n := range nodes {
 		if err := l.Remove(n); err != nil {
 			errs = append(errs, assets.CannotDoSth("remove", n.Title, err))
@@ -285,6 +318,10 @@ func (l *LocalService) ClearNodes() ([]models.Node, []error) {
 		res = append(res, n)
 	}
 
+	if err := flush(); err != nil {
+		errs = append(errs, err)
+	}
+
 	return res, errs
 }
 
@@ -304,7 +341,9 @@ func (l *LocalService) Create(note models.Note) (*models.Note, error) {
 		return nil, creatingErr
 	}
 
-	return &models.Note{Title: note.Title, Path: map[string]string{l.Type(): notePath}}, nil
+	l.indexNote(notePath, note.Body)
+
+	return &models.Note{Title: note.Title, Path: map[string]string{l.Type(): notePath}}, ClearTombstone(l, note.Title)
 }
 
 // View opens note-file from given [note.Name], then takes it body,
@@ -341,10 +380,16 @@ func (l *LocalService) Edit(note models.Note) (*models.Note, error) {
 		return nil, assets.NotExists(note.Title, "File")
 	}
 
+	previous := l.currentVersion(notePath)
 	if writingErr := pkg.WriteNote(notePath, note.Body); writingErr != nil {
 		return nil, writingErr
 	}
 
+	l.indexNote(notePath, note.Body)
+	if previous != nil {
+		keepVersion(l, *previous, note.Body)
+	}
+
 	return &models.Note{Title: note.Title, Path: map[string]string{l.Type(): notePath}, Body: note.Body}, nil
 }
 
@@ -405,12 +450,15 @@ func (l *LocalService) Mkdir(dir models.Folder) (*models.Folder, error) {
 		return nil, mkdirErr
 	}
 
-	return &models.Folder{Title: title, Path: map[string]string{l.Type(): folderPath}}, nil
+	return &models.Folder{Title: title, Path: map[string]string{l.Type(): folderPath}}, ClearTombstone(l, title)
 }
 
 // GetAll fetches all nodes(files and folders) from current active local directory.
 func (l *LocalService) GetAll(additional, typ string, ignore []string) ([]models.Node, []string, error) {
 	path, _ := l.GeneratePath(l.Config.NotesPath, models.Node{Title: additional})
+	if path[len(path)-1] != '/' {
+		path += "/"
+	}
 
 	// Generate array of all file names that are located in [path].
 	files, pretty, err := pkg.ListDir(path, path, typ, ignore, 0)
@@ -425,7 +473,8 @@ func (l *LocalService) GetAll(additional, typ string, ignore []string) ([]models
 	// Generate node list via [files] array.
 	nodes := []models.Node{}
 	for i, title := range files {
-		p, err := l.GeneratePath(l.Config.NotesPath, models.Node{Title: title})
+		// Titles are relative to [path], which is the listed folder.
+		p, err := l.GeneratePath(path, models.Node{Title: title})
 		if err != nil {
 			continue
 		}
@@ -437,9 +486,16 @@ func (l *LocalService) GetAll(additional, typ string, ignore []string) ([]models
 			data, err := l.View(node.ToNote())
 			if err == nil {
 				node = models.Node{Type: models.FILE, Title: title, Path: path, Body: data.Body, Pretty: pretty[i]}
+				node.Size, node.Hash = int64(len(data.Body)), pkg.HashBody(data.Body)
+				node.FillTags()
 			}
 		}
 
+		// Fill timestamps from file stat.
+		if info, err := os.Stat(p); err == nil {
+			node.Created, node.Updated = pkg.BirthTime(info).UTC(), info.ModTime().UTC()
+		}
+
 		nodes = append(nodes, node)
 	}
 
@@ -498,127 +554,16 @@ func (l *LocalService) MoveNotes(settings models.Settings) error {
 
 // Fetch creates a clone of nodes(that doesn't exists on [l](local-service)) from given [remote] service.
 func (l *LocalService) Fetch(remote ServiceRepo) ([]models.Node, []error) {
-	nodes, _, err := remote.GetAll("", "", models.NotyaIgnoreFiles)
-	if err != nil {
-		return nil, []error{err}
-	}
-
-	// Sort nodes via title-len ascending order.
-	sort.Slice(
-		nodes,
-		func(i, j int) bool { return len(nodes[i].Title) < len(nodes[j].Title) },
-	)
-
-	fetched := []models.Node{}
-	errors := []error{}
-
-	for _, node := range nodes {
-		exists, _ := l.IsNodeExists(node)
-		if exists && !node.IsFolder() {
-			local, err := l.View(node.ToNote())
-			if err != nil {
-				errors = append(errors, assets.CannotDoSth("fetch", node.Title, err))
-				continue
-			}
-
-			if local.Body != node.Body {
-				local.Body = node.Body
-				if _, err := l.Edit(*local); err != nil {
-					errors = append(errors, assets.CannotDoSth("fetch", node.Title, err))
-					continue
-				}
-
-				fetched = append(fetched, node)
-			}
-
-			continue
-		}
-
-		if node.IsFolder() && !exists {
-			if _, err := l.Mkdir(node.ToFolder()); err != nil {
-				errors = append(errors, assets.CannotDoSth("fetch", node.Title, err))
-			} else {
-				fetched = append(fetched, node)
-			}
-
-			continue
-		}
-
-		if exists {
-			continue
-		}
-
-		if _, err := l.Create(node.ToNote()); err != nil {
-			errors = append(errors, assets.CannotDoSth("fetch", node.Title, err))
-		} else {
-			fetched = append(fetched, node)
-		}
-	}
-
-	return fetched, errors
+	return transfer("fetch", remote, l)
 }
 
 // Push uploads nodes(that doesn't exists on given remote) from [l](current) to given [remote].
 func (l *LocalService) Push(remote ServiceRepo) ([]models.Node, []error) {
-	nodes, _, err := l.GetAll("", "", models.NotyaIgnoreFiles)
-	if err != nil {
-		return nil, []error{err}
-	}
-
-	sort.Slice(
-		nodes,
-		func(i, j int) bool { return len(nodes[i].Title) < len(nodes[j].Title) },
-	)
-
-	pushed := []models.Node{}
-	errors := []error{}
-
-	for _, node := range nodes {
-		path, err := l.GeneratePath(l.Config.NotesPath, node)
-		if err != nil {
-			continue
-		}
-
-		exists, _ := remote.IsNodeExists(node)
-
-		if pkg.IsDir(path) && !exists {
-			if _, err := remote.Mkdir(node.ToFolder()); err != nil {
-				errors = append(errors, assets.CannotDoSth("push", node.Title, err))
-			} else {
-				pushed = append(pushed, node)
-			}
-
-			continue
-		}
-
-		r, _ := remote.View(node.ToNote())
-		if !exists {
-			if _, err := remote.Create(node.ToNote()); err != nil {
-				errors = append(errors, assets.CannotDoSth("push", node.Title, err))
-			} else {
-				pushed = append(pushed, node)
-			}
-
-			continue
-		}
-
-		if r.Body != node.Body {
-			if _, err := remote.Edit(node.ToNote()); err != nil {
-				errors = append(errors, assets.CannotDoSth("push", node.Title, err))
-			} else {
-				pushed = append(pushed, node)
-			}
-		}
-	}
-
-	return pushed, errors
+	return transfer("push", l, remote)
 }
 
 // Migrate overwrites all notes of given [remote] service with [l](current-service).
+// If migration fails halfway, [remote] is restored back to its previous state.
 func (l *LocalService) Migrate(remote ServiceRepo) ([]models.Node, []error) {
-	if _, err := remote.ClearNodes(); err != nil {
-		return nil, err
-	}
-
-	return l.Push(remote)
+	return Migrate(l, remote)
 }
@@ -0,0 +1,38 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/pkg"
+)
+
+func TestLocalGetAllMetadata(t *testing.T) {
+	local := newTestLocalService(t)
+	start := time.Now().Add(-time.Minute)
+
+	local.Mkdir(models.Folder{Title: "ideas/"})
+	local.Create(models.Note{Title: "ideas/a.md", Body: "notya"})
+
+	nodes, _, err := local.GetAll("", "", models.NotyaIgnoreFiles)
+	if err != nil || len(nodes) != 2 {
+		t.Fatalf("GetAll sum was different: Nodes: %v | Error: %v", nodes, err)
+	}
+
+	for _, n := range nodes {
+		if n.Updated.Before(start) || n.Created.Before(start) {
+			t.Errorf("Timestamps of %v weren't filled: Created: %v | Updated: %v", n.Title, n.Created, n.Updated)
+		}
+
+		if n.IsFile() && (n.Size != 5 || n.Hash != pkg.HashBody("notya")) {
+			t.Errorf("Size or hash of %v was different: Size: %v | Hash: %v", n.Title, n.Size, n.Hash)
+		}
+	}
+}
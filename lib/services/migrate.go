@@ -0,0 +1,123 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services
+
+import (
+	"github.com/insolite-dev/notya/assets"
+	"github.com/insolite-dev/notya/lib/models"
+)
+
+// Committer is implemented by those services, that can apply a whole plan atomically.
+// So, the plan is either applied completely, or not applied at all.
+type Committer interface {
+	CommitPlan(plan models.Plan) ([]models.Node, error)
+}
+
+// commitSealed seals bodies of notes of [plan] via [seal], and commits it via [s] (if it's a [Committer]).
+// It's the shared implementation of [Committer] for wrapper services.
+func commitSealed(s ServiceRepo, plan models.Plan, seal func(n models.Node) (string, error)) ([]models.Node, error) {
+	committer, ok := s.(Committer)
+	if !ok {
+		return nil, assets.CommitNotAvailable
+	}
+
+	sealed := plan
+	sealed.Changes = make([]models.Change, len(plan.Changes))
+
+	for i, c := range plan.Changes {
+		if c.Type == models.FILE {
+			n := c.Node
+			n.Title = c.Title
+
+			body, err := seal(n)
+			if err != nil {
+				return nil, err
+			}
+
+			c.Node.Body = body
+		}
+
+		sealed.Changes[i] = c
+	}
+
+	return committer.CommitPlan(sealed)
+}
+
+// Migrate overwrites all nodes of [to] service with nodes of [from] service.
+// It's the shared implementation of [ServiceRepo.Migrate].
+//
+// If [to] service is a [Committer], the migration is committed atomically.
+// Otherwise, the delete changes of plan are used as the snapshot of [to] service,
+// and if any change fails, [to] service is rolled back to that snapshot.
+//
+// Returns the migrated nodes, which would be empty if migration was rolled back.
+func Migrate(from, to ServiceRepo) ([]models.Node, []error) {
+	plan, err := PlanMigrate(from, to)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	committed := false
+	if c, ok := to.(Committer); ok {
+		_, err := c.CommitPlan(plan)
+
+		// Wrappers of non-committer services, fall back to snapshot & rollback way.
+		if err != nil && err != assets.CommitNotAvailable {
+			return nil, []error{assets.CannotDoSth("migrate to", to.Type(), err)}
+		}
+
+		committed = err == nil
+	}
+
+	if !committed {
+		if _, errs := ApplyPlan(plan, to); len(errs) > 0 {
+			return nil, append(errs, rollback(plan, to)...)
+		}
+	}
+
+	migrated := []models.Node{}
+	for _, c := range plan.Changes {
+		if c.Action == models.CREATE {
+			migrated = append(migrated, c.Node)
+		}
+	}
+
+	return migrated, nil
+}
+
+// rollback restores [to] service to the snapshot, that was taken by [plan].
+// Only the differences between current state and snapshot are re-written.
+//
+// Returns the errors of restoring, and a last error which reports the rollback itself.
+func rollback(plan models.Plan, to ServiceRepo) []error {
+	snapshot := map[string]models.Node{}
+	for _, c := range plan.Changes {
+		if c.Action == models.DELETE {
+			snapshot[c.Title] = c.Node
+		}
+	}
+
+	current, err := syncNodes(to)
+	if err != nil {
+		return []error{assets.CannotDoSth("roll back", to.Type(), err)}
+	}
+
+	restore := models.Plan{Act: "restore", From: to.Type(), To: to.Type(), Changes: []models.Change{}}
+
+	// Delete via title-len decreasing order, so children come before parents.
+	titles := sortedTitles(keys(current))
+	for i := len(titles) - 1; i >= 0; i-- {
+		if _, ok := snapshot[titles[i]]; !ok {
+			restore.Changes = append(restore.Changes, change(models.DELETE, titles[i], current[titles[i]]))
+		}
+	}
+
+	restore.Changes = append(restore.Changes, planClone(snapshot, current)...)
+
+	_, errs := ApplyPlan(restore, to)
+	return append(errs, assets.RolledBack(plan.Act, to.Type(), len(snapshot)))
+}
@@ -0,0 +1,133 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/insolite-dev/notya/assets"
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/lib/services"
+	"github.com/insolite-dev/notya/pkg"
+)
+
+// failingService is a local service, which fails on creating the note of [fail] title.
+type failingService struct {
+	*services.LocalService
+	fail string
+}
+
+func (f *failingService) Create(note models.Note) (*models.Note, error) {
+	if note.Title == f.fail {
+		return nil, errors.New("sww")
+	}
+
+	return f.LocalService.Create(note)
+}
+
+func TestMigrate(t *testing.T) {
+	local, remote := newTestLocalService(t), newTestLocalService(t)
+
+	local.Mkdir(models.Folder{Title: "ideas/"})
+	local.Create(models.Note{Title: "ideas/a.md", Body: "A"})
+	remote.Create(models.Note{Title: "b.md", Body: "B"})
+
+	migrated, errs := services.Migrate(local, remote)
+	if len(errs) > 0 || len(migrated) != 2 {
+		t.Fatalf("Migrate sum was different: Migrated: %v | Errors: %v", migrated, errs)
+	}
+
+	if plan, _ := services.PlanTransfer("push", local, remote, false); len(plan.Changes) != 0 {
+		t.Errorf("Remote should be same as local after migrating, Got: %v", planSummary(plan))
+	}
+
+	if exists, _ := remote.IsNodeExists(models.Node{Title: "b.md"}); exists {
+		t.Errorf("Migrate should remove nodes, that don't exist at local")
+	}
+}
+
+func TestMigrateRollback(t *testing.T) {
+	local, remote := newTestLocalService(t), newTestLocalService(t)
+
+	local.Create(models.Note{Title: "a.md", Body: "A"})
+	local.Create(models.Note{Title: "b.md", Body: "new"})
+	local.Create(models.Note{Title: "c.md", Body: "C"})
+
+	remote.Mkdir(models.Folder{Title: "ideas/"})
+	remote.Create(models.Note{Title: "ideas/x.md", Body: "X"})
+	remote.Create(models.Note{Title: "b.md", Body: "old"})
+
+	before, _, _ := remote.GetAll("", "", models.NotyaIgnoreFiles)
+
+	migrated, errs := services.Migrate(local, &failingService{LocalService: remote, fail: "c.md"})
+	if len(migrated) != 0 || len(errs) != 2 {
+		t.Fatalf("Migrate sum was different: Migrated: %v | Errors: %v", migrated, errs)
+	}
+
+	expected := "Cannot migrate to LOCAL, rolled back to its snapshot of 3 nodes"
+	if got := errs[len(errs)-1].Error(); got != expected {
+		t.Errorf("Rollback error was different: Want: %v | Got: %v", expected, got)
+	}
+
+	after, _, _ := remote.GetAll("", "", models.NotyaIgnoreFiles)
+	if fmt.Sprint(titles(before)) != fmt.Sprint(titles(after)) {
+		t.Errorf("Remote wasn't restored: Want: %v | Got: %v", titles(before), titles(after))
+	}
+
+	if b, err := remote.View(models.Note{Title: "b.md"}); err != nil || b.Body != "old" {
+		t.Errorf("Remote note wasn't restored: Note: %v | Error: %v", b, err)
+	}
+}
+
+// titles collects titles of nodes.
+func titles(nodes []models.Node) []string {
+	res := []string{}
+	for _, n := range nodes {
+		res = append(res, n.Title)
+	}
+
+	return res
+}
+
+func TestMigrateEncrypted(t *testing.T) {
+	local, sqlite := newTestLocalService(t), newTestSQLiteService(t)
+	encrypted := services.NewEncryptedService(sqlite, models.StdArgs{}, func() (*pkg.Cipher, error) {
+		return pkg.NewCipher(bytes.Repeat([]byte{1}, pkg.KeyLength))
+	})
+
+	// Wrapper of committer service, commits atomically as well.
+	plan := models.Plan{Act: "migrate", Changes: []models.Change{
+		{Action: models.CREATE, Type: models.FILE, Title: "a.md", Node: models.Node{Title: "a.md", Body: "A"}},
+		{Action: models.CREATE, Type: models.FILE, Title: "missing/b.md", Node: models.Node{Title: "missing/b.md"}},
+	}}
+
+	if _, err := encrypted.CommitPlan(plan); err == nil {
+		t.Fatalf("CommitPlan should fail for notes of missing folders")
+	}
+
+	if exists, _ := sqlite.IsNodeExists(models.Node{Title: "a.md"}); exists {
+		t.Errorf("CommitPlan shouldn't apply any change of failed plan")
+	}
+
+	local.Create(models.Note{Title: "a.md", Body: "A"})
+	if migrated, errs := services.Migrate(local, encrypted); len(errs) > 0 || len(migrated) != 1 {
+		t.Fatalf("Migrate sum was different: Migrated: %v | Errors: %v", titles(migrated), errs)
+	}
+
+	if a, err := sqlite.View(models.Note{Title: "a.md"}); err != nil || !pkg.IsEncrypted(a.Body) {
+		t.Errorf("Migrate should keep bodies encrypted, Got: %v (%v)", a, err)
+	}
+
+	// Wrapper of non-committer service, falls back to snapshot & rollback way.
+	wrapped := services.NewEncryptedService(newTestLocalService(t), models.StdArgs{}, nil)
+	if _, err := wrapped.CommitPlan(plan); err != assets.CommitNotAvailable {
+		t.Errorf("CommitPlan should report unavailable commit, Got: %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services
+
+import (
+	"strings"
+	"time"
+
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/pkg"
+)
+
+// splitTitle splits [title] to names of its nodes, like: "a/b/c.md" ─▶ [a, b, c.md].
+func splitTitle(title string) []string {
+	names := []string{}
+	for _, n := range strings.Split(title, "/") {
+		if len(strings.TrimSpace(n)) > 0 {
+			names = append(names, n)
+		}
+	}
+
+	return names
+}
+
+// parentTitle returns the title of parent folder of node at [title].
+func parentTitle(title string) string {
+	names := splitTitle(title)
+	if len(names) == 0 {
+		return ""
+	}
+
+	return strings.Join(names[:len(names)-1], "/")
+}
+
+// stamp fills metadata fields of [node] by its body, and marks it as updated at [t].
+// Creation time is set only if it's missing.
+func stamp(node *models.Node, t time.Time) {
+	if node.Created.IsZero() {
+		node.Created = t
+	}
+
+	node.Updated = t
+	if node.IsFile() {
+		node.Size, node.Hash = int64(len(node.Body)), pkg.HashBody(node.Body)
+		node.FillTags()
+	}
+}
@@ -0,0 +1,201 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services
+
+import (
+	"github.com/insolite-dev/notya/assets"
+	"github.com/insolite-dev/notya/lib/models"
+)
+
+// PlanTransfer works out the changes, that cloning nodes of [from] service to [to] service would make.
+// Which is the way of fetch and push: new nodes are created, and notes with different bodies are updated.
+//
+// If [prune] is enabled, nodes that were removed from [from] service (see [Prune]),
+// would be planned to be deleted from [to] service.
+func PlanTransfer(act string, from, to ServiceRepo, prune bool) (models.Plan, error) {
+	plan := models.Plan{Act: act, From: from.Type(), To: to.Type(), Changes: []models.Change{}}
+
+	fromNodes, err := syncNodes(from)
+	if err != nil {
+		return plan, err
+	}
+
+	toNodes, err := syncNodes(to)
+	if err != nil {
+		return plan, err
+	}
+
+	if prune {
+		deletes, err := planPrune(from, fromNodes, toNodes)
+		if err != nil {
+			return plan, err
+		}
+
+		plan.Changes = append(plan.Changes, deletes...)
+	}
+
+	plan.Changes = append(plan.Changes, planClone(fromNodes, toNodes)...)
+	return plan, nil
+}
+
+// PlanMigrate works out the changes, that migrating [from] service to [to] service would make.
+// Which deletes each node of [to] service, and creates each node of [from] service.
+func PlanMigrate(from, to ServiceRepo) (models.Plan, error) {
+	plan := models.Plan{Act: "migrate", From: from.Type(), To: to.Type(), Changes: []models.Change{}}
+
+	fromNodes, err := syncNodes(from)
+	if err != nil {
+		return plan, err
+	}
+
+	toNodes, err := syncNodes(to)
+	if err != nil {
+		return plan, err
+	}
+
+	toTitles := sortedTitles(keys(toNodes))
+	for i := len(toTitles) - 1; i >= 0; i-- {
+		plan.Changes = append(plan.Changes, change(models.DELETE, toTitles[i], toNodes[toTitles[i]]))
+	}
+
+	for _, title := range sortedTitles(keys(fromNodes)) {
+		plan.Changes = append(plan.Changes, change(models.CREATE, title, fromNodes[title]))
+	}
+
+	return plan, nil
+}
+
+// planClone generates create changes for those nodes of [fromNodes], that don't exist at [toNodes],
+// and update changes for those notes, which have different bodies.
+func planClone(fromNodes, toNodes map[string]models.Node) []models.Change {
+	changes := []models.Change{}
+
+	for _, title := range sortedTitles(keys(fromNodes)) {
+		node := fromNodes[title]
+		existing, exists := toNodes[title]
+
+		switch {
+		case !exists:
+			changes = append(changes, change(models.CREATE, title, node))
+		case !node.IsFolder() && existing.Body != node.Body:
+			changes = append(changes, change(models.UPDATE, title, node))
+		}
+	}
+
+	return changes
+}
+
+// planPrune generates delete changes for those nodes of [toNodes], that are covered by
+// tombstones of [from] service, don't exist at [fromNodes], and weren't modified after removal.
+// Folders that keep any modified node are kept as well.
+func planPrune(from ServiceRepo, fromNodes, toNodes map[string]models.Node) ([]models.Change, error) {
+	tombstones, err := Tombstones(from)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := map[string]bool{}
+	for _, n := range fromNodes {
+		existing[n.ToNote().Title] = true
+	}
+
+	deletes, kept := []models.Change{}, map[string]bool{}
+
+	// Delete via title-len decreasing order, so children come before parents.
+	titles := sortedTitles(keys(toNodes))
+	for i := len(titles) - 1; i >= 0; i-- {
+		node := toNodes[titles[i]]
+		title := node.ToNote().Title
+
+		deletedAt, covered := tombstones.DeletedAt(title)
+		if existing[title] || !covered {
+			continue
+		}
+
+		if kept[title] || node.Updated.IsZero() || !node.Updated.Before(deletedAt) {
+			for parent := parentTitle(title); len(parent) > 0; parent = parentTitle(parent) {
+				kept[parent] = true
+			}
+
+			continue
+		}
+
+		deletes = append(deletes, change(models.DELETE, titles[i], node))
+	}
+
+	return deletes, nil
+}
+
+// ApplyPlan applies each change of [plan] to [to] service, by its order.
+// Returns nodes of successfully applied changes, and errors of failed ones.
+//
+// Tombstones and search index of [to] service are written once, after applying all changes.
+func ApplyPlan(plan models.Plan, to ServiceRepo) ([]models.Node, []error) {
+	applied := []models.Node{}
+	errors := []error{}
+
+	flush := Batch(to)
+
+	for _, c := range plan.Changes {
+		var err error
+
+		switch {
+		case c.Action == models.DELETE:
+			err = to.Remove(c.Node)
+		case c.Type == models.FOLDER:
+			_, err = to.Mkdir(models.Folder{Title: c.Title})
+		case c.Action == models.CREATE:
+			_, err = to.Create(models.Note{Title: c.Title, Body: c.Node.Body})
+		default:
+			_, err = to.Edit(models.Note{Title: c.Title, Body: c.Node.Body})
+		}
+
+		if err != nil {
+			errors = append(errors, assets.CannotDoSth(plan.Act, c.Title, err))
+			continue
+		}
+
+		applied = append(applied, c.Node)
+	}
+
+	if err := flush(); err != nil {
+		errors = append(errors, err)
+	}
+
+	return applied, errors
+}
+
+// transfer clones nodes of [from] service to [to] service.
+// It's the shared implementation of [ServiceRepo.Fetch] and [ServiceRepo.Push].
+func transfer(act string, from, to ServiceRepo) ([]models.Node, []error) {
+	plan, err := PlanTransfer(act, from, to, false)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	return ApplyPlan(plan, to)
+}
+
+// change generates a [models.Change] of [node] for provided action.
+func change(action models.Action, title string, node models.Node) models.Change {
+	typ := models.FILE
+	if node.IsFolder() {
+		typ = models.FOLDER
+	}
+
+	return models.Change{Action: action, Type: typ, Title: title, Size: len(node.Body), Node: node}
+}
+
+// keys collects titles of [nodes] map as a set.
+func keys(nodes map[string]models.Node) map[string]bool {
+	set := map[string]bool{}
+	for title := range nodes {
+		set[title] = true
+	}
+
+	return set
+}
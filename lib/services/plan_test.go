@@ -0,0 +1,83 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/lib/services"
+)
+
+// planSummary converts changes of plan to a comparable string list.
+func planSummary(plan models.Plan) []string {
+	res := []string{}
+	for _, c := range plan.Changes {
+		res = append(res, fmt.Sprintf("%v %v %v", c.Action, c.Title, c.Size))
+	}
+
+	return res
+}
+
+func TestPlanTransfer(t *testing.T) {
+	local, remote := newTestLocalService(t), newTestLocalService(t)
+
+	remote.Create(models.Note{Title: "b.md", Body: "old body"})
+	remote.Create(models.Note{Title: "same.md", Body: "same"})
+	remote.Create(models.Note{Title: "old.md", Body: "removed"})
+
+	// Removed after the remote note was written, so the removal is newer.
+	local.Mkdir(models.Folder{Title: "ideas/"})
+	local.Create(models.Note{Title: "ideas/a.md", Body: "A"})
+	local.Create(models.Note{Title: "b.md", Body: "new body"})
+	local.Create(models.Note{Title: "same.md", Body: "same"})
+	local.Create(models.Note{Title: "old.md"})
+	local.Remove(models.Node{Title: "old.md"})
+
+	plan, err := services.PlanTransfer("push", local, remote, true)
+	if err != nil {
+		t.Fatalf("PlanTransfer returned an error: %v", err)
+	}
+
+	expected := fmt.Sprint([]string{"delete old.md 7", "update b.md 8", "create ideas/ 0", "create ideas/a.md 1"})
+	if got := fmt.Sprint(planSummary(plan)); got != expected {
+		t.Errorf("PlanTransfer sum was different: Want: %v | Got: %v", expected, got)
+	}
+
+	// Planning shouldn't change anything.
+	if exists, _ := remote.IsNodeExists(models.Node{Title: "old.md"}); !exists {
+		t.Errorf("PlanTransfer shouldn't remove any node")
+	}
+
+	applied, errs := services.ApplyPlan(plan, remote)
+	if len(errs) > 0 || len(applied) != len(plan.Changes) {
+		t.Errorf("ApplyPlan sum was different: Applied: %v | Errors: %v", applied, errs)
+	}
+
+	if plan, _ := services.PlanTransfer("push", local, remote, true); len(plan.Changes) != 0 {
+		t.Errorf("Plan should be empty after applying it, Got: %v", planSummary(plan))
+	}
+}
+
+func TestPlanMigrate(t *testing.T) {
+	local, remote := newTestLocalService(t), newTestLocalService(t)
+
+	local.Create(models.Note{Title: "a.md", Body: "A"})
+	remote.Mkdir(models.Folder{Title: "ideas/"})
+	remote.Create(models.Note{Title: "ideas/b.md", Body: "BB"})
+
+	plan, err := services.PlanMigrate(local, remote)
+	if err != nil {
+		t.Fatalf("PlanMigrate returned an error: %v", err)
+	}
+
+	expected := fmt.Sprint([]string{"delete ideas/b.md 2", "delete ideas/ 0", "create a.md 1"})
+	if got := fmt.Sprint(planSummary(plan)); got != expected {
+		t.Errorf("PlanMigrate sum was different: Want: %v | Got: %v", expected, got)
+	}
+}
@@ -0,0 +1,57 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services
+
+import (
+	"github.com/insolite-dev/notya/assets"
+	"github.com/insolite-dev/notya/lib/models"
+)
+
+// RenameAndRelink renames the node of [editNode], and rewrites wiki-style and
+// relative markdown links of all notes, that point to the renamed node.
+// If the renamed node is a folder, links to each note under it are rewritten too.
+//
+// Returns the notes which's links were rewritten, and errors of rewriting.
+// If renaming itself fails, nothing is rewritten.
+func RenameAndRelink(s ServiceRepo, editNode models.EditNode) ([]models.Node, []error) {
+	// Links have to be resolved by the titles before renaming.
+	nodes, _, err := s.GetAll("", "", models.NotyaIgnoreFiles)
+	if err != nil && err.Error() != assets.EmptyWorkingDirectory.Error() {
+		return nil, []error{err}
+	}
+
+	resolver := models.NewLinkResolver(nodes)
+	moves := models.MovesOf(nodes, editNode.Current.Title, editNode.New.Title)
+
+	if err := s.Rename(editNode); err != nil {
+		return nil, []error{err}
+	}
+
+	relinked := []models.Node{}
+	errs := []error{}
+
+	for _, n := range nodes {
+		if n.IsFolder() {
+			continue
+		}
+
+		body := models.RelinkBody(n.Title, n.Body, moves, resolver)
+		if body == n.Body {
+			continue
+		}
+
+		note := models.Note{Title: moves.Title(n.Title), Body: body}
+		if _, err := s.Edit(note); err != nil {
+			errs = append(errs, assets.CannotDoSth("relink", note.Title, err))
+			continue
+		}
+
+		relinked = append(relinked, note.ToNode())
+	}
+
+	return relinked, errs
+}
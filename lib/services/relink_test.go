@@ -0,0 +1,48 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services_test
+
+import (
+	"testing"
+
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/lib/services"
+)
+
+func TestRenameAndRelink(t *testing.T) {
+	local := newTestLocalService(t)
+
+	local.Mkdir(models.Folder{Title: "ideas/"})
+	local.Create(models.Note{Title: "ideas/plan.md", Body: "back to [[todo]]"})
+	local.Create(models.Note{Title: "todo.md", Body: "see [plan](ideas/plan.md)"})
+	local.Create(models.Note{Title: "other.md", Body: "nothing"})
+
+	editNode := models.EditNode{Current: models.Node{Title: "ideas"}, New: models.Node{Title: "archive"}}
+	relinked, errs := services.RenameAndRelink(local, editNode)
+	if len(errs) > 0 || len(relinked) != 1 {
+		t.Fatalf("RenameAndRelink sum was different: Relinked: %v | Errors: %v", relinked, errs)
+	}
+
+	if note, _ := local.View(models.Note{Title: "todo.md"}); note.Body != "see [plan](archive/plan.md)" {
+		t.Errorf("Links weren't rewritten: Got: %v", note.Body)
+	}
+
+	editNode = models.EditNode{Current: models.Node{Title: "todo.md"}, New: models.Node{Title: "done.md"}}
+	if relinked, errs = services.RenameAndRelink(local, editNode); len(errs) > 0 || len(relinked) != 1 {
+		t.Fatalf("RenameAndRelink sum was different: Relinked: %v | Errors: %v", relinked, errs)
+	}
+
+	if note, _ := local.View(models.Note{Title: "archive/plan.md"}); note.Body != "back to [[done]]" {
+		t.Errorf("Links weren't rewritten: Got: %v", note.Body)
+	}
+
+	// Failed rename shouldn't rewrite anything.
+	editNode = models.EditNode{Current: models.Node{Title: "missing.md"}, New: models.Node{Title: "other.md"}}
+	if relinked, errs = services.RenameAndRelink(local, editNode); len(errs) != 1 || len(relinked) != 0 {
+		t.Errorf("RenameAndRelink should fail: Relinked: %v | Errors: %v", relinked, errs)
+	}
+}
@@ -13,19 +13,47 @@ import (
 )
 
 var (
-	LOCAL ServiceType = "LOCAL"
-	FIRE  ServiceType = "FIREBASE"
+	LOCAL  ServiceType = "LOCAL"
+	FIRE   ServiceType = "FIREBASE"
+	GIT    ServiceType = "GIT"
+	SQLITE ServiceType = "SQLITE"
+	S3     ServiceType = "S3"
+	WEBDAV ServiceType = "WEBDAV"
+	SFTP   ServiceType = "SFTP"
+	HTTP   ServiceType = "HTTP"
 
 	// All services into one list: including local and remote.
+	//
+	// Note: [GIT] isn't listed, because it works on the same notes
+	// folder as [LOCAL], so it cannot be a fetch/push target of it.
 	Services []string = []string{
 		LOCAL.ToStr(),
 		FIRE.ToStr(),
+		SQLITE.ToStr(),
+		S3.ToStr(),
+		WEBDAV.ToStr(),
+		SFTP.ToStr(),
+		HTTP.ToStr(),
 	}
 
 	// Only remote services into one list.
-	RemoteServices []string = []string{FIRE.ToStr()}
+	RemoteServices []string = []string{FIRE.ToStr(), S3.ToStr(), WEBDAV.ToStr(), SFTP.ToStr(), HTTP.ToStr()}
 )
 
+// SameNotes checks if services of types [a] and [b] work on the same notes.
+// Like [LOCAL] and [GIT], which both work on the local notes folder.
+func SameNotes(a, b string) bool {
+	local := func(t string) bool { return t == LOCAL.ToStr() || t == GIT.ToStr() }
+	return a == b || (local(a) && local(b))
+}
+
+// ServiceKey generates the identity of service [s], from its type and paths.
+// So, the same type of service connected to another place (bucket, collection, URL and etc.) has another key.
+func ServiceKey(s ServiceRepo) string {
+	main, notes := s.Path()
+	return s.Type() + "|" + main + "|" + notes
+}
+
 // Custom string struct to define type of services
 type ServiceType string
 
@@ -36,6 +64,18 @@ func (s *ServiceType) ToStr() string {
 		return "LOCAL"
 	case &FIRE:
 		return "FIREBASE"
+	case &GIT:
+		return "GIT"
+	case &SQLITE:
+		return "SQLITE"
+	case &S3:
+		return "S3"
+	case &WEBDAV:
+		return "WEBDAV"
+	case &SFTP:
+		return "SFTP"
+	case &HTTP:
+		return "HTTP"
 	}
 
 	return "undefined"
@@ -49,6 +89,57 @@ func IsFirebaseEnabled(s models.Settings, local *ServiceRepo) bool {
 	return err == nil
 }
 
+// IsS3Enabled checks if S3 connection is enabled or not.
+func IsS3Enabled(s models.Settings, local *ServiceRepo) bool {
+	if len(s.S3Bucket) == 0 {
+		return false
+	}
+
+	stargs := models.StdArgs{Stdin: os.Stdin, Stdout: os.Stdout, Stderr: os.Stderr}
+	err := NewS3Service(stargs, *local).Init(&s)
+
+	return err == nil
+}
+
+// IsWebDAVEnabled checks if WebDAV connection is enabled or not.
+func IsWebDAVEnabled(s models.Settings, local *ServiceRepo) bool {
+	if len(s.WebDAVURL) == 0 {
+		return false
+	}
+
+	stargs := models.StdArgs{Stdin: os.Stdin, Stdout: os.Stdout, Stderr: os.Stderr}
+	err := NewWebDAVService(stargs, *local).Init(&s)
+
+	return err == nil
+}
+
+// IsSFTPEnabled checks if SFTP connection is enabled or not.
+func IsSFTPEnabled(s models.Settings, local *ServiceRepo) bool {
+	if len(s.SFTPHost) == 0 {
+		return false
+	}
+
+	stargs := models.StdArgs{Stdin: os.Stdin, Stdout: os.Stdout, Stderr: os.Stderr}
+	service := NewSFTPService(stargs, *local)
+	if err := service.Init(&s); err != nil {
+		return false
+	}
+
+	return service.Close() == nil
+}
+
+// IsHTTPEnabled checks if notya server connection is enabled or not.
+func IsHTTPEnabled(s models.Settings, local *ServiceRepo) bool {
+	if len(s.HTTPURL) == 0 {
+		return false
+	}
+
+	stargs := models.StdArgs{Stdin: os.Stdin, Stdout: os.Stdout, Stderr: os.Stderr}
+	err := NewHTTPService(stargs, *local).Init(&s)
+
+	return err == nil
+}
+
 // ServiceRepo is a abstract class for all service implementations.
 //
 //	╭──────╮     ╭────────────────────╮
@@ -68,6 +159,12 @@ type ServiceRepo interface {
 	// Type returns the current implementation's type.
 	// - LOCAL, if it's local service implementation.
 	// - FIRE, if it's firebase service implementation.
+	// - GIT, if it's git service implementation.
+	// - SQLITE, if it's sqlite service implementation.
+	// - S3, if it's S3 compatible object storage service implementation.
+	// - WEBDAV, if it's WebDAV service implementation.
+	// - SFTP, if it's SFTP (SSH) service implementation.
+	// - HTTP, if it's notya server service implementation.
 	// and etc ...
 	Type() string
 
@@ -104,6 +201,7 @@ type ServiceRepo interface {
 	// GetAll gets the all notes from current service.
 	//
 	// [additional] provides a way of entering to sub-folders of main folder.
+	// Titles of nodes are relative to [additional] folder, at each service.
 	// [ignore] provides a way of ignoring files. Default ignorable files: [models.NotyaIgnoreFiles].
 	// [typ] provides a way to get only specific type of file-nodes.
 	GetAll(additional, typ string, ignore []string) ([]models.Node, []string, error)
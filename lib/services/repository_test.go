@@ -19,6 +19,12 @@ func TestServiceTypeToStr(t *testing.T) {
 	}{
 		{t: &services.LOCAL, expected: "LOCAL"},
 		{t: &services.FIRE, expected: "FIREBASE"},
+		{t: &services.GIT, expected: "GIT"},
+		{t: &services.SQLITE, expected: "SQLITE"},
+		{t: &services.S3, expected: "S3"},
+		{t: &services.WEBDAV, expected: "WEBDAV"},
+		{t: &services.SFTP, expected: "SFTP"},
+		{t: &services.HTTP, expected: "HTTP"},
 		{t: nil, expected: "undefined"},
 	}
 
@@ -29,3 +35,22 @@ func TestServiceTypeToStr(t *testing.T) {
 		}
 	}
 }
+
+func TestSameNotes(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected bool
+	}{
+		{a: "LOCAL", b: "LOCAL", expected: true},
+		{a: "LOCAL", b: "GIT", expected: true},
+		{a: "GIT", b: "LOCAL", expected: true},
+		{a: "LOCAL", b: "SQLITE", expected: false},
+		{a: "GIT", b: "FIREBASE", expected: false},
+	}
+
+	for _, td := range tests {
+		if got := services.SameNotes(td.a, td.b); got != td.expected {
+			t.Errorf("SameNotes sum is different for %v, %v: Want: %v | Got: %v", td.a, td.b, td.expected, got)
+		}
+	}
+}
@@ -0,0 +1,795 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services
+
+import (
+	"context"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+	"github.com/insolite-dev/notya/assets"
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/pkg"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// s3CreatedMeta is the user metadata key of objects, that keeps creation time of notes.
+// Object storages keep only the last modification time.
+const s3CreatedMeta = "Created"
+
+// S3Service is a class implementation of service repo.
+// Which keeps nodes as objects of a S3 compatible object storage bucket.
+//
+//	╭────────────╮     ╭──────────────────────────────────────╮
+//	│ S3 Service │ ──▶ │ bucket                               │
+//	╰────────────╯     │  notya/ideas/         (folder marker)│
+//	                   │  notya/ideas/note.md  (note object)  │
+//	                   ╰──────────────────────────────────────╯
+//
+// Notes are objects under the [models.Settings.S3Path] prefix, and folders
+// are empty marker objects, which's keys end with a slash. Key prefixes
+// without a marker object are listed as folders too.
+type S3Service struct {
+	LS      ServiceRepo // embedded local service.
+	Stdargs models.StdArgs
+	Config  models.Settings
+
+	// S3 related.
+	Ctx    context.Context
+	Client *minio.Client
+}
+
+// Mark [S3Service] as [ServiceRepo], [Trasher] and [Versioner].
+var (
+	_ ServiceRepo = &S3Service{}
+	_ Trasher     = &S3Service{}
+	_ Versioner   = &S3Service{}
+)
+
+// NewS3Service creates new S3 service by given arguments.
+func NewS3Service(stdargs models.StdArgs, ls ServiceRepo) *S3Service {
+	return &S3Service{
+		LS:      ls,
+		Stdargs: stdargs,
+		Ctx:     context.Background(),
+	}
+}
+
+// Type returns type of S3Service - S3.
+func (s *S3Service) Type() string {
+	return S3.ToStr()
+}
+
+// Path returns the bucket and key prefix of nodes.
+func (s *S3Service) Path() (string, string) {
+	return s.Config.S3Bucket, s.Config.S3Path()
+}
+
+// StateConfig returns current configuration of state i.e [s.Config].
+func (s *S3Service) StateConfig() models.Settings {
+	return s.Config
+}
+
+// Init connects to the bucket of settings, and reads (or writes) settings of it.
+func (s *S3Service) Init(settings *models.Settings) error {
+	if settings != nil {
+		s.Config = *settings
+	} else {
+		localConfig, err := s.LS.Settings(nil)
+		if err != nil {
+			return err
+		}
+
+		s.Config = *localConfig // should be re-written later.
+	}
+
+	if len(s.Config.S3Bucket) == 0 {
+		return assets.InvalidS3Bucket
+	}
+
+	if len(s.Config.S3CredentialsFile) > 0 && !pkg.FileExists(s.Config.S3CredentialsFile) {
+		return assets.S3CredentialsNotExists
+	}
+
+	if err := s.InitS3(); err != nil {
+		return err
+	}
+
+	config, err := s.Settings(nil)
+	if isS3NotFound(err) {
+		return s.WriteSettings(s.Config)
+	} else if err != nil {
+		return err
+	}
+
+	s.Config = *config // set remote settings data instead of local.
+	return nil
+}
+
+// InitS3 creates the client of object storage as [s.Client], and checks accessibility of bucket.
+func (s *S3Service) InitS3() error {
+	endpoint, secure := s.Config.S3Endpoint, true
+	if len(endpoint) == 0 {
+		endpoint = models.DefaultS3Endpoint
+	}
+
+	if strings.HasPrefix(endpoint, "http://") {
+		secure = false
+	}
+
+	endpoint = strings.TrimPrefix(strings.TrimPrefix(endpoint, "http://"), "https://")
+
+	creds := credentials.NewChainCredentials([]credentials.Provider{
+		&credentials.EnvAWS{},
+		&credentials.EnvMinio{},
+		&credentials.FileAWSCredentials{},
+	})
+	if len(s.Config.S3CredentialsFile) > 0 {
+		creds = credentials.NewFileAWSCredentials(s.Config.S3CredentialsFile, "")
+	}
+
+	client, err := minio.New(strings.TrimSuffix(endpoint, "/"), &minio.Options{
+		Creds:  creds,
+		Secure: secure,
+		Region: s.Config.S3Region,
+	})
+	if err != nil {
+		return err
+	}
+
+	exists, err := client.BucketExists(s.Ctx, s.Config.S3Bucket)
+	if err != nil {
+		return err
+	} else if !exists {
+		return assets.S3BucketNotExists
+	}
+
+	s.Client = client
+	return nil
+}
+
+// isS3NotFound checks if [err] is a missing key (or bucket) error of object storage.
+func isS3NotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	code := minio.ToErrorResponse(err).Code
+	return code == "NoSuchKey" || code == "NotFound" || code == "NoSuchBucket"
+}
+
+// Key generates the object key of node at [title], under prefix of notes.
+func (s *S3Service) Key(title string) string {
+	if names := splitTitle(title); len(names) > 0 {
+		return s.Config.S3Path() + "/" + strings.Join(names, "/")
+	}
+
+	return s.Config.S3Path()
+}
+
+// read reads the body and object info of note object at [key].
+func (s *S3Service) read(key string) (string, *minio.ObjectInfo, error) {
+	obj, err := s.Client.GetObject(s.Ctx, s.Config.S3Bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return "", nil, err
+	}
+	defer obj.Close()
+
+	info, err := obj.Stat()
+	if err != nil {
+		return "", nil, err
+	}
+
+	body, err := io.ReadAll(obj)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return string(body), &info, nil
+}
+
+// write overwrites the object at [key] with [body].
+// [created] is kept at user metadata of object.
+func (s *S3Service) write(key, body string, created time.Time) error {
+	_, err := s.Client.PutObject(
+		s.Ctx, s.Config.S3Bucket, key,
+		strings.NewReader(body), int64(len(body)),
+		minio.PutObjectOptions{
+			ContentType:  "text/plain; charset=utf-8",
+			UserMetadata: map[string]string{s3CreatedMeta: created.UTC().Format(time.RFC3339Nano)},
+		},
+	)
+
+	return err
+}
+
+// list lists all objects under [prefix], recursively.
+func (s *S3Service) list(prefix string) ([]minio.ObjectInfo, error) {
+	objects := []minio.ObjectInfo{}
+	for obj := range s.Client.ListObjects(s.Ctx, s.Config.S3Bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if obj.Err != nil {
+			return nil, obj.Err
+		}
+
+		objects = append(objects, obj)
+	}
+
+	return objects, nil
+}
+
+// removeAll removes objects of given [keys] via multi-object delete requests.
+func (s *S3Service) removeAll(keys []string) error {
+	objects := make(chan minio.ObjectInfo, len(keys))
+	for _, k := range keys {
+		objects <- minio.ObjectInfo{Key: k}
+	}
+	close(objects)
+
+	for rErr := range s.Client.RemoveObjects(s.Ctx, s.Config.S3Bucket, objects, minio.RemoveObjectsOptions{}) {
+		return rErr.Err
+	}
+
+	return nil
+}
+
+// nodeType finds type of node at [title].
+// A folder exists, if it has a marker object, or any object under its prefix.
+func (s *S3Service) nodeType(title string) (models.NodeType, bool, error) {
+	key := s.Key(title)
+	if len(splitTitle(title)) == 0 {
+		return models.FOLDER, true, nil
+	}
+
+	if !strings.HasSuffix(title, "/") {
+		if _, err := s.Client.StatObject(s.Ctx, s.Config.S3Bucket, key, minio.StatObjectOptions{}); err == nil {
+			return models.FILE, true, nil
+		} else if !isS3NotFound(err) {
+			return "", false, err
+		}
+	}
+
+	// Listing is cancelled after the first object.
+	ctx, cancel := context.WithCancel(s.Ctx)
+	defer cancel()
+
+	for obj := range s.Client.ListObjects(ctx, s.Config.S3Bucket, minio.ListObjectsOptions{Prefix: key + "/", MaxKeys: 1}) {
+		if obj.Err != nil {
+			return "", false, obj.Err
+		}
+
+		return models.FOLDER, true, nil
+	}
+
+	return "", false, nil
+}
+
+// checkParent checks if parent folder of node at [title] exists.
+func (s *S3Service) checkParent(title string) error {
+	names := splitTitle(title)
+	if len(names) == 0 {
+		return assets.InvalidPathForAct
+	}
+
+	parent := strings.Join(names[:len(names)-1], "/")
+	if typ, exists, err := s.nodeType(parent + "/"); err != nil {
+		return err
+	} else if !exists || typ != models.FOLDER {
+		return assets.NotExists(parent, "Directory")
+	}
+
+	return nil
+}
+
+// Settings reads settings object, which is stored by [p] (or [models.SettingsName]) under prefix of notes.
+func (s *S3Service) Settings(p *string) (*models.Settings, error) {
+	name := models.SettingsName
+	if p != nil && len(*p) != 0 {
+		name = *p
+	}
+
+	body, _, err := s.read(s.Key(name))
+	if err != nil {
+		return nil, err
+	}
+
+	settings := models.DecodeSettings(body)
+	return &settings, nil
+}
+
+// WriteSettings overwrites settings object by given settings model, without its secrets.
+func (s *S3Service) WriteSettings(settings models.Settings) error {
+	if !settings.IsValid() {
+		return assets.InvalidSettingsData
+	}
+
+	settings = settings.WithoutSecrets()
+	return s.write(s.Key(models.SettingsName), settings.ToString(), time.Now())
+}
+
+// OpenSettings opens settings object via editor,
+// and overwrites settings object, if they were updated.
+func (s *S3Service) OpenSettings(settings models.Settings) error {
+	return openSettingsViaTemp(s, s.Stdargs)
+}
+
+// IsNodeExists checks if an object (or a folder prefix) exists for node at [node.Title].
+func (s *S3Service) IsNodeExists(node models.Node) (bool, error) {
+	_, exists, err := s.nodeType(node.Title)
+	return exists, err
+}
+
+// Open opens the note object via editor (at a temporary file),
+// and overwrites the object, after editing.
+func (s *S3Service) Open(node models.Node) error {
+	return openViaTemp(s, s.Stdargs, node)
+}
+
+// Remove deletes the object of note, or all objects under the prefix of folder.
+func (s *S3Service) Remove(node models.Node) error {
+	typ, exists, err := s.nodeType(node.Title)
+	if err != nil {
+		return err
+	} else if !exists {
+		return assets.NotExists(node.Title, "File or Directory")
+	}
+
+	keys := []string{s.Key(node.Title)}
+	if typ == models.FOLDER {
+		objects, err := s.list(s.Key(node.Title) + "/")
+		if err != nil {
+			return err
+		}
+
+		keys = []string{}
+		for _, obj := range objects {
+			keys = append(keys, obj.Key)
+		}
+	}
+
+	if err := s.removeAll(keys); err != nil {
+		return err
+	}
+
+	return RecordTombstone(s, nodeTitle(node.Title, typ))
+}
+
+// Rename copies objects of node to their new keys, and removes the old ones.
+// Object storages cannot rename objects, so sub nodes of folders are copied one by one.
+// History of renamed notes is moved along.
+func (s *S3Service) Rename(editNode models.EditNode) error {
+	if editNode.Current.Title == editNode.New.Title {
+		return assets.SameTitles
+	}
+
+	typ, exists, err := s.nodeType(editNode.Current.Title)
+	if err != nil {
+		return err
+	} else if !exists {
+		return assets.NotExists(editNode.Current.Title, "File or Directory")
+	}
+
+	if exists, err := s.IsNodeExists(editNode.New); err != nil {
+		return err
+	} else if exists {
+		return assets.AlreadyExists(editNode.New.Title, "File or Directory")
+	}
+
+	from, to := s.Key(editNode.Current.Title), s.Key(editNode.New.Title)
+
+	// Folders cannot be moved into themselves.
+	if strings.HasPrefix(to, from+"/") {
+		return assets.InvalidPathForAct
+	}
+
+	if err := s.checkParent(editNode.New.Title); err != nil {
+		return err
+	}
+
+	moves := map[string]string{from: to}
+	if _, err := s.Client.StatObject(s.Ctx, s.Config.S3Bucket, s.Key(historyTitle(editNode.Current.Title)), minio.StatObjectOptions{}); err == nil {
+		moves[s.Key(historyTitle(editNode.Current.Title))] = s.Key(historyTitle(editNode.New.Title))
+	}
+
+	if typ == models.FOLDER {
+		moves = map[string]string{}
+
+		prefixes := map[string]string{
+			from: to,
+			s.Key(models.HistoryName + "/" + editNode.Current.Title): s.Key(models.HistoryName + "/" + editNode.New.Title),
+		}
+
+		for fromPrefix, toPrefix := range prefixes {
+			objects, err := s.list(fromPrefix + "/")
+			if err != nil {
+				return err
+			}
+
+			for _, obj := range objects {
+				moves[obj.Key] = toPrefix + strings.TrimPrefix(obj.Key, fromPrefix)
+			}
+		}
+	}
+
+	if err := s.move(moves); err != nil {
+		return err
+	}
+
+	// Old title doesn't exist anymore, so it's recorded as removed.
+	return MoveTombstone(s, nodeTitle(editNode.Current.Title, typ), nodeTitle(editNode.New.Title, typ))
+}
+
+// move copies objects of [moves] keys to their values at server side, and removes the old objects.
+func (s *S3Service) move(moves map[string]string) error {
+	keys := []string{}
+	for from, to := range moves {
+		_, err := s.Client.CopyObject(
+			s.Ctx,
+			minio.CopyDestOptions{Bucket: s.Config.S3Bucket, Object: to},
+			minio.CopySrcOptions{Bucket: s.Config.S3Bucket, Object: from},
+		)
+		if err != nil {
+			return err
+		}
+
+		keys = append(keys, from)
+	}
+
+	return s.removeAll(keys)
+}
+
+// ClearNodes removes all nodes (including folders) via a single multi-object delete.
+func (s *S3Service) ClearNodes() ([]models.Node, []error) {
+	nodes, _, err := s.GetAll("", "", models.NotyaIgnoreFiles)
+	if err != nil && err != assets.EmptyWorkingDirectory {
+		return nil, []error{err}
+	}
+
+	keys := []string{}
+	for _, n := range nodes {
+		keys = append(keys, s.Key(n.Title))
+		if n.IsFolder() {
+			keys = append(keys, s.Key(n.Title)+"/")
+		}
+	}
+
+	if err := s.removeAll(keys); err != nil {
+		return nil, []error{err}
+	}
+
+	tombstones, err := Tombstones(s)
+	if err != nil {
+		return nodes, []error{err}
+	}
+
+	now := time.Now().UTC()
+	for _, n := range nodes {
+		tombstones = tombstones.Add(n.Title, s.Type(), now)
+	}
+
+	if err := s.write(s.Key(models.TombstonesName), tombstones.ToString(), now); err != nil {
+		return nodes, []error{err}
+	}
+
+	return nodes, nil
+}
+
+// GetAll lists all objects under the prefix of notes, or the prefix of [additional] folder.
+// Titles of nodes are relative to [additional] folder, and folders are generated from
+// both marker objects and key prefixes of notes.
+func (s *S3Service) GetAll(additional, typ string, ignore []string) ([]models.Node, []string, error) {
+	scope := s.Key(additional) + "/"
+
+	objects, err := s.list(scope)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	folders, files := map[string]bool{}, map[string]minio.ObjectInfo{}
+	for _, obj := range objects {
+		names := splitTitle(strings.TrimPrefix(obj.Key, scope))
+		if len(names) == 0 {
+			continue
+		}
+
+		ignored := false
+		for _, name := range names {
+			ignored = ignored || pkg.IsIgnorable(name, ignore)
+		}
+
+		if ignored {
+			continue
+		}
+
+		// Each parent of object is a folder, even without a marker object.
+		for i := 1; i < len(names); i++ {
+			folders[strings.Join(names[:i], "/")] = true
+		}
+
+		if strings.HasSuffix(obj.Key, "/") {
+			folders[strings.Join(names, "/")] = true
+		} else {
+			files[strings.Join(names, "/")] = obj
+		}
+	}
+
+	nodes := []models.Node{}
+	for title := range folders {
+		if pkg.IsType(typ, true) {
+			nodes = append(nodes, models.Node{Title: nodeTitle(title, models.FOLDER), Type: models.FOLDER})
+		}
+	}
+
+	for title, obj := range files {
+		if !pkg.IsType(typ, false) {
+			continue
+		}
+
+		body, info, err := s.read(obj.Key)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		node := models.Node{Title: title, Type: models.FILE, Body: body}
+		node.Created, _ = time.Parse(time.RFC3339Nano, info.UserMetadata[s3CreatedMeta])
+		stamp(&node, info.LastModified.UTC())
+
+		nodes = append(nodes, node)
+	}
+
+	if len(nodes) == 0 {
+		return nil, nil, assets.EmptyWorkingDirectory
+	}
+
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Title < nodes[j].Title })
+
+	titles := []string{}
+	for i, n := range nodes {
+		names := splitTitle(n.Title)
+
+		nodes[i].Path = map[string]string{s.Type(): s.Key(additional) + "/" + n.Title}
+		nodes[i].Pretty = []string{strings.Repeat("  ", len(names)-1) + n.GenPretty(), names[len(names)-1]}
+		titles = append(titles, n.Title)
+	}
+
+	return nodes, titles, nil
+}
+
+// Create uploads a new note object, under the prefix of its parent folder.
+func (s *S3Service) Create(note models.Note) (*models.Note, error) {
+	if exists, err := s.IsNodeExists(note.ToNode()); err != nil {
+		return nil, err
+	} else if exists {
+		return nil, assets.AlreadyExists(note.Title, "file")
+	}
+
+	if err := s.checkParent(note.Title); err != nil {
+		return nil, err
+	}
+
+	key := s.Key(note.Title)
+	if err := s.write(key, note.Body, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return &models.Note{Title: note.Title, Path: map[string]string{s.Type(): key}, Body: note.Body}, ClearTombstone(s, note.Title)
+}
+
+// View downloads the note object at [note.Title].
+func (s *S3Service) View(note models.Note) (*models.Note, error) {
+	key := s.Key(note.Title)
+
+	body, _, err := s.read(key)
+	if isS3NotFound(err) {
+		return nil, assets.NotExists(note.Title, "File")
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &models.Note{Title: note.Title, Path: map[string]string{s.Type(): key}, Body: body}, nil
+}
+
+// Edit overwrites the existing note object, and keeps its previous body at history.
+func (s *S3Service) Edit(note models.Note) (*models.Note, error) {
+	key := s.Key(note.Title)
+
+	previous, info, err := s.read(key)
+	if isS3NotFound(err) {
+		return nil, assets.NotExists(note.Title, "File")
+	} else if err != nil {
+		return nil, err
+	}
+
+	created, err := time.Parse(time.RFC3339Nano, info.UserMetadata[s3CreatedMeta])
+	if err != nil {
+		created = info.LastModified
+	}
+
+	if err := s.write(key, note.Body, created); err != nil {
+		return nil, err
+	}
+
+	keepVersion(s, NewVersion(note.Title, previous, info.LastModified), note.Body)
+
+	return &models.Note{Title: note.Title, Path: map[string]string{s.Type(): key}, Body: note.Body}, nil
+}
+
+// Copy writes body of note to machine's clipboard.
+func (s *S3Service) Copy(note models.Note) error {
+	data, err := s.View(note)
+	if err != nil {
+		return err
+	}
+
+	return clipboard.WriteAll(data.Body)
+}
+
+// Cut, copies note data to machine's clipboard and removes it instantly.
+func (s *S3Service) Cut(note models.Note) (*models.Note, error) {
+	n, err := s.View(note)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := clipboard.WriteAll(n.Body); err != nil {
+		return nil, err
+	}
+
+	if err := s.Remove(note.ToNode()); err != nil {
+		return nil, err
+	}
+
+	return n, nil
+}
+
+// Mkdir uploads an empty marker object of folder, which's key ends with a slash.
+func (s *S3Service) Mkdir(dir models.Folder) (*models.Folder, error) {
+	if exists, err := s.IsNodeExists(dir.ToNode()); err != nil {
+		return nil, err
+	} else if exists {
+		return nil, assets.AlreadyExists(dir.Title, "folder")
+	}
+
+	if err := s.checkParent(dir.Title); err != nil {
+		return nil, err
+	}
+
+	key := s.Key(dir.Title) + "/"
+	if err := s.write(key, "", time.Now()); err != nil {
+		return nil, err
+	}
+
+	title := nodeTitle(dir.Title, models.FOLDER)
+	return &models.Folder{Title: title, Path: map[string]string{s.Type(): key}}, ClearTombstone(s, title)
+}
+
+// MoveNotes copies all objects (including hidden ones) from current bucket and prefix,
+// to the bucket and prefix of [settings], and removes the old objects.
+func (s *S3Service) MoveNotes(settings models.Settings) error {
+	if len(settings.S3Bucket) == 0 {
+		settings.S3Bucket = s.Config.S3Bucket
+	}
+
+	from, to := s.Config.S3Path()+"/", settings.S3Path()+"/"
+	if settings.S3Bucket == s.Config.S3Bucket && from == to {
+		return nil
+	}
+
+	objects, err := s.list(from)
+	if err != nil {
+		return err
+	}
+
+	keys := []string{}
+	for _, obj := range objects {
+		_, err := s.Client.CopyObject(
+			s.Ctx,
+			minio.CopyDestOptions{Bucket: settings.S3Bucket, Object: to + strings.TrimPrefix(obj.Key, from)},
+			minio.CopySrcOptions{Bucket: s.Config.S3Bucket, Object: obj.Key},
+		)
+		if err != nil {
+			return err
+		}
+
+		keys = append(keys, obj.Key)
+	}
+
+	if err := s.removeAll(keys); err != nil {
+		return err
+	}
+
+	s.Config.S3Bucket, s.Config.S3Prefix = settings.S3Bucket, settings.S3Prefix
+	return nil
+}
+
+// Fetch creates a clone of nodes(that doesn't exists on [s]) from given [remote] service.
+func (s *S3Service) Fetch(remote ServiceRepo) ([]models.Node, []error) {
+	return transfer("fetch", remote, s)
+}
+
+// Push uploads nodes(that doesn't exists on given remote) from [s] to given [remote].
+func (s *S3Service) Push(remote ServiceRepo) ([]models.Node, []error) {
+	return transfer("push", s, remote)
+}
+
+// Migrate overwrites all notes of given [remote] service with [s].
+// If migration fails halfway, [remote] is restored back to its previous state.
+func (s *S3Service) Migrate(remote ServiceRepo) ([]models.Node, []error) {
+	return Migrate(s, remote)
+}
+
+// readArchive reads the hidden object at [title] (see [archiveStore]).
+func (s *S3Service) readArchive(title string) (string, error) {
+	body, _, err := s.read(s.Key(title))
+	if isS3NotFound(err) {
+		return "", errArchiveNotFound
+	}
+
+	return body, err
+}
+
+// readArchives reads all hidden objects under [folder] (see [archiveStore]).
+// Objects that cannot be read are skipped.
+func (s *S3Service) readArchives(folder string) ([]string, error) {
+	objects, err := s.list(s.Key(folder) + "/")
+	if err != nil {
+		return nil, err
+	}
+
+	bodies := []string{}
+	for _, obj := range objects {
+		if body, _, err := s.read(obj.Key); err == nil {
+			bodies = append(bodies, body)
+		}
+	}
+
+	return bodies, nil
+}
+
+// writeArchive overwrites the hidden object at [title] (see [archiveStore]).
+// Object storage has no folders, so nothing is created besides the object.
+func (s *S3Service) writeArchive(title, body string) error {
+	return s.write(s.Key(title), body, time.Now())
+}
+
+// removeArchive removes the hidden object at [title] (see [archiveStore]).
+func (s *S3Service) removeArchive(title string) error {
+	return s.Client.RemoveObject(s.Ctx, s.Config.S3Bucket, s.Key(title), minio.RemoveObjectOptions{})
+}
+
+// PutTrash saves [entry] as a JSON object under trash prefix.
+func (s *S3Service) PutTrash(entry models.TrashEntry) error {
+	return archive{s}.PutTrash(entry)
+}
+
+// TrashEntries reads all entry objects under trash prefix.
+func (s *S3Service) TrashEntries() (models.Trash, error) {
+	return archive{s}.TrashEntries()
+}
+
+// DropTrash deletes the entry object of [id] from trash prefix.
+func (s *S3Service) DropTrash(id string) error {
+	return archive{s}.DropTrash(id)
+}
+
+// Versions reads the history object of note at [title].
+func (s *S3Service) Versions(title string) (models.History, error) {
+	return archive{s}.Versions(title)
+}
+
+// DropVersions removes the history object of note at [title].
+func (s *S3Service) DropVersions(title string) error {
+	return archive{s}.DropVersions(title)
+}
+
+// SaveVersion appends [version] to the history object of its note.
+func (s *S3Service) SaveVersion(version models.Version) error {
+	return archive{s}.SaveVersion(version)
+}
@@ -0,0 +1,313 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/lib/services"
+	"github.com/minio/minio-go/v7"
+)
+
+// newTestS3Service creates a S3 service, that's connected to an in-process fake object storage.
+func newTestS3Service(t *testing.T) *services.S3Service {
+	server := httptest.NewServer(&fakeS3{bucket: "notya", objects: map[string]fakeS3Object{}})
+	t.Cleanup(server.Close)
+
+	credentials := t.TempDir() + "/credentials"
+	if err := os.WriteFile(credentials, []byte("[default]\naws_access_key_id = notya\naws_secret_access_key = notya\n"), 0o600); err != nil {
+		t.Fatalf("Cannot write credentials file: %v", err)
+	}
+
+	local := newTestLocalService(t)
+
+	settings := local.Config
+	settings.S3Endpoint = server.URL
+	settings.S3Bucket = "notya"
+	settings.S3Region = "us-east-1"
+	settings.S3CredentialsFile = credentials
+
+	s := services.NewS3Service(local.Stdargs, local)
+	if err := s.Init(&settings); err != nil {
+		t.Fatalf("S3Service.Init returned an error: %v", err)
+	}
+
+	return s
+}
+
+func TestS3ServiceInit(t *testing.T) {
+	s := newTestS3Service(t)
+
+	tests := []struct {
+		settings models.Settings
+	}{
+		{settings: models.Settings{}},
+		{settings: models.Settings{S3Bucket: "notya", S3CredentialsFile: "/not/exists"}},
+		{settings: models.Settings{S3Bucket: "missing", S3Endpoint: s.Config.S3Endpoint, S3Region: "us-east-1", S3CredentialsFile: s.Config.S3CredentialsFile}},
+	}
+
+	for _, td := range tests {
+		if err := services.NewS3Service(s.Stdargs, s.LS).Init(&td.settings); err == nil {
+			t.Errorf("Init should fail for invalid settings: %v", td.settings)
+		}
+	}
+
+	settings, err := s.Settings(nil)
+	if err != nil || settings.S3Bucket != "notya" {
+		t.Errorf("Init should write settings to bucket, Got: %v (%v)", settings, err)
+	}
+}
+
+func TestS3ServiceKeys(t *testing.T) {
+	s := newTestS3Service(t)
+
+	s.Mkdir(models.Folder{Title: "ideas"})
+	s.Create(models.Note{Title: "ideas/a.md", Body: "A"})
+	s.Edit(models.Note{Title: "ideas/a.md", Body: "A2"})
+
+	tests := []struct {
+		key  string
+		body string
+	}{
+		{key: s.Key("ideas") + "/", body: ""},  // folder marker.
+		{key: s.Key("ideas/a.md"), body: "A2"}, // note object.
+	}
+
+	for _, td := range tests {
+		obj, err := s.Client.GetObject(s.Ctx, "notya", td.key, minio.GetObjectOptions{})
+		if err != nil {
+			t.Fatalf("Cannot get object %v: %v", td.key, err)
+		}
+
+		body, err := io.ReadAll(obj)
+		if obj.Close(); err != nil || string(body) != td.body {
+			t.Errorf("Object %v was different: Want: %q | Got: %q (%v)", td.key, td.body, body, err)
+		}
+	}
+
+	// History is kept under the hidden prefix, next to notes.
+	history := s.Key(models.HistoryName + "/ideas/a.md.json")
+	if _, err := s.Client.StatObject(s.Ctx, "notya", history, minio.StatObjectOptions{}); err != nil {
+		t.Errorf("History should be kept at %v, Got: %v", history, err)
+	}
+}
+
+func TestS3ServicePrefixFolders(t *testing.T) {
+	s := newTestS3Service(t)
+
+	// Objects uploaded by other tools don't have folder marker objects.
+	s.Client.PutObject(s.Ctx, "notya", s.Key("inbox/today/a.md"), nil, 0, minio.PutObjectOptions{})
+
+	if exists, _ := s.IsNodeExists(models.Node{Title: "inbox/today"}); !exists {
+		t.Errorf("Key prefixes should exist as folders")
+	}
+
+	nodes, _, err := s.GetAll("", "", models.NotyaIgnoreFiles)
+	if err != nil || fmt.Sprint(titles(nodes)) != "[inbox/ inbox/today/ inbox/today/a.md]" {
+		t.Errorf("GetAll should list key prefixes as folders, Got: %v (%v)", titles(nodes), err)
+	}
+}
+
+func TestS3ServiceMoveNotes(t *testing.T) {
+	s := newTestS3Service(t)
+	s.Create(models.Note{Title: "a.md", Body: "A"})
+
+	settings := s.StateConfig()
+	settings.S3Prefix = "work/notes"
+
+	if err := s.MoveNotes(settings); err != nil {
+		t.Fatalf("MoveNotes returned an error: %v", err)
+	}
+
+	if a, err := s.View(models.Note{Title: "a.md"}); err != nil || a.Path[s.Type()] != "work/notes/a.md" {
+		t.Errorf("MoveNotes should move notes to new prefix, Got: %v (%v)", a, err)
+	}
+}
+
+// fakeS3Object is an object of [fakeS3] bucket.
+type fakeS3Object struct {
+	body     []byte
+	meta     http.Header
+	modified time.Time
+}
+
+// fakeS3 is a minimal in-memory S3 server of a single bucket.
+// It implements only those requests, which are used by [services.S3Service].
+type fakeS3 struct {
+	mu      sync.Mutex
+	bucket  string
+	objects map[string]fakeS3Object
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/")
+	bucket, key := path, ""
+	if i := strings.Index(path, "/"); i >= 0 {
+		bucket, key = path[:i], path[i+1:]
+	}
+
+	if bucket != f.bucket {
+		f.fail(w, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+
+	switch {
+	case len(key) == 0 && r.Method == http.MethodHead:
+		w.WriteHeader(http.StatusOK)
+	case len(key) == 0 && r.Method == http.MethodGet:
+		f.list(w, r.URL.Query().Get("prefix"))
+	case len(key) == 0 && r.Method == http.MethodPost:
+		f.deleteMulti(w, r)
+	case r.Method == http.MethodPut:
+		f.put(w, r, key)
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		f.get(w, r, key)
+	}
+}
+
+func (f *fakeS3) fail(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "<Error><Code>%v</Code><Message>%v</Message></Error>", code, code)
+}
+
+func (f *fakeS3) put(w http.ResponseWriter, r *http.Request, key string) {
+	if source := r.Header.Get("X-Amz-Copy-Source"); len(source) > 0 {
+		source, _ = url.PathUnescape(source)
+
+		obj, ok := f.objects[strings.TrimPrefix(strings.TrimPrefix(source, "/"), f.bucket+"/")]
+		if !ok {
+			f.fail(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+
+		obj.modified = time.Now().UTC()
+		f.objects[key] = obj
+
+		fmt.Fprintf(w, "<CopyObjectResult><LastModified>%v</LastModified><ETag>\"etag\"</ETag></CopyObjectResult>", obj.modified.Format(time.RFC3339))
+		return
+	}
+
+	body, _ := io.ReadAll(r.Body)
+
+	// Uploads of insecure connections are streamed as signed chunks, like: "<hex-size>;chunk-signature=...\r\n<data>\r\n".
+	if r.Header.Get("X-Amz-Content-Sha256") == "STREAMING-AWS4-HMAC-SHA256-PAYLOAD" {
+		data := []byte{}
+		for len(body) > 0 {
+			header := body[:bytes.Index(body, []byte("\r\n"))]
+			size, _ := strconv.ParseInt(strings.Split(string(header), ";")[0], 16, 64)
+
+			body = body[len(header)+2:]
+			data = append(data, body[:size]...)
+			body = body[size+2:]
+		}
+
+		body = data
+	}
+
+	meta := http.Header{}
+	for k, v := range r.Header {
+		if strings.HasPrefix(k, "X-Amz-Meta-") || k == "Content-Type" {
+			meta[k] = v
+		}
+	}
+
+	f.objects[key] = fakeS3Object{body: body, meta: meta, modified: time.Now().UTC()}
+	w.Header().Set("ETag", `"etag"`)
+}
+
+func (f *fakeS3) get(w http.ResponseWriter, r *http.Request, key string) {
+	obj, ok := f.objects[key]
+	if !ok {
+		f.fail(w, http.StatusNotFound, "NoSuchKey")
+		return
+	}
+
+	for k, v := range obj.meta {
+		w.Header()[k] = v
+	}
+
+	w.Header().Set("ETag", `"etag"`)
+	w.Header().Set("Last-Modified", obj.modified.Format(http.TimeFormat))
+	w.Header().Set("Content-Length", strconv.Itoa(len(obj.body)))
+
+	if r.Method != http.MethodHead {
+		w.Write(obj.body)
+	}
+}
+
+func (f *fakeS3) list(w http.ResponseWriter, prefix string) {
+	type content struct {
+		Key          string
+		LastModified string
+		ETag         string
+		Size         int
+	}
+
+	result := struct {
+		XMLName  xml.Name `xml:"ListBucketResult"`
+		Name     string
+		Prefix   string
+		KeyCount int
+		Contents []content
+	}{Name: f.bucket, Prefix: prefix}
+
+	keys := []string{}
+	for k := range f.objects {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		obj := f.objects[k]
+		result.Contents = append(result.Contents, content{
+			Key: k, LastModified: obj.modified.Format(time.RFC3339), ETag: `"etag"`, Size: len(obj.body),
+		})
+	}
+	result.KeyCount = len(keys)
+
+	xml.NewEncoder(w).Encode(result)
+}
+
+func (f *fakeS3) deleteMulti(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Objects []struct{ Key string } `xml:"Object"`
+	}
+
+	if err := xml.NewDecoder(r.Body).Decode(&request); err != nil {
+		f.fail(w, http.StatusBadRequest, "MalformedXML")
+		return
+	}
+
+	for _, o := range request.Objects {
+		delete(f.objects, o.Key)
+	}
+
+	fmt.Fprint(w, "<DeleteResult></DeleteResult>")
+}
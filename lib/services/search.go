@@ -0,0 +1,48 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services
+
+import (
+	"strings"
+
+	"github.com/insolite-dev/notya/assets"
+	"github.com/insolite-dev/notya/lib/models"
+)
+
+// Search looks for [opts.Query] at titles and bodies of nodes of provided service.
+// If [opts.In] is provided, search is scoped to that folder.
+func Search(s ServiceRepo, opts models.SearchOptions) ([]models.SearchResult, error) {
+	if len(opts.Query) == 0 {
+		return nil, assets.EmptySearchQuery
+	}
+
+	matcher, err := opts.Matcher()
+	if err != nil {
+		return nil, assets.CannotDoSth("search", opts.Query, err)
+	}
+
+	nodes, _, err := s.GetAll(opts.In, "", models.NotyaIgnoreFiles)
+	if err != nil && err.Error() != assets.EmptyWorkingDirectory.Error() {
+		return nil, err
+	}
+
+	scope := ""
+	if len(opts.In) > 0 {
+		scope = strings.Trim(opts.In, "/") + "/"
+	}
+
+	results := []models.SearchResult{}
+	for _, n := range nodes {
+		n.Title = scope + n.Title
+
+		if result := models.MatchNode(n, scope, matcher); result != nil {
+			results = append(results, *result)
+		}
+	}
+
+	return results, nil
+}
@@ -0,0 +1,76 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/lib/services"
+)
+
+func TestSearch(t *testing.T) {
+	local := newTestLocalService(t)
+
+	local.Mkdir(models.Folder{Title: "ideas/"})
+	local.Create(models.Note{Title: "ideas/a.md", Body: "some TODO here"})
+	local.Create(models.Note{Title: "todo.md", Body: "nothing"})
+
+	tests := []struct {
+		opts     models.SearchOptions
+		expected string
+		err      bool
+	}{
+		{opts: models.SearchOptions{Query: "todo"}, expected: "[todo.md]"},
+		{opts: models.SearchOptions{Query: "todo", IgnoreCase: true}, expected: "[todo.md ideas/a.md]"},
+		{opts: models.SearchOptions{Query: "todo", IgnoreCase: true, In: "ideas"}, expected: "[ideas/a.md]"},
+		{opts: models.SearchOptions{Query: "ideas", In: "ideas"}, expected: "[]"},
+		{opts: models.SearchOptions{Query: "a*", Regex: true}, expected: "[ideas/ ideas/a.md]"},
+		{opts: models.SearchOptions{Query: ""}, err: true},
+	}
+
+	for _, td := range tests {
+		results, err := services.Search(local, td.opts)
+		if (err != nil) != td.err {
+			t.Errorf("Search error was different: Got: %v", err)
+			continue
+		}
+
+		titles := []string{}
+		for _, r := range results {
+			titles = append(titles, r.Node.Title)
+		}
+
+		if got := fmt.Sprint(titles); !td.err && got != td.expected {
+			t.Errorf("Search sum was different: Want: %v | Got: %v", td.expected, got)
+		}
+	}
+}
+
+func TestSearchNestedFolderOfSameName(t *testing.T) {
+	local := newTestLocalService(t)
+
+	local.Mkdir(models.Folder{Title: "work/"})
+	local.Mkdir(models.Folder{Title: "work/work/"})
+	local.Create(models.Note{Title: "work/work/plan.md", Body: "plan"})
+	local.Create(models.Note{Title: "work/plan.md", Body: "plan"})
+
+	results, err := services.Search(local, models.SearchOptions{Query: "plan", In: "work"})
+	if err != nil {
+		t.Fatalf("Search returned an error: %v", err)
+	}
+
+	titles := []string{}
+	for _, r := range results {
+		titles = append(titles, r.Node.Title)
+	}
+
+	if expected := "[work/plan.md work/work/plan.md]"; fmt.Sprint(titles) != expected {
+		t.Errorf("Search sum was different: Want: %v | Got: %v", expected, titles)
+	}
+}
@@ -0,0 +1,728 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services
+
+import (
+	"errors"
+	"io"
+	"net"
+	"os"
+	"os/user"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+	"github.com/insolite-dev/notya/assets"
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/pkg"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// SFTPService is a class implementation of service repo.
+// Which keeps nodes at a directory of remote host, over SSH.
+//
+//	╭──────────────╮     ╭──────────────────────────────────╮
+//	│ SFTP Service │ ──▶ │ john-doe@dev.example.com:22      │
+//	╰──────────────╯     │  /srv/notya/ideas/    (folder)   │
+//	                     │  /srv/notya/ideas/note.md (note) │
+//	                     ╰──────────────────────────────────╯
+//
+// Connection is authenticated via the key file of settings, or via keys of ssh-agent,
+// and host key of server is verified via known hosts file.
+type SFTPService struct {
+	LS      ServiceRepo // embedded local service.
+	Stdargs models.StdArgs
+	Config  models.Settings
+
+	// SSH related.
+	SSH    *ssh.Client
+	Client *sftp.Client
+}
+
+// Mark [SFTPService] as [ServiceRepo], [Trasher] and [Versioner].
+var (
+	_ ServiceRepo = &SFTPService{}
+	_ Trasher     = &SFTPService{}
+	_ Versioner   = &SFTPService{}
+)
+
+// NewSFTPService creates new SFTP service by given arguments.
+func NewSFTPService(stdargs models.StdArgs, ls ServiceRepo) *SFTPService {
+	return &SFTPService{LS: ls, Stdargs: stdargs}
+}
+
+// Type returns type of SFTPService - SFTP.
+func (s *SFTPService) Type() string {
+	return SFTP.ToStr()
+}
+
+// Path returns the address of SSH server as main, and the remote directory of notes as notes folder of service.
+func (s *SFTPService) Path() (string, string) {
+	return s.Config.SFTPAddress(), s.Config.SFTPPath
+}
+
+// StateConfig returns current configuration of state i.e [s.Config].
+func (s *SFTPService) StateConfig() models.Settings {
+	return s.Config
+}
+
+// Init connects to the SSH server of settings, creates the remote directory of notes
+// (if it doesn't exist), and reads (or writes) settings of it.
+func (s *SFTPService) Init(settings *models.Settings) error {
+	if settings != nil {
+		s.Config = *settings
+	} else {
+		localConfig, err := s.LS.Settings(nil)
+		if err != nil {
+			return err
+		}
+
+		s.Config = *localConfig // should be re-written later.
+	}
+
+	if len(s.Config.SFTPHost) == 0 || !path.IsAbs(s.Config.SFTPPath) {
+		return assets.InvalidSFTPSettings
+	}
+
+	if err := s.InitSFTP(); err != nil {
+		return err
+	}
+
+	if err := s.Client.MkdirAll(s.Config.SFTPPath); err != nil {
+		return err
+	}
+
+	config, err := s.Settings(nil)
+	if errors.Is(err, os.ErrNotExist) {
+		return s.WriteSettings(s.Config)
+	} else if err != nil {
+		return err
+	}
+
+	s.Config = *config // set remote settings data instead of local.
+	return nil
+}
+
+// InitSFTP connects to the SSH server, and opens a SFTP session as [s.Client].
+func (s *SFTPService) InitSFTP() error {
+	auth, err := s.auth()
+	if err != nil {
+		return err
+	}
+
+	knownHosts := s.Config.SFTPKnownHosts
+	if len(knownHosts) == 0 {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return err
+		}
+
+		knownHosts = filepath.Join(home, ".ssh", "known_hosts")
+	}
+
+	hostKeyCallback, err := knownhosts.New(knownHosts)
+	if err != nil {
+		return err
+	}
+
+	username := s.Config.SFTPUser
+	if len(username) == 0 {
+		if u, err := user.Current(); err == nil {
+			username = u.Username
+		}
+	}
+
+	conn, err := ssh.Dial("tcp", s.Config.SFTPAddress(), &ssh.ClientConfig{
+		User:            username,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         30 * time.Second,
+	})
+	if err != nil {
+		return err
+	}
+
+	client, err := sftp.NewClient(conn)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	s.SSH, s.Client = conn, client
+	return nil
+}
+
+// auth generates authentication methods of connection.
+// Key file of settings is preferred over ssh-agent.
+func (s *SFTPService) auth() ([]ssh.AuthMethod, error) {
+	if len(s.Config.SFTPKeyFile) > 0 {
+		key, err := os.ReadFile(s.Config.SFTPKeyFile)
+		if err != nil {
+			return nil, assets.SSHKeyNotExists
+		}
+
+		signer, err := ssh.ParsePrivateKey(key)
+		if err != nil {
+			return nil, err
+		}
+
+		return []ssh.AuthMethod{ssh.PublicKeys(signer)}, nil
+	}
+
+	socket := os.Getenv("SSH_AUTH_SOCK")
+	if len(socket) == 0 {
+		return nil, assets.SSHAgentNotAvailable
+	}
+
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		return nil, assets.SSHAgentNotAvailable
+	}
+
+	return []ssh.AuthMethod{ssh.PublicKeysCallback(agent.NewClient(conn).Signers)}, nil
+}
+
+// Close closes the SFTP session and SSH connection of service.
+func (s *SFTPService) Close() error {
+	if s.Client != nil {
+		s.Client.Close()
+	}
+
+	if s.SSH != nil {
+		return s.SSH.Close()
+	}
+
+	return nil
+}
+
+// Remote generates the remote path of node at [title].
+func (s *SFTPService) Remote(title string) string {
+	return path.Join(append([]string{s.Config.SFTPPath}, splitTitle(title)...)...)
+}
+
+// read reads the body of remote file at [title].
+func (s *SFTPService) read(title string) (string, error) {
+	file, err := s.Client.Open(s.Remote(title))
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	body, err := io.ReadAll(file)
+	return string(body), err
+}
+
+// write overwrites (or creates) the remote file at [title] with [body].
+// If [exclusive] is true, existing files aren't overwritten.
+func (s *SFTPService) write(title, body string, exclusive bool) error {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if exclusive {
+		flags |= os.O_EXCL
+	}
+
+	file, err := s.Client.OpenFile(s.Remote(title), flags)
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write([]byte(body)); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// removeAll removes the remote file, or directory with its contents at [p].
+// Symbolic links are removed themselves, instead of their targets.
+func (s *SFTPService) removeAll(p string) error {
+	info, err := s.Client.Lstat(p)
+	if err != nil {
+		return err
+	}
+
+	if !info.IsDir() {
+		return s.Client.Remove(p)
+	}
+
+	children, err := s.Client.ReadDir(p)
+	if err != nil {
+		return err
+	}
+
+	for _, child := range children {
+		if err := s.removeAll(path.Join(p, child.Name())); err != nil {
+			return err
+		}
+	}
+
+	return s.Client.RemoveDirectory(p)
+}
+
+// checkParent checks if the parent folder of node at [title] exists.
+func (s *SFTPService) checkParent(title string) error {
+	parent := parentTitle(title)
+	if len(parent) == 0 {
+		return nil
+	}
+
+	if info, err := s.Client.Stat(s.Remote(parent)); err != nil || !info.IsDir() {
+		return assets.NotExists(parent, "Directory")
+	}
+
+	return nil
+}
+
+// Settings reads settings file, which is stored by [p] (or [models.SettingsName]) at remote directory of notes.
+func (s *SFTPService) Settings(p *string) (*models.Settings, error) {
+	name := models.SettingsName
+	if p != nil && len(*p) != 0 {
+		name = *p
+	}
+
+	body, err := s.read(name)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := models.DecodeSettings(body)
+	return &settings, nil
+}
+
+// WriteSettings overwrites settings file by given settings model, without its secrets.
+func (s *SFTPService) WriteSettings(settings models.Settings) error {
+	if !settings.IsValid() {
+		return assets.InvalidSettingsData
+	}
+
+	settings = settings.WithoutSecrets()
+	return s.write(models.SettingsName, settings.ToString(), false)
+}
+
+// OpenSettings opens remote settings file via editor,
+// and overwrites remote settings file, if they were updated.
+func (s *SFTPService) OpenSettings(settings models.Settings) error {
+	return openSettingsViaTemp(s, s.Stdargs)
+}
+
+// IsNodeExists checks if a remote file (or directory) exists for node at [node.Title].
+func (s *SFTPService) IsNodeExists(node models.Node) (bool, error) {
+	_, err := s.Client.Stat(s.Remote(node.Title))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// Open opens the remote note file via editor (at a temporary file),
+// and overwrites the remote file, after editing.
+func (s *SFTPService) Open(node models.Node) error {
+	return openViaTemp(s, s.Stdargs, node)
+}
+
+// Remove deletes the remote file of note, or the remote directory of folder with its contents.
+func (s *SFTPService) Remove(node models.Node) error {
+	info, err := s.Client.Stat(s.Remote(node.Title))
+	if errors.Is(err, os.ErrNotExist) || len(splitTitle(node.Title)) == 0 {
+		return assets.NotExists(node.Title, "File or Directory")
+	} else if err != nil {
+		return err
+	}
+
+	if err := s.removeAll(s.Remote(node.Title)); err != nil {
+		return err
+	}
+
+	tombstone := node.ToNote().Title
+	if info.IsDir() {
+		tombstone = nodeTitle(node.Title, models.FOLDER)
+	}
+
+	return RecordTombstone(s, tombstone)
+}
+
+// Rename renames the remote file (or directory) of node via a single SFTP rename request.
+// History of renamed node is moved along.
+func (s *SFTPService) Rename(editNode models.EditNode) error {
+	if editNode.Current.Title == editNode.New.Title {
+		return assets.SameTitles
+	}
+
+	info, err := s.Client.Stat(s.Remote(editNode.Current.Title))
+	if errors.Is(err, os.ErrNotExist) {
+		return assets.NotExists(editNode.Current.Title, "File or Directory")
+	} else if err != nil {
+		return err
+	}
+
+	if exists, _ := s.IsNodeExists(editNode.New); exists {
+		return assets.AlreadyExists(editNode.New.Title, "File or Directory")
+	}
+
+	from := strings.Join(splitTitle(editNode.Current.Title), "/")
+	to := strings.Join(splitTitle(editNode.New.Title), "/")
+
+	// Folders cannot be moved into themselves.
+	if strings.HasPrefix(to, from+"/") {
+		return assets.InvalidPathForAct
+	}
+
+	if err := s.checkParent(to); err != nil {
+		return err
+	}
+
+	if err := s.Client.Rename(s.Remote(from), s.Remote(to)); err != nil {
+		return err
+	}
+
+	renameHistory(s, from, to, info.IsDir(),
+		func(title string) error { return s.Client.MkdirAll(s.Remote(title)) },
+		func(src, dst string) error { return s.Client.Rename(s.Remote(src), s.Remote(dst)) },
+	)
+
+	// Old title doesn't exist anymore, so it's recorded as removed.
+	tombstone, created := from, to
+	if info.IsDir() {
+		tombstone, created = nodeTitle(from, models.FOLDER), nodeTitle(to, models.FOLDER)
+	}
+
+	return MoveTombstone(s, tombstone, created)
+}
+
+// ClearNodes removes all top-level remote files and directories, with their contents.
+func (s *SFTPService) ClearNodes() ([]models.Node, []error) {
+	nodes, _, err := s.GetAll("", "", models.NotyaIgnoreFiles)
+	if err != nil && err != assets.EmptyWorkingDirectory {
+		return nil, []error{err}
+	}
+
+	tombstones, err := Tombstones(s)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	var res []models.Node
+	var errs []error
+
+	now := time.Now().UTC()
+	for _, n := range nodes {
+		// Sub nodes are removed along with top-level directories.
+		if !strings.Contains(strings.TrimSuffix(n.Title, "/"), "/") {
+			if err := s.removeAll(s.Remote(n.Title)); err != nil {
+				errs = append(errs, assets.CannotDoSth("remove", n.Title, err))
+				continue
+			}
+		}
+
+		res = append(res, n)
+		tombstones = tombstones.Add(n.Title, s.Type(), now)
+	}
+
+	if err := s.write(models.TombstonesName, tombstones.ToString(), false); err != nil {
+		errs = append(errs, err)
+	}
+
+	return res, errs
+}
+
+// GetAll walks through the remote directory of notes, or the directory of [additional] folder.
+// Titles of nodes are relative to [additional] folder.
+func (s *SFTPService) GetAll(additional, typ string, ignore []string) ([]models.Node, []string, error) {
+	root := s.Remote(additional)
+	if info, err := s.Client.Stat(root); err != nil || !info.IsDir() {
+		return nil, nil, assets.NotExists(additional, "Directory")
+	}
+
+	nodes := []models.Node{}
+
+	walker := s.Client.Walk(root)
+	for walker.Step() {
+		if err := walker.Err(); err != nil {
+			return nil, nil, err
+		}
+
+		p, info := walker.Path(), walker.Stat()
+		if p == root {
+			continue
+		}
+
+		if pkg.IsIgnorable(info.Name(), ignore) {
+			if info.IsDir() {
+				walker.SkipDir()
+			}
+			continue
+		}
+
+		if !pkg.IsType(typ, info.IsDir()) {
+			continue
+		}
+
+		title := strings.TrimPrefix(p, root+"/")
+		if info.IsDir() {
+			nodes = append(nodes, models.Node{Title: nodeTitle(title, models.FOLDER), Type: models.FOLDER, Updated: info.ModTime().UTC()})
+			continue
+		}
+
+		body, err := s.read(path.Join(additional, title))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		node := models.Node{Title: title, Type: models.FILE, Body: body}
+		stamp(&node, info.ModTime().UTC())
+
+		nodes = append(nodes, node)
+	}
+
+	if len(nodes) == 0 {
+		return nil, nil, assets.EmptyWorkingDirectory
+	}
+
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Title < nodes[j].Title })
+
+	titles := []string{}
+	for i, n := range nodes {
+		names := splitTitle(n.Title)
+
+		nodes[i].Path = map[string]string{s.Type(): path.Join(root, n.Title)}
+		nodes[i].Pretty = []string{strings.Repeat("  ", len(names)-1) + n.GenPretty(), names[len(names)-1]}
+		titles = append(titles, n.Title)
+	}
+
+	return nodes, titles, nil
+}
+
+// Create creates a new remote note file, at the existing directory of its parent folder.
+func (s *SFTPService) Create(note models.Note) (*models.Note, error) {
+	if exists, err := s.IsNodeExists(note.ToNode()); err != nil {
+		return nil, err
+	} else if exists {
+		return nil, assets.AlreadyExists(note.Title, "file")
+	}
+
+	if err := s.checkParent(note.Title); err != nil {
+		return nil, err
+	}
+
+	if err := s.write(note.Title, note.Body, true); err != nil {
+		return nil, err
+	}
+
+	return &models.Note{Title: note.Title, Path: map[string]string{s.Type(): s.Remote(note.Title)}, Body: note.Body}, ClearTombstone(s, note.Title)
+}
+
+// View reads the remote note file at [note.Title].
+func (s *SFTPService) View(note models.Note) (*models.Note, error) {
+	if info, err := s.Client.Stat(s.Remote(note.Title)); err != nil || info.IsDir() {
+		return nil, assets.NotExists(note.Title, "File")
+	}
+
+	body, err := s.read(note.Title)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Note{Title: note.Title, Path: map[string]string{s.Type(): s.Remote(note.Title)}, Body: body}, nil
+}
+
+// Edit overwrites the existing remote note file, and keeps its previous body at history.
+func (s *SFTPService) Edit(note models.Note) (*models.Note, error) {
+	info, err := s.Client.Stat(s.Remote(note.Title))
+	if err != nil || info.IsDir() {
+		return nil, assets.NotExists(note.Title, "File")
+	}
+
+	previous, err := s.read(note.Title)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.write(note.Title, note.Body, false); err != nil {
+		return nil, err
+	}
+
+	keepVersion(s, NewVersion(note.Title, previous, info.ModTime()), note.Body)
+
+	return &models.Note{Title: note.Title, Path: map[string]string{s.Type(): s.Remote(note.Title)}, Body: note.Body}, nil
+}
+
+// Copy writes body of note to machine's clipboard.
+func (s *SFTPService) Copy(note models.Note) error {
+	data, err := s.View(note)
+	if err != nil {
+		return err
+	}
+
+	return clipboard.WriteAll(data.Body)
+}
+
+// Cut, copies note data to machine's clipboard and removes it instantly.
+func (s *SFTPService) Cut(note models.Note) (*models.Note, error) {
+	n, err := s.View(note)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := clipboard.WriteAll(n.Body); err != nil {
+		return nil, err
+	}
+
+	if err := s.Remove(note.ToNode()); err != nil {
+		return nil, err
+	}
+
+	return n, nil
+}
+
+// Mkdir creates a new remote directory, at the existing directory of its parent folder.
+func (s *SFTPService) Mkdir(dir models.Folder) (*models.Folder, error) {
+	if exists, err := s.IsNodeExists(dir.ToNode()); err != nil {
+		return nil, err
+	} else if exists {
+		return nil, assets.AlreadyExists(dir.Title, "folder")
+	}
+
+	if err := s.checkParent(dir.Title); err != nil {
+		return nil, err
+	}
+
+	if err := s.Client.Mkdir(s.Remote(dir.Title)); err != nil {
+		return nil, err
+	}
+
+	title := nodeTitle(dir.Title, models.FOLDER)
+	return &models.Folder{Title: title, Path: map[string]string{s.Type(): s.Remote(title) + "/"}}, ClearTombstone(s, title)
+}
+
+// MoveNotes renames the remote directory of notes to the path of [settings], via a single SFTP rename request.
+// So, notes could be moved only in the same host.
+func (s *SFTPService) MoveNotes(settings models.Settings) error {
+	if !pkg.IsPathUpdated(s.Config, settings, s.Type()) {
+		return nil
+	}
+
+	if !path.IsAbs(settings.SFTPPath) {
+		return assets.InvalidSFTPSettings
+	}
+
+	if err := s.Client.MkdirAll(path.Dir(path.Clean(settings.SFTPPath))); err != nil {
+		return err
+	}
+
+	if err := s.Client.Rename(path.Clean(s.Config.SFTPPath), path.Clean(settings.SFTPPath)); err != nil {
+		return err
+	}
+
+	s.Config.SFTPPath = settings.SFTPPath
+	return nil
+}
+
+// Fetch creates a clone of nodes(that doesn't exists on [s]) from given [remote] service.
+func (s *SFTPService) Fetch(remote ServiceRepo) ([]models.Node, []error) {
+	return transfer("fetch", remote, s)
+}
+
+// Push uploads nodes(that doesn't exists on given remote) from [s] to given [remote].
+func (s *SFTPService) Push(remote ServiceRepo) ([]models.Node, []error) {
+	return transfer("push", s, remote)
+}
+
+// Migrate overwrites all notes of given [remote] service with [s].
+// If migration fails halfway, [remote] is restored back to its previous state.
+func (s *SFTPService) Migrate(remote ServiceRepo) ([]models.Node, []error) {
+	return Migrate(s, remote)
+}
+
+// readArchive reads the hidden file at [title] (see [archiveStore]).
+func (s *SFTPService) readArchive(title string) (string, error) {
+	body, err := s.read(title)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", errArchiveNotFound
+	}
+
+	return body, err
+}
+
+// readArchives reads all files of hidden directory at [folder] (see [archiveStore]).
+// Files that cannot be read are skipped.
+func (s *SFTPService) readArchives(folder string) ([]string, error) {
+	bodies := []string{}
+
+	files, err := s.Client.ReadDir(s.Remote(folder))
+	if errors.Is(err, os.ErrNotExist) {
+		return bodies, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
+
+		if body, err := s.read(folder + "/" + f.Name()); err == nil {
+			bodies = append(bodies, body)
+		}
+	}
+
+	return bodies, nil
+}
+
+// writeArchive overwrites the hidden file at [title], creating its parent directories (see [archiveStore]).
+func (s *SFTPService) writeArchive(title, body string) error {
+	if err := s.Client.MkdirAll(s.Remote(parentTitle(title))); err != nil {
+		return err
+	}
+
+	return s.write(title, body, false)
+}
+
+// removeArchive removes the hidden file at [title] (see [archiveStore]).
+func (s *SFTPService) removeArchive(title string) error {
+	if err := s.Client.Remove(s.Remote(title)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// PutTrash saves [entry] as a JSON file at hidden trash directory.
+func (s *SFTPService) PutTrash(entry models.TrashEntry) error {
+	return archive{s}.PutTrash(entry)
+}
+
+// TrashEntries reads all entry files of hidden trash directory.
+func (s *SFTPService) TrashEntries() (models.Trash, error) {
+	return archive{s}.TrashEntries()
+}
+
+// DropTrash removes the entry file of [id] from hidden trash directory.
+func (s *SFTPService) DropTrash(id string) error {
+	return archive{s}.DropTrash(id)
+}
+
+// Versions reads the history file of note at [title].
+func (s *SFTPService) Versions(title string) (models.History, error) {
+	return archive{s}.Versions(title)
+}
+
+// DropVersions removes the history file of note at [title].
+func (s *SFTPService) DropVersions(title string) error {
+	return archive{s}.DropVersions(title)
+}
+
+// SaveVersion appends [version] to the history file of its note.
+func (s *SFTPService) SaveVersion(version models.Version) error {
+	return archive{s}.SaveVersion(version)
+}
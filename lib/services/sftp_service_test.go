@@ -0,0 +1,281 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services_test
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/lib/services"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// testSFTPServer is an in-process SSH server, that serves SFTP subsystem on the real file system.
+// It accepts only the public key of [key].
+type testSFTPServer struct {
+	addr       string
+	key        ed25519.PrivateKey
+	keyFile    string
+	knownHosts string
+}
+
+// newTestSFTPServer starts a [testSFTPServer], and writes its client key and known hosts files.
+func newTestSFTPServer(t *testing.T) *testSFTPServer {
+	_, hostKey, _ := ed25519.GenerateKey(rand.Reader)
+	_, clientKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	hostSigner, _ := ssh.NewSignerFromKey(hostKey)
+	clientSigner, _ := ssh.NewSignerFromKey(clientKey)
+
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if conn.User() != "notya" || !bytes.Equal(key.Marshal(), clientSigner.PublicKey().Marshal()) {
+				return nil, fmt.Errorf("unknown public key of %v", conn.User())
+			}
+
+			return nil, nil
+		},
+	}
+	config.AddHostKey(hostSigner)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Cannot start SSH server: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go serveSFTP(conn, config)
+		}
+	}()
+
+	dir := t.TempDir()
+	server := &testSFTPServer{
+		addr:       listener.Addr().String(),
+		key:        clientKey,
+		keyFile:    filepath.Join(dir, "id_ed25519"),
+		knownHosts: filepath.Join(dir, "known_hosts"),
+	}
+
+	der, _ := x509.MarshalPKCS8PrivateKey(clientKey)
+	if err := os.WriteFile(server.keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("Cannot write key file: %v", err)
+	}
+
+	line := knownhosts.Line([]string{knownhosts.Normalize(server.addr)}, hostSigner.PublicKey())
+	if err := os.WriteFile(server.knownHosts, []byte(line+"\n"), 0o600); err != nil {
+		t.Fatalf("Cannot write known hosts file: %v", err)
+	}
+
+	return server
+}
+
+// serveSFTP serves SFTP subsystem of sessions of [conn].
+func serveSFTP(conn net.Conn, config *ssh.ServerConfig) {
+	_, channels, requests, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(requests)
+
+	for ch := range channels {
+		if ch.ChannelType() != "session" {
+			ch.Reject(ssh.UnknownChannelType, "unknown channel type")
+			continue
+		}
+
+		channel, requests, err := ch.Accept()
+		if err != nil {
+			continue
+		}
+
+		go func() {
+			for req := range requests {
+				ok := req.Type == "subsystem" && string(req.Payload[4:]) == "sftp"
+				req.Reply(ok, nil)
+
+				if ok {
+					if server, err := sftp.NewServer(channel); err == nil {
+						server.Serve()
+					}
+					channel.Close()
+				}
+			}
+		}()
+	}
+}
+
+// settings generates SFTP settings of [local] settings, that connect to the server.
+func (server *testSFTPServer) settings(t *testing.T, local models.Settings) models.Settings {
+	local.SFTPHost = server.addr
+	local.SFTPUser = "notya"
+	local.SFTPKeyFile = server.keyFile
+	local.SFTPKnownHosts = server.knownHosts
+	local.SFTPPath = filepath.ToSlash(t.TempDir()) + "/notes"
+
+	return local
+}
+
+// newTestSFTPService creates a SFTP service, that's connected to an in-process SSH server.
+func newTestSFTPService(t *testing.T) *services.SFTPService {
+	server := newTestSFTPServer(t)
+	local := newTestLocalService(t)
+
+	settings := server.settings(t, local.Config)
+
+	s := services.NewSFTPService(local.Stdargs, local)
+	if err := s.Init(&settings); err != nil {
+		t.Fatalf("SFTPService.Init returned an error: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	return s
+}
+
+func TestSFTPServiceInit(t *testing.T) {
+	server := newTestSFTPServer(t)
+	local := newTestLocalService(t)
+
+	valid := server.settings(t, local.Config)
+
+	unknownUser := valid
+	unknownUser.SFTPUser = "root"
+
+	tests := []struct {
+		settings models.Settings
+	}{
+		{settings: models.Settings{}},
+		{settings: models.Settings{SFTPHost: server.addr, SFTPPath: "notes"}},
+		{settings: models.Settings{SFTPHost: server.addr, SFTPPath: "/notes", SFTPKeyFile: "/not/exists"}},
+		{settings: unknownUser},
+	}
+
+	for _, td := range tests {
+		if err := services.NewSFTPService(local.Stdargs, local).Init(&td.settings); err == nil {
+			t.Errorf("Init should fail for invalid settings: %v", td.settings)
+		}
+	}
+
+	s := services.NewSFTPService(local.Stdargs, local)
+	if err := s.Init(&valid); err != nil {
+		t.Fatalf("Init returned an error: %v", err)
+	}
+	defer s.Close()
+
+	if settings, err := s.Settings(nil); err != nil || settings.SFTPPath != valid.SFTPPath {
+		t.Errorf("Init should write settings to remote directory, Got: %v (%v)", settings, err)
+	}
+}
+
+func TestSFTPServiceHostKey(t *testing.T) {
+	server := newTestSFTPServer(t)
+	local := newTestLocalService(t)
+
+	_, otherKey, _ := ed25519.GenerateKey(rand.Reader)
+	otherSigner, _ := ssh.NewSignerFromKey(otherKey)
+
+	tests := []struct {
+		name       string
+		knownHosts string
+		valid      bool
+	}{
+		{name: "unknown host", knownHosts: ""},
+		{name: "changed host key", knownHosts: knownhosts.Line([]string{knownhosts.Normalize(server.addr)}, otherSigner.PublicKey())},
+		{name: "known host key", valid: true},
+	}
+
+	for _, td := range tests {
+		settings := server.settings(t, local.Config)
+		if !td.valid {
+			settings.SFTPKnownHosts = filepath.Join(t.TempDir(), "known_hosts")
+			os.WriteFile(settings.SFTPKnownHosts, []byte(td.knownHosts+"\n"), 0o600)
+		}
+
+		s := services.NewSFTPService(local.Stdargs, local)
+		if err := s.Init(&settings); (err == nil) != td.valid {
+			t.Errorf("Init of %v was different: Want valid: %v | Got: %v", td.name, td.valid, err)
+		} else if err == nil {
+			s.Close()
+		}
+	}
+}
+
+func TestSFTPServiceAgent(t *testing.T) {
+	server := newTestSFTPServer(t)
+	local := newTestLocalService(t)
+
+	keyring := agent.NewKeyring()
+	keyring.Add(agent.AddedKey{PrivateKey: server.key})
+
+	socket := filepath.Join(t.TempDir(), "agent.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("Cannot start SSH agent: %v", err)
+	}
+	defer listener.Close()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go agent.ServeAgent(keyring, conn)
+		}
+	}()
+
+	settings := server.settings(t, local.Config)
+	settings.SFTPKeyFile = ""
+
+	t.Setenv("SSH_AUTH_SOCK", "")
+	if err := services.NewSFTPService(local.Stdargs, local).Init(&settings); err == nil {
+		t.Errorf("Init should fail without key file and ssh-agent")
+	}
+
+	t.Setenv("SSH_AUTH_SOCK", socket)
+	s := services.NewSFTPService(local.Stdargs, local)
+	if err := s.Init(&settings); err != nil {
+		t.Fatalf("Init should authenticate via ssh-agent, Got: %v", err)
+	}
+
+	s.Close()
+}
+
+func TestSFTPServiceMoveNotes(t *testing.T) {
+	s := newTestSFTPService(t)
+	s.Create(models.Note{Title: "a.md", Body: "A"})
+
+	settings := s.StateConfig()
+	settings.SFTPPath = filepath.ToSlash(t.TempDir()) + "/work/notes"
+
+	if err := s.MoveNotes(settings); err != nil {
+		t.Fatalf("MoveNotes returned an error: %v", err)
+	}
+
+	if a, err := s.View(models.Note{Title: "a.md"}); err != nil || a.Path[s.Type()] != settings.SFTPPath+"/a.md" {
+		t.Errorf("MoveNotes should move notes to new path, Got: %v (%v)", a, err)
+	}
+}
@@ -0,0 +1,898 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+	"github.com/insolite-dev/notya/assets"
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/pkg"
+
+	// Pure-go SQLite driver, that's compiled with FTS5.
+	_ "modernc.org/sqlite"
+)
+
+// sqliteSchema creates tables of SQLite service, if they don't exist.
+//
+// Nodes are rows, that refer to their parent folder. Top-level nodes have
+// zero as parent. Bodies of nodes are mirrored to an external-content FTS5
+// table by triggers, so full-text search index is always up to date.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS nodes (
+	id      INTEGER PRIMARY KEY,
+	parent  INTEGER NOT NULL DEFAULT 0,
+	name    TEXT    NOT NULL,
+	type    TEXT    NOT NULL,
+	body    TEXT    NOT NULL DEFAULT '',
+	created INTEGER NOT NULL DEFAULT 0,
+	updated INTEGER NOT NULL DEFAULT 0,
+	size    INTEGER NOT NULL DEFAULT 0,
+	hash    TEXT    NOT NULL DEFAULT '',
+	UNIQUE (parent, name)
+);
+
+CREATE VIRTUAL TABLE IF NOT EXISTS nodes_fts USING fts5(
+	name, body, content='nodes', content_rowid='id'
+);
+
+CREATE TRIGGER IF NOT EXISTS nodes_fts_insert AFTER INSERT ON nodes BEGIN
+	INSERT INTO nodes_fts(rowid, name, body) VALUES (new.id, new.name, new.body);
+END;
+
+CREATE TRIGGER IF NOT EXISTS nodes_fts_delete AFTER DELETE ON nodes BEGIN
+	INSERT INTO nodes_fts(nodes_fts, rowid, name, body) VALUES ('delete', old.id, old.name, old.body);
+END;
+
+CREATE TRIGGER IF NOT EXISTS nodes_fts_update AFTER UPDATE OF name, body ON nodes BEGIN
+	INSERT INTO nodes_fts(nodes_fts, rowid, name, body) VALUES ('delete', old.id, old.name, old.body);
+	INSERT INTO nodes_fts(rowid, name, body) VALUES (new.id, new.name, new.body);
+END;
+
+CREATE TABLE IF NOT EXISTS settings (
+	name TEXT PRIMARY KEY,
+	data TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS versions (
+	title TEXT    NOT NULL,
+	rev   INTEGER NOT NULL,
+	data  TEXT    NOT NULL,
+	PRIMARY KEY (title, rev)
+);
+
+CREATE TABLE IF NOT EXISTS trash (
+	id   TEXT PRIMARY KEY,
+	data TEXT NOT NULL
+);
+`
+
+// sqliteTree lists all nodes under the parent of first argument,
+// with their titles relative to that parent, and their depth levels.
+const sqliteTree = `
+WITH RECURSIVE tree(id, parent, name, type, title, level) AS (
+	SELECT id, parent, name, type, name, 0 FROM nodes WHERE parent = ?
+	UNION ALL
+	SELECT n.id, n.parent, n.name, n.type, tree.title || '/' || n.name, tree.level + 1
+	FROM nodes n JOIN tree ON n.parent = tree.id
+)
+SELECT tree.id, tree.parent, tree.name, tree.type, tree.title, tree.level,
+	nodes.body, nodes.created, nodes.updated, nodes.size, nodes.hash
+FROM tree JOIN nodes ON nodes.id = tree.id
+ORDER BY tree.title`
+
+// sqliteDeleteTree deletes the node of first argument, with all of its sub nodes.
+const sqliteDeleteTree = `
+WITH RECURSIVE tree(id) AS (
+	SELECT ?
+	UNION ALL
+	SELECT n.id FROM nodes n JOIN tree ON n.parent = tree.id
+)
+DELETE FROM nodes WHERE id IN tree`
+
+// SQLiteService is a class implementation of service repo.
+// Which keeps the whole notes tree at a single SQLite database file,
+// so it could be copied between machines as one portable file.
+//
+//	╭────────────────╮     ╭──────────────────────────────────╮
+//	│ SQLite Service │ ──▶ │ .notya.db                        │
+//	╰────────────────╯     │  nodes: id, parent, name, body.. │
+//	                       │  nodes_fts: FTS5 index of nodes  │
+//	                       ╰──────────────────────────────────╯
+type SQLiteService struct {
+	LS      ServiceRepo // embedded local service.
+	Stdargs models.StdArgs
+	Config  models.Settings
+
+	// File is the path of opened database file.
+	File string
+	DB   *sql.DB
+}
+
+// Mark [SQLiteService] as [ServiceRepo], [Committer], [Trasher], [Versioner] and [FullTextSearcher].
+var (
+	_ ServiceRepo      = &SQLiteService{}
+	_ Committer        = &SQLiteService{}
+	_ Trasher          = &SQLiteService{}
+	_ Versioner        = &SQLiteService{}
+	_ FullTextSearcher = &SQLiteService{}
+)
+
+// querier is implemented by both [sql.DB] and [sql.Tx],
+// so helpers could be used in and out of transactions.
+type querier interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// sqliteRow is a minimal representation of a looked up node row.
+type sqliteRow struct {
+	ID      int64
+	Type    models.NodeType
+	Body    string
+	Updated time.Time
+}
+
+// NewSQLiteService creates new sqlite service by given arguments.
+func NewSQLiteService(stdargs models.StdArgs, ls ServiceRepo) *SQLiteService {
+	return &SQLiteService{LS: ls, Stdargs: stdargs}
+}
+
+// Type returns type of SQLiteService - SQLITE.
+func (s *SQLiteService) Type() string {
+	return SQLITE.ToStr()
+}
+
+// Path returns the path of database file, which is both main and notes "folder" of service.
+func (s *SQLiteService) Path() (string, string) {
+	return s.File, s.File
+}
+
+// StateConfig returns current configuration of state i.e [s.Config].
+func (s *SQLiteService) StateConfig() models.Settings {
+	return s.Config
+}
+
+// Init opens (or creates) the database file, and creates its tables.
+// Settings are kept at the database too, so copied files bring their settings.
+func (s *SQLiteService) Init(settings *models.Settings) error {
+	if settings != nil {
+		s.Config = *settings
+	} else {
+		localConfig, err := s.LS.Settings(nil)
+		if err != nil {
+			return err
+		}
+
+		s.Config = *localConfig
+	}
+
+	s.File = s.Config.SQLitePath
+	if len(s.File) == 0 {
+		notyaPath, _ := s.LS.Path()
+		s.File = pkg.NormalizePath(notyaPath) + models.SQLiteName
+	}
+
+	if err := s.open(s.File); err != nil {
+		return err
+	}
+
+	config, err := s.Settings(nil)
+	if err == sql.ErrNoRows {
+		return s.WriteSettings(s.Config)
+	} else if err != nil {
+		return err
+	}
+
+	s.Config = *config
+	return nil
+}
+
+// open opens the database file at [file], and creates missing tables.
+func (s *SQLiteService) open(file string) error {
+	db, err := sql.Open("sqlite", file)
+	if err != nil {
+		return err
+	}
+
+	// SQLite allows a single writer, so connections are not pooled.
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return err
+	}
+
+	s.DB = db
+	return nil
+}
+
+// transact runs [fn] at a single transaction, which is rolled back if [fn] fails.
+func (s *SQLiteService) transact(fn func(tx *sql.Tx) error) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Settings reads settings of database, which is stored by [p] (or [models.SettingsName]).
+func (s *SQLiteService) Settings(p *string) (*models.Settings, error) {
+	name := models.SettingsName
+	if p != nil && len(*p) != 0 {
+		name = *p
+	}
+
+	var data string
+	if err := s.DB.QueryRow(`SELECT data FROM settings WHERE name = ?`, name).Scan(&data); err != nil {
+		return nil, err
+	}
+
+	settings := models.DecodeSettings(data)
+	return &settings, nil
+}
+
+// WriteSettings overwrites settings of database by given settings model.
+func (s *SQLiteService) WriteSettings(settings models.Settings) error {
+	if !settings.IsValid() {
+		return assets.InvalidSettingsData
+	}
+
+	_, err := s.DB.Exec(
+		`INSERT INTO settings(name, data) VALUES (?, ?) ON CONFLICT(name) DO UPDATE SET data = excluded.data`,
+		models.SettingsName, settings.ToString(),
+	)
+
+	return err
+}
+
+// OpenSettings opens settings of database via editor,
+// and overwrites settings of database, if they were updated.
+func (s *SQLiteService) OpenSettings(settings models.Settings) error {
+	return openSettingsViaTemp(s, s.Stdargs)
+}
+
+// lookup finds the row of node at [title], by walking through its parent folders.
+// Empty title refers to the root, which's id is zero.
+func (s *SQLiteService) lookup(q querier, title string) (*sqliteRow, error) {
+	row := &sqliteRow{ID: 0, Type: models.FOLDER}
+
+	for _, name := range splitTitle(title) {
+		if row.Type != models.FOLDER {
+			return nil, sql.ErrNoRows
+		}
+
+		var updated int64
+		err := q.QueryRow(
+			`SELECT id, type, body, updated FROM nodes WHERE parent = ? AND name = ?`, row.ID, name,
+		).Scan(&row.ID, &row.Type, &row.Body, &updated)
+		if err != nil {
+			return nil, err
+		}
+
+		row.Updated = time.Unix(0, updated).UTC()
+	}
+
+	return row, nil
+}
+
+// parentOf finds the row of parent folder of node at [title], and the name of node.
+func (s *SQLiteService) parentOf(q querier, title string) (*sqliteRow, string, error) {
+	names := splitTitle(title)
+	if len(names) == 0 {
+		return nil, "", assets.InvalidPathForAct
+	}
+
+	parentTitle := strings.Join(names[:len(names)-1], "/")
+
+	parent, err := s.lookup(q, parentTitle)
+	if err == sql.ErrNoRows || (err == nil && parent.Type != models.FOLDER) {
+		return nil, "", assets.NotExists(parentTitle, "Directory")
+	} else if err != nil {
+		return nil, "", err
+	}
+
+	return parent, names[len(names)-1], nil
+}
+
+// insert creates a row of [node] at [title], under its parent folder.
+func (s *SQLiteService) insert(q querier, title string, node models.Node) error {
+	parent, name, err := s.parentOf(q, title)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.lookup(q, title); err == nil {
+		return assets.AlreadyExists(title, strings.ToLower(string(node.Type)))
+	}
+
+	stamp(&node, time.Now().UTC())
+
+	_, err = q.Exec(
+		`INSERT INTO nodes(parent, name, type, body, created, updated, size, hash) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		parent.ID, name, node.Type, node.Body, node.Created.UnixNano(), node.Updated.UnixNano(), node.Size, node.Hash,
+	)
+
+	return err
+}
+
+// update overwrites body of note row of [id].
+func (s *SQLiteService) update(q querier, id int64, body string) error {
+	node := models.Node{Type: models.FILE, Body: body}
+	stamp(&node, time.Now().UTC())
+
+	_, err := q.Exec(
+		`UPDATE nodes SET body = ?, updated = ?, size = ?, hash = ? WHERE id = ?`,
+		node.Body, node.Updated.UnixNano(), node.Size, node.Hash, id,
+	)
+
+	return err
+}
+
+// nodeTitle generates the title of node, ending with slash for folders.
+func nodeTitle(title string, typ models.NodeType) string {
+	title = strings.Trim(title, "/")
+	if typ == models.FOLDER {
+		return title + "/"
+	}
+
+	return title
+}
+
+// IsNodeExists checks if a row exists for node at [node.Title].
+func (s *SQLiteService) IsNodeExists(node models.Node) (bool, error) {
+	if len(strings.TrimSpace(node.Title)) == 0 {
+		return true, nil
+	}
+
+	_, err := s.lookup(s.DB, node.Title)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// Open opens the note via editor (at a temporary file),
+// and overwrites the note at database, after editing.
+func (s *SQLiteService) Open(node models.Node) error {
+	return openViaTemp(s, s.Stdargs, node)
+}
+
+// Remove deletes the row of [node] with rows of its sub nodes, at a single statement.
+func (s *SQLiteService) Remove(node models.Node) error {
+	row, err := s.lookup(s.DB, node.Title)
+	if err == sql.ErrNoRows {
+		return assets.NotExists(node.Title, "File or Directory")
+	} else if err != nil {
+		return err
+	}
+
+	if _, err := s.DB.Exec(sqliteDeleteTree, row.ID); err != nil {
+		return err
+	}
+
+	return RecordTombstone(s, nodeTitle(node.Title, row.Type))
+}
+
+// Rename moves the row of node to its new parent and name.
+// Sub nodes of folders refer to their parent row, so they're moved along without any extra writes.
+// History of renamed nodes is moved at the same transaction.
+func (s *SQLiteService) Rename(editNode models.EditNode) error {
+	if editNode.Current.Title == editNode.New.Title {
+		return assets.SameTitles
+	}
+
+	var current *sqliteRow
+	err := s.transact(func(tx *sql.Tx) error {
+		var err error
+		if current, err = s.lookup(tx, editNode.Current.Title); err == sql.ErrNoRows {
+			return assets.NotExists(editNode.Current.Title, "File or Directory")
+		} else if err != nil {
+			return err
+		}
+
+		if _, err := s.lookup(tx, editNode.New.Title); err == nil {
+			return assets.AlreadyExists(editNode.New.Title, "File or Directory")
+		}
+
+		from := strings.Trim(editNode.Current.Title, "/")
+		to := strings.Trim(editNode.New.Title, "/")
+
+		// Folders cannot be moved into themselves.
+		if strings.HasPrefix(to, from+"/") {
+			return assets.InvalidPathForAct
+		}
+
+		parent, name, err := s.parentOf(tx, to)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(
+			`UPDATE nodes SET parent = ?, name = ?, updated = ? WHERE id = ?`,
+			parent.ID, name, time.Now().UTC().UnixNano(), current.ID,
+		); err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(
+			`UPDATE versions SET title = ? || substr(title, ?) WHERE title = ? OR substr(title, 1, ?) = ?`,
+			to, len(from)+1, from, len(from)+1, from+"/",
+		)
+
+		return err
+	})
+
+	if err != nil {
+		return err
+	}
+
+	// Old title doesn't exist anymore, so it's recorded as removed.
+	return MoveTombstone(s, nodeTitle(editNode.Current.Title, current.Type), nodeTitle(editNode.New.Title, current.Type))
+}
+
+// ClearNodes removes all nodes (including folders) at a single transaction.
+func (s *SQLiteService) ClearNodes() ([]models.Node, []error) {
+	nodes, _, err := s.GetAll("", "", models.NotyaIgnoreFiles)
+	if err != nil && err != assets.EmptyWorkingDirectory {
+		return nil, []error{err}
+	}
+
+	tombstones, err := Tombstones(s)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	now := time.Now().UTC()
+	for _, n := range nodes {
+		tombstones = tombstones.Add(n.Title, s.Type(), now)
+	}
+
+	err = s.transact(func(tx *sql.Tx) error {
+		for _, n := range nodes {
+			// Sub nodes are deleted along with top-level nodes.
+			if strings.Contains(strings.TrimSuffix(n.Title, "/"), "/") {
+				continue
+			}
+
+			row, err := s.lookup(tx, n.Title)
+			if err != nil {
+				return assets.CannotDoSth("remove", n.Title, err)
+			}
+
+			if _, err := tx.Exec(sqliteDeleteTree, row.ID); err != nil {
+				return assets.CannotDoSth("remove", n.Title, err)
+			}
+		}
+
+		return s.put(tx, models.TombstonesName, tombstones.ToString())
+	})
+
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	return nodes, nil
+}
+
+// put creates or overwrites the note at [title].
+func (s *SQLiteService) put(q querier, title, body string) error {
+	row, err := s.lookup(q, title)
+	if err == sql.ErrNoRows {
+		return s.insert(q, title, models.Node{Type: models.FILE, Body: body})
+	} else if err != nil {
+		return err
+	}
+
+	return s.update(q, row.ID, body)
+}
+
+// GetAll lists all nodes, or nodes of [additional] folder, via a single recursive query.
+// Titles of nodes are relative to [additional] folder.
+func (s *SQLiteService) GetAll(additional, typ string, ignore []string) ([]models.Node, []string, error) {
+	root, err := s.lookup(s.DB, additional)
+	if err == sql.ErrNoRows {
+		return nil, nil, assets.NotExists(additional, "Directory")
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := s.DB.Query(sqliteTree, root.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	scope := ""
+	if names := splitTitle(additional); len(names) > 0 {
+		scope = strings.Join(names, "/") + "/"
+	}
+
+	nodes, titles := []models.Node{}, []string{}
+	ignored := map[int64]bool{}
+
+	for rows.Next() {
+		var id, parent, created, updated int64
+		var level int
+		var name, title string
+		var node models.Node
+
+		if err := rows.Scan(
+			&id, &parent, &name, &node.Type, &title, &level,
+			&node.Body, &created, &updated, &node.Size, &node.Hash,
+		); err != nil {
+			return nil, nil, err
+		}
+
+		// Sub nodes of ignored folders are ignored too.
+		if ignored[parent] || pkg.IsIgnorable(name, ignore) {
+			ignored[id] = true
+			continue
+		}
+
+		if !pkg.IsType(typ, node.IsFolder()) {
+			continue
+		}
+
+		node.Title = nodeTitle(title, node.Type)
+		node.Path = map[string]string{s.Type(): scope + node.Title}
+		node.Created, node.Updated = time.Unix(0, created).UTC(), time.Unix(0, updated).UTC()
+		node.Pretty = []string{strings.Repeat("  ", level) + node.GenPretty(), name}
+		if node.IsFile() {
+			node.FillTags()
+		}
+
+		nodes = append(nodes, node)
+		titles = append(titles, node.Title)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	if len(nodes) == 0 {
+		return nil, nil, assets.EmptyWorkingDirectory
+	}
+
+	return nodes, titles, nil
+}
+
+// Create creates a new note row, under its parent folder.
+func (s *SQLiteService) Create(note models.Note) (*models.Note, error) {
+	if err := s.insert(s.DB, note.Title, note.ToNode()); err != nil {
+		return nil, err
+	}
+
+	return &models.Note{Title: note.Title, Path: map[string]string{s.Type(): note.Title}, Body: note.Body}, ClearTombstone(s, note.Title)
+}
+
+// View reads the note row at [note.Title].
+func (s *SQLiteService) View(note models.Note) (*models.Note, error) {
+	row, err := s.lookup(s.DB, note.Title)
+	if err == sql.ErrNoRows || (err == nil && row.Type != models.FILE) {
+		return nil, assets.NotExists(note.Title, "File")
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &models.Note{Title: note.Title, Path: map[string]string{s.Type(): note.Title}, Body: row.Body}, nil
+}
+
+// Edit overwrites body of existing note row, and keeps its previous body at history.
+func (s *SQLiteService) Edit(note models.Note) (*models.Note, error) {
+	row, err := s.lookup(s.DB, note.Title)
+	if err == sql.ErrNoRows || (err == nil && row.Type != models.FILE) {
+		return nil, assets.NotExists(note.Title, "File")
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := s.update(s.DB, row.ID, note.Body); err != nil {
+		return nil, err
+	}
+
+	keepVersion(s, NewVersion(note.Title, row.Body, row.Updated), note.Body)
+
+	return &models.Note{Title: note.Title, Path: map[string]string{s.Type(): note.Title}, Body: note.Body}, nil
+}
+
+// Copy writes body of note to machine's clipboard.
+func (s *SQLiteService) Copy(note models.Note) error {
+	data, err := s.View(note)
+	if err != nil {
+		return err
+	}
+
+	return clipboard.WriteAll(data.Body)
+}
+
+// Cut, copies note data to machine's clipboard and removes it instantly.
+func (s *SQLiteService) Cut(note models.Note) (*models.Note, error) {
+	if err := s.Copy(note); err != nil {
+		return nil, err
+	}
+
+	n, err := s.View(note)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.Remove(note.ToNode()); err != nil {
+		return nil, err
+	}
+
+	return n, nil
+}
+
+// Mkdir creates a new folder row, under its parent folder.
+func (s *SQLiteService) Mkdir(dir models.Folder) (*models.Folder, error) {
+	if err := s.insert(s.DB, dir.Title, dir.ToNode()); err != nil {
+		return nil, err
+	}
+
+	title := nodeTitle(dir.Title, models.FOLDER)
+	return &models.Folder{Title: title, Path: map[string]string{s.Type(): title}}, ClearTombstone(s, title)
+}
+
+// MoveNotes copies the database to the path of [settings], and switches to it.
+// Old database file is removed, once the new one is opened.
+func (s *SQLiteService) MoveNotes(settings models.Settings) error {
+	if len(settings.SQLitePath) == 0 || settings.SQLitePath == s.File {
+		return nil
+	}
+
+	if pkg.FileExists(settings.SQLitePath) {
+		return assets.AlreadyExists(settings.SQLitePath, "database")
+	}
+
+	if _, err := s.DB.Exec(`VACUUM INTO ?`, settings.SQLitePath); err != nil {
+		return err
+	}
+
+	previous := s.File
+	s.DB.Close()
+
+	if err := s.open(settings.SQLitePath); err != nil {
+		return err
+	}
+
+	s.File = settings.SQLitePath
+	return os.Remove(previous)
+}
+
+// Fetch creates a clone of nodes(that doesn't exists on [s]) from given [remote] service.
+func (s *SQLiteService) Fetch(remote ServiceRepo) ([]models.Node, []error) {
+	return transfer("fetch", remote, s)
+}
+
+// Push uploads nodes(that doesn't exists on given remote) from [s] to given [remote].
+func (s *SQLiteService) Push(remote ServiceRepo) ([]models.Node, []error) {
+	return transfer("push", s, remote)
+}
+
+// Migrate overwrites all notes of given [remote] service with [s].
+// If migration fails halfway, [remote] is restored back to its previous state.
+func (s *SQLiteService) Migrate(remote ServiceRepo) ([]models.Node, []error) {
+	return Migrate(s, remote)
+}
+
+// CommitPlan applies all changes of [plan] at a single transaction.
+// So, the database is never left half-changed.
+func (s *SQLiteService) CommitPlan(plan models.Plan) ([]models.Node, error) {
+	applied := []models.Node{}
+
+	err := s.transact(func(tx *sql.Tx) error {
+		for _, c := range plan.Changes {
+			var err error
+
+			switch {
+			case c.Action == models.DELETE:
+				var row *sqliteRow
+				if row, err = s.lookup(tx, c.Title); err == nil {
+					_, err = tx.Exec(sqliteDeleteTree, row.ID)
+				}
+			case c.Type == models.FOLDER:
+				err = s.insert(tx, c.Title, models.Node{Type: models.FOLDER})
+			default:
+				err = s.put(tx, c.Title, c.Node.Body)
+			}
+
+			if err != nil {
+				return assets.CannotDoSth(plan.Act, c.Title, err)
+			}
+
+			applied = append(applied, c.Node)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return applied, nil
+}
+
+// FullTextSearch ranks notes, that match any term of [query], via BM25 of FTS5 index.
+func (s *SQLiteService) FullTextSearch(query string) ([]models.IndexHit, error) {
+	terms := []string{}
+	for _, t := range models.Tokenize(query) {
+		terms = append(terms, `"`+strings.ReplaceAll(t, `"`, `""`)+`"`)
+	}
+
+	if len(terms) == 0 {
+		return nil, assets.EmptySearchQuery
+	}
+
+	rows, err := s.DB.Query(
+		`SELECT rowid, bm25(nodes_fts) FROM nodes_fts WHERE nodes_fts MATCH ? ORDER BY bm25(nodes_fts)`,
+		strings.Join(terms, " OR "),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	type match struct {
+		id    int64
+		score float64
+	}
+
+	matches := []match{}
+	for rows.Next() {
+		var m match
+		if err := rows.Scan(&m.id, &m.score); err != nil {
+			rows.Close()
+			return nil, err
+		}
+
+		matches = append(matches, m)
+	}
+	rows.Close()
+
+	titles, err := s.titles()
+	if err != nil {
+		return nil, err
+	}
+
+	hits := []models.IndexHit{}
+	for _, m := range matches {
+		if title, ok := titles[m.id]; ok {
+			// BM25 of FTS5 is negative, where lower is better.
+			hits = append(hits, models.IndexHit{Title: title, Score: -m.score})
+		}
+	}
+
+	return hits, nil
+}
+
+// titles maps ids of listed note rows to their full titles.
+func (s *SQLiteService) titles() (map[int64]string, error) {
+	nodes, _, err := s.GetAll("", "file", models.NotyaIgnoreFiles)
+	if err != nil && err != assets.EmptyWorkingDirectory {
+		return nil, err
+	}
+
+	res := map[int64]string{}
+	for _, n := range nodes {
+		row, err := s.lookup(s.DB, n.Title)
+		if err != nil {
+			continue
+		}
+
+		res[row.ID] = n.Title
+	}
+
+	return res, nil
+}
+
+// PutTrash saves [entry] as a JSON row of trash table.
+func (s *SQLiteService) PutTrash(entry models.TrashEntry) error {
+	_, err := s.DB.Exec(`INSERT INTO trash(id, data) VALUES (?, ?)`, entry.ID, entry.ToString())
+	return err
+}
+
+// TrashEntries reads all entries of trash table.
+// Rows that cannot be decoded as entries are skipped.
+func (s *SQLiteService) TrashEntries() (models.Trash, error) {
+	rows, err := s.DB.Query(`SELECT data FROM trash`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	trash := models.Trash{}
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+
+		if entry, err := models.DecodeTrashEntry(data); err == nil {
+			trash = append(trash, *entry)
+		}
+	}
+
+	return trash, rows.Err()
+}
+
+// DropTrash deletes the entry row of [id] from trash table.
+func (s *SQLiteService) DropTrash(id string) error {
+	_, err := s.DB.Exec(`DELETE FROM trash WHERE id = ?`, id)
+	return err
+}
+
+// Versions reads the history of note at [title] from versions table.
+func (s *SQLiteService) Versions(title string) (models.History, error) {
+	rows, err := s.DB.Query(`SELECT data FROM versions WHERE title = ?`, strings.Trim(title, "/"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := models.History{}
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+
+		var version models.Version
+		if err := json.Unmarshal([]byte(data), &version); err == nil {
+			history = append(history, version)
+		}
+	}
+
+	return history, rows.Err()
+}
+
+// SaveVersion appends [version] to the history of its note.
+func (s *SQLiteService) SaveVersion(version models.Version) error {
+	history, err := s.Versions(version.Title)
+	if err != nil {
+		return err
+	}
+
+	version.Title = strings.Trim(version.Title, "/")
+	version.Rev = history.NextRev()
+
+	data, err := json.Marshal(version)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.DB.Exec(`INSERT INTO versions(title, rev, data) VALUES (?, ?, ?)`, version.Title, version.Rev, string(data))
+	return err
+}
+
+// DropVersions removes the whole history of note at [title].
+func (s *SQLiteService) DropVersions(title string) error {
+	_, err := s.DB.Exec(`DELETE FROM versions WHERE title = ?`, strings.Trim(title, "/"))
+	return err
+}
+
+// Close closes the database of service.
+func (s *SQLiteService) Close() error {
+	if s.DB == nil {
+		return nil
+	}
+
+	return s.DB.Close()
+}
@@ -0,0 +1,249 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/lib/services"
+)
+
+func newTestSQLiteService(t *testing.T) *services.SQLiteService {
+	local := newTestLocalService(t)
+
+	settings := local.Config
+	settings.SQLitePath = t.TempDir() + "/notes.db"
+
+	s := services.NewSQLiteService(local.Stdargs, local)
+	if err := s.Init(&settings); err != nil {
+		t.Fatalf("SQLiteService.Init returned an error: %v", err)
+	}
+
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestSQLiteServiceNodes(t *testing.T) {
+	s := newTestSQLiteService(t)
+
+	if _, err := s.Create(models.Note{Title: "ideas/a.md"}); err == nil {
+		t.Errorf("Create should fail, if parent folder doesn't exist")
+	}
+
+	s.Mkdir(models.Folder{Title: "ideas"})
+	s.Mkdir(models.Folder{Title: "ideas/old/"})
+	s.Create(models.Note{Title: "ideas/a.md", Body: "---\ntags: [work]\n---\nA"})
+	s.Create(models.Note{Title: "ideas/old/b.md", Body: "B"})
+	s.Create(models.Note{Title: "c.md", Body: "C"})
+
+	if _, err := s.Create(models.Note{Title: "c.md"}); err == nil {
+		t.Errorf("Create should fail for existing notes")
+	}
+
+	nodes, _, err := s.GetAll("", "", models.NotyaIgnoreFiles)
+	expected := "[c.md ideas/ ideas/a.md ideas/old/ ideas/old/b.md]"
+	if err != nil || fmt.Sprint(titles(nodes)) != expected {
+		t.Fatalf("GetAll sum was different: Want: %v | Got: %v (%v)", expected, titles(nodes), err)
+	}
+
+	if a := nodes[2]; a.Size != int64(len(a.Body)) || len(a.Hash) == 0 || fmt.Sprint(a.Tags) != "[work]" || a.Created.IsZero() {
+		t.Errorf("GetAll should fill metadata of notes, Got: %v", a)
+	}
+
+	files, _, err := s.GetAll("ideas", "file", models.NotyaIgnoreFiles)
+	if err != nil || fmt.Sprint(titles(files)) != "[a.md old/b.md]" {
+		t.Errorf("GetAll of folder sum was different: Got: %v (%v)", titles(files), err)
+	}
+
+	if _, err := s.Edit(models.Note{Title: "c.md", Body: "C2"}); err != nil {
+		t.Fatalf("Edit returned an error: %v", err)
+	}
+
+	if c, err := s.View(models.Note{Title: "c.md"}); err != nil || c.Body != "C2" {
+		t.Errorf("View sum was different: Got: %v (%v)", c, err)
+	}
+
+	if history, err := services.History(s, "c.md"); err != nil || len(history) != 1 || history[0].Body != "C" {
+		t.Errorf("Edit should keep the previous body at history, Got: %v (%v)", history, err)
+	}
+
+	if err := s.Remove(models.Node{Title: "ideas/"}); err != nil {
+		t.Fatalf("Remove returned an error: %v", err)
+	}
+
+	nodes, _, _ = s.GetAll("", "", models.NotyaIgnoreFiles)
+	if fmt.Sprint(titles(nodes)) != "[c.md]" {
+		t.Errorf("Remove should remove sub nodes of folder, Got: %v", titles(nodes))
+	}
+
+	tombstones, _ := services.Tombstones(s)
+	if !tombstones.Covers("ideas/old/b.md") {
+		t.Errorf("Removed folder should be recorded as removed, Got: %v", tombstones)
+	}
+}
+
+func TestSQLiteServiceRename(t *testing.T) {
+	s := newTestSQLiteService(t)
+
+	s.Mkdir(models.Folder{Title: "ideas/"})
+	s.Mkdir(models.Folder{Title: "archive/"})
+	s.Create(models.Note{Title: "ideas/a.md", Body: "A"})
+	s.Edit(models.Note{Title: "ideas/a.md", Body: "A2"})
+
+	rename := models.EditNode{Current: models.Node{Title: "ideas"}, New: models.Node{Title: "archive/ideas"}}
+	if err := s.Rename(rename); err != nil {
+		t.Fatalf("Rename returned an error: %v", err)
+	}
+
+	if a, err := s.View(models.Note{Title: "archive/ideas/a.md"}); err != nil || a.Body != "A2" {
+		t.Errorf("Rename should move sub nodes of folder, Got: %v (%v)", a, err)
+	}
+
+	if history, _ := services.History(s, "archive/ideas/a.md"); len(history) != 1 {
+		t.Errorf("Rename should move history of sub nodes, Got: %v", history)
+	}
+
+	invalid := models.EditNode{Current: models.Node{Title: "archive"}, New: models.Node{Title: "archive/ideas/archive"}}
+	if err := s.Rename(invalid); err == nil {
+		t.Errorf("Rename should fail for moving folder into itself")
+	}
+
+	missing := models.EditNode{Current: models.Node{Title: "archive/ideas"}, New: models.Node{Title: "missing/ideas"}}
+	if err := s.Rename(missing); err == nil {
+		t.Errorf("Rename should fail, if new parent folder doesn't exist")
+	}
+}
+
+func TestSQLiteServiceClearNodes(t *testing.T) {
+	s := newTestSQLiteService(t)
+
+	s.Mkdir(models.Folder{Title: "ideas/"})
+	s.Create(models.Note{Title: "ideas/a.md"})
+	s.Create(models.Note{Title: "b.md"})
+
+	cleared, errs := s.ClearNodes()
+	if len(errs) > 0 || len(cleared) != 3 {
+		t.Fatalf("ClearNodes sum was different: Cleared: %v | Errors: %v", titles(cleared), errs)
+	}
+
+	if nodes, _, _ := s.GetAll("", "", models.NotyaIgnoreFiles); len(nodes) != 0 {
+		t.Errorf("ClearNodes should remove all nodes, Got: %v", titles(nodes))
+	}
+
+	tombstones, _ := services.Tombstones(s)
+	if !tombstones.Covers("ideas/a.md") || !tombstones.Covers("b.md") {
+		t.Errorf("Cleared nodes should be recorded as removed, Got: %v", tombstones)
+	}
+}
+
+func TestSQLiteServiceMigrate(t *testing.T) {
+	local, s := newTestLocalService(t), newTestSQLiteService(t)
+
+	local.Mkdir(models.Folder{Title: "ideas/"})
+	local.Create(models.Note{Title: "ideas/a.md", Body: "A"})
+	s.Create(models.Note{Title: "b.md", Body: "B"})
+
+	migrated, errs := local.Migrate(s)
+	if len(errs) > 0 || len(migrated) != 2 {
+		t.Fatalf("Migrate sum was different: Migrated: %v | Errors: %v", titles(migrated), errs)
+	}
+
+	if plan, _ := services.PlanTransfer("push", local, s, false); len(plan.Changes) != 0 {
+		t.Errorf("SQLite should be same as local after migrating, Got: %v", planSummary(plan))
+	}
+
+	local.Create(models.Note{Title: "c.md", Body: "C"})
+	if pushed, errs := local.Push(s); len(errs) > 0 || len(pushed) != 1 {
+		t.Errorf("Push sum was different: Pushed: %v | Errors: %v", titles(pushed), errs)
+	}
+
+	other := newTestLocalService(t)
+	if fetched, errs := other.Fetch(s); len(errs) > 0 || len(fetched) != 3 {
+		t.Errorf("Fetch sum was different: Fetched: %v | Errors: %v", titles(fetched), errs)
+	}
+}
+
+func TestSQLiteServiceCommitPlan(t *testing.T) {
+	s := newTestSQLiteService(t)
+	s.Create(models.Note{Title: "a.md", Body: "A"})
+
+	plan := models.Plan{Act: "migrate", Changes: []models.Change{
+		{Action: models.DELETE, Type: models.FILE, Title: "a.md", Node: models.Node{Title: "a.md"}},
+		{Action: models.CREATE, Type: models.FILE, Title: "missing/b.md", Node: models.Node{Body: "B"}},
+	}}
+
+	if _, err := s.CommitPlan(plan); err == nil {
+		t.Fatalf("CommitPlan should fail for notes of missing folders")
+	}
+
+	if exists, _ := s.IsNodeExists(models.Node{Title: "a.md"}); !exists {
+		t.Errorf("Failed plan should be rolled back completely")
+	}
+}
+
+func TestSQLiteServiceRankedSearch(t *testing.T) {
+	s := newTestSQLiteService(t)
+
+	s.Mkdir(models.Folder{Title: "ideas/"})
+	s.Create(models.Note{Title: "ideas/a.md", Body: "sqlite keeps notes\nsqlite again"})
+	s.Create(models.Note{Title: "b.md", Body: "notes only"})
+	s.Edit(models.Note{Title: "b.md", Body: "notes of sqlite"})
+
+	results, err := services.RankedSearch(s, models.SearchOptions{Query: "sqlite"})
+	if err != nil || len(results) != 2 || results[0].Node.Title != "ideas/a.md" {
+		t.Fatalf("RankedSearch sum was different: Got: %v (%v)", results, err)
+	}
+
+	if results[0].Score <= 0 || len(results[0].Matches) != 2 {
+		t.Errorf("RankedSearch should score and highlight results, Got: %v", results[0])
+	}
+
+	scoped, _ := services.RankedSearch(s, models.SearchOptions{Query: "sqlite", In: "ideas"})
+	if len(scoped) != 1 {
+		t.Errorf("RankedSearch should be scoped to folder, Got: %v", scoped)
+	}
+}
+
+func TestSQLiteServiceTrash(t *testing.T) {
+	s := newTestSQLiteService(t)
+	s.Create(models.Note{Title: "a.md", Body: "A"})
+
+	if err := services.MoveToTrash(s, models.Node{Title: "a.md"}); err != nil {
+		t.Fatalf("MoveToTrash returned an error: %v", err)
+	}
+
+	if _, err := services.RestoreTrash(s, "a.md"); err != nil {
+		t.Fatalf("RestoreTrash returned an error: %v", err)
+	}
+
+	if a, err := s.View(models.Note{Title: "a.md"}); err != nil || a.Body != "A" {
+		t.Errorf("Restored note sum was different: Got: %v (%v)", a, err)
+	}
+}
+
+func TestSQLiteServiceSettings(t *testing.T) {
+	s := newTestSQLiteService(t)
+
+	settings := s.StateConfig()
+	settings.Editor = "nvim"
+	if err := s.WriteSettings(settings); err != nil {
+		t.Fatalf("WriteSettings returned an error: %v", err)
+	}
+
+	// Re-opened database should bring its own settings.
+	reopened := services.NewSQLiteService(s.Stdargs, s.LS)
+	if err := reopened.Init(&models.Settings{SQLitePath: s.File}); err != nil {
+		t.Fatalf("Init returned an error: %v", err)
+	}
+	defer reopened.Close()
+
+	if reopened.StateConfig().Editor != "nvim" {
+		t.Errorf("Settings of database sum was different: Got: %v", reopened.StateConfig())
+	}
+}
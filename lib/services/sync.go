@@ -0,0 +1,361 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/insolite-dev/notya/assets"
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/pkg"
+)
+
+// ConflictTitle generates the title of conflict copy for given note title.
+//
+//	ConflictTitle("ideas/todo.md", "FIREBASE") ─▶ "ideas/todo.FIREBASE-conflict.md"
+func ConflictTitle(title, service string) string {
+	ext := filepath.Ext(title)
+	return fmt.Sprintf("%v.%v-conflict%v", strings.TrimSuffix(title, ext), service, ext)
+}
+
+// ConflictBody merges [current] and [remote] bodies into one body,
+// by wrapping each of them with git-like conflict markers.
+//
+//	<<<<<<< LOCAL
+//	... current body ...
+//	=======
+//	... remote body ...
+//	>>>>>>> FIREBASE
+func ConflictBody(current, remote, currentType, remoteType string) string {
+	terminate := func(s string) string {
+		if len(s) > 0 && !strings.HasSuffix(s, "\n") {
+			return s + "\n"
+		}
+
+		return s
+	}
+
+	return fmt.Sprintf(
+		"<<<<<<< %v\n%v=======\n%v>>>>>>> %v\n",
+		currentType, terminate(current), terminate(remote), remoteType,
+	)
+}
+
+// syncer keeps the state of one synchronization between two services.
+type syncer struct {
+	current, remote ServiceRepo
+	copies          bool
+
+	base, next map[string]string
+	report     models.SyncReport
+	errs       []error
+}
+
+// Sync runs three-way synchronization between [current] and [remote] services.
+//
+// [base] is the snapshot of last synced state, which maps node titles to body hashes.
+// Comparing each side with base, tells which side has changed the node:
+//   - If only one side has changed, the change is applied to other side.
+//   - If a node was removed on one side and not changed on other, it's removed on other side too.
+//   - If both sides have changed, the note is reported as a conflict. And resolved by conflict markers,
+//     or by conflict copies (if [copies] is enabled). So, neither side gets overwritten silently.
+//
+// Returns the report of sync, the new base snapshot, and errors that happened on the way.
+func Sync(current, remote ServiceRepo, base map[string]string, copies bool) (models.SyncReport, map[string]string, []error) {
+	s := &syncer{current: current, remote: remote, copies: copies, base: base, next: map[string]string{}}
+
+	currentNodes, err := syncNodes(current)
+	if err != nil {
+		return s.report, base, []error{err}
+	}
+
+	remoteNodes, err := syncNodes(remote)
+	if err != nil {
+		return s.report, base, []error{err}
+	}
+
+	fileSet, folderSet := map[string]bool{}, map[string]bool{}
+	for _, nodes := range []map[string]models.Node{currentNodes, remoteNodes} {
+		for title, node := range nodes {
+			if node.IsFolder() {
+				folderSet[title] = true
+			} else {
+				fileSet[title] = true
+			}
+		}
+	}
+
+	// Tombstones and search indexes of both sides are written once, after syncing all nodes.
+	flushCurrent, flushRemote := Batch(current), Batch(remote)
+
+	// Parents have to come before children.
+	files, folders := sortedTitles(fileSet), sortedTitles(folderSet)
+
+	// Folders that were removed on one side, but still exist on other side.
+	removed := []string{}
+
+	for _, f := range folders {
+		c, cok := currentNodes[f]
+		r, rok := remoteNodes[f]
+		_, bok := base[f]
+
+		switch {
+		case cok && rok:
+			s.next[f] = models.FolderHash
+		case !bok:
+			s.mkdir(c, r, cok)
+		case s.isKept(f, currentNodes, remoteNodes, cok):
+			// Folder has new or changed notes, that have to be synced.
+			s.mkdir(c, r, cok)
+		default:
+			removed = append(removed, f)
+		}
+	}
+
+	for _, t := range files {
+		s.syncFile(t, currentNodes, remoteNodes)
+	}
+
+	// Remove folders via title-len decreasing order, so children come before parents.
+	for i := len(removed) - 1; i >= 0; i-- {
+		f := removed[i]
+		if c, ok := currentNodes[f]; ok {
+			s.remove(current, c, f)
+		} else {
+			s.remove(remote, remoteNodes[f], f)
+		}
+	}
+
+	for _, flush := range []func() error{flushCurrent, flushRemote} {
+		if err := flush(); err != nil {
+			s.errs = append(s.errs, err)
+		}
+	}
+
+	return s.report, s.next, s.errs
+}
+
+// syncNodes collects all nodes of service, as a map of normalized titles.
+func syncNodes(s ServiceRepo) (map[string]models.Node, error) {
+	return folderNodes(s, "")
+}
+
+// folderNodes collects all nodes under [folder] of service, as a map of normalized full titles.
+func folderNodes(s ServiceRepo, folder string) (map[string]models.Node, error) {
+	nodes, _, err := s.GetAll(folder, "", models.NotyaIgnoreFiles)
+	if err != nil && err.Error() != assets.EmptyWorkingDirectory.Error() {
+		return nil, err
+	}
+
+	res := map[string]models.Node{}
+	for _, n := range nodes {
+		n.Title = joinTitle(folder, n.Title)
+
+		if n.IsFolder() {
+			res[n.ToFolder().Title] = n
+		} else {
+			res[n.ToNote().Title] = n
+		}
+	}
+
+	return res, nil
+}
+
+// joinTitle converts [title] of a node listed at [folder], to its full title.
+func joinTitle(folder, title string) string {
+	scope := strings.Trim(folder, "/")
+	if len(scope) == 0 {
+		return title
+	}
+
+	return scope + "/" + title
+}
+
+// isKept checks if removed folder [f] has to be re-created, instead of removing it on other side.
+// It's true when other side has notes under [f], that are new or changed after last sync.
+func (s *syncer) isKept(f string, currentNodes, remoteNodes map[string]models.Node, onCurrent bool) bool {
+	nodes, others := remoteNodes, currentNodes
+	if onCurrent {
+		nodes, others = currentNodes, remoteNodes
+	}
+
+	for title, n := range nodes {
+		if n.IsFolder() || !strings.HasPrefix(title, f) {
+			continue
+		}
+
+		if _, ok := others[title]; ok {
+			continue
+		}
+
+		if b, ok := s.base[title]; !ok || b != pkg.HashBody(n.Body) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// mkdir creates the folder on the side where it's missing.
+func (s *syncer) mkdir(c, r models.Node, onCurrent bool) {
+	to, node := s.current, r
+	if onCurrent {
+		to, node = s.remote, c
+	}
+
+	title := node.ToFolder().Title
+	if _, err := to.Mkdir(models.Folder{Title: title}); err != nil {
+		s.errs = append(s.errs, assets.CannotDoSth("sync", title, err))
+		return
+	}
+
+	s.next[title] = models.FolderHash
+	s.track(to, node)
+}
+
+// syncFile decides which side has changed the note [t], and applies that change to the other side.
+func (s *syncer) syncFile(t string, currentNodes, remoteNodes map[string]models.Node) {
+	c, cok := currentNodes[t]
+	r, rok := remoteNodes[t]
+	b, bok := s.base[t]
+
+	switch {
+	case cok && rok:
+		ch, rh := pkg.HashBody(c.Body), pkg.HashBody(r.Body)
+		switch {
+		case ch == rh:
+			s.next[t] = ch
+		case bok && ch == b: // changed only on remote.
+			s.write(s.current, t, r, true)
+		case bok && rh == b: // changed only on current.
+			s.write(s.remote, t, c, true)
+		default:
+			s.conflict(t, c, r)
+		}
+	case cok:
+		if bok && pkg.HashBody(c.Body) == b {
+			s.remove(s.current, c, t)
+		} else {
+			s.write(s.remote, t, c, false)
+		}
+	case rok:
+		if bok && pkg.HashBody(r.Body) == b {
+			s.remove(s.remote, r, t)
+		} else {
+			s.write(s.current, t, r, false)
+		}
+	}
+}
+
+// write creates or edits note [t] at [to] service with [node]'s body.
+func (s *syncer) write(to ServiceRepo, t string, node models.Node, exists bool) {
+	if err := put(to, models.Note{Title: t, Body: node.Body}, exists); err != nil {
+		s.fail(t, err)
+		return
+	}
+
+	s.next[t] = pkg.HashBody(node.Body)
+	s.track(to, node)
+}
+
+// remove deletes node [t] from [from] service, because it was removed on other side.
+func (s *syncer) remove(from ServiceRepo, node models.Node, t string) {
+	if err := from.Remove(node); err != nil {
+		s.fail(t, err)
+		return
+	}
+
+	s.report.Removed = append(s.report.Removed, node)
+}
+
+// conflict resolves the conflict of note [t], which was changed on both sides.
+func (s *syncer) conflict(t string, c, r models.Node) {
+	s.report.Conflicts = append(s.report.Conflicts, c)
+
+	if !s.copies {
+		body := ConflictBody(c.Body, r.Body, s.current.Type(), s.remote.Type())
+		for _, to := range []ServiceRepo{s.current, s.remote} {
+			if err := put(to, models.Note{Title: t, Body: body}, true); err != nil {
+				s.fail(t, err)
+				return
+			}
+		}
+
+		s.next[t] = pkg.HashBody(body)
+		return
+	}
+
+	// Keep current body as the main note, and save remote body as a conflict copy on both sides.
+	copyTitle := ConflictTitle(t, s.remote.Type())
+	if err := put(s.remote, models.Note{Title: t, Body: c.Body}, true); err != nil {
+		s.fail(t, err)
+		return
+	}
+
+	for _, to := range []ServiceRepo{s.current, s.remote} {
+		exists, _ := to.IsNodeExists(models.Node{Title: copyTitle})
+		if err := put(to, models.Note{Title: copyTitle, Body: r.Body}, exists); err != nil {
+			s.fail(t, err)
+			return
+		}
+	}
+
+	s.next[t] = pkg.HashBody(c.Body)
+	s.next[copyTitle] = pkg.HashBody(r.Body)
+}
+
+// track appends synced [node] to report, appropriate to the service it was written to.
+func (s *syncer) track(to ServiceRepo, node models.Node) {
+	if to == s.current {
+		s.report.Pulled = append(s.report.Pulled, node)
+	} else {
+		s.report.Pushed = append(s.report.Pushed, node)
+	}
+}
+
+// fail collects the error of node [t], and keeps its base snapshot as it was.
+// So, the node would be synced again at next run.
+func (s *syncer) fail(t string, err error) {
+	s.errs = append(s.errs, assets.CannotDoSth("sync", t, err))
+
+	if b, ok := s.base[t]; ok {
+		s.next[t] = b
+	}
+}
+
+// put edits the [note] at [to] service if it [exists], otherwise creates it.
+func put(to ServiceRepo, note models.Note, exists bool) error {
+	var err error
+	if exists {
+		_, err = to.Edit(note)
+	} else {
+		_, err = to.Create(note)
+	}
+
+	return err
+}
+
+// sortedTitles converts [set] of titles to a slice, sorted via title-len ascending order.
+func sortedTitles(set map[string]bool) []string {
+	titles := []string{}
+	for t := range set {
+		titles = append(titles, t)
+	}
+
+	sort.Slice(titles, func(i, j int) bool {
+		if len(titles[i]) == len(titles[j]) {
+			return titles[i] < titles[j]
+		}
+
+		return len(titles[i]) < len(titles[j])
+	})
+
+	return titles
+}
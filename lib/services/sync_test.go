@@ -0,0 +1,160 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services_test
+
+import (
+	"testing"
+
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/lib/services"
+)
+
+// newTestLocalService creates a local service, which works on a temporary directory.
+func newTestLocalService(t *testing.T) *services.LocalService {
+	dir := t.TempDir() + "/"
+	return &services.LocalService{NotyaPath: dir, Config: models.InitSettings(dir)}
+}
+
+func TestConflictTitle(t *testing.T) {
+	tests := []struct {
+		title, service, expected string
+	}{
+		{title: "todo.md", service: "FIREBASE", expected: "todo.FIREBASE-conflict.md"},
+		{title: "ideas/todo", service: "LOCAL", expected: "ideas/todo.LOCAL-conflict"},
+	}
+
+	for _, td := range tests {
+		got := services.ConflictTitle(td.title, td.service)
+		if got != td.expected {
+			t.Errorf("ConflictTitle sum was different: Want: %v | Got: %v", td.expected, got)
+		}
+	}
+}
+
+func TestConflictBody(t *testing.T) {
+	got := services.ConflictBody("mine", "theirs\n", "LOCAL", "FIREBASE")
+	expected := "<<<<<<< LOCAL\nmine\n=======\ntheirs\n>>>>>>> FIREBASE\n"
+
+	if got != expected {
+		t.Errorf("ConflictBody sum was different: Want: %q | Got: %q", expected, got)
+	}
+}
+
+func TestSync(t *testing.T) {
+	current, remote := newTestLocalService(t), newTestLocalService(t)
+
+	body := func(s services.ServiceRepo, title string) string {
+		n, err := s.View(models.Note{Title: title})
+		if err != nil {
+			return "<missing>"
+		}
+
+		return n.Body
+	}
+
+	// First sync, without base: each side gets the other side's notes.
+	current.Mkdir(models.Folder{Title: "ideas/"})
+	current.Create(models.Note{Title: "ideas/a.md", Body: "A"})
+	remote.Create(models.Note{Title: "b.md", Body: "B"})
+
+	report, base, errs := services.Sync(current, remote, map[string]string{}, false)
+	if len(errs) > 0 || len(report.Pushed) != 2 || len(report.Pulled) != 1 {
+		t.Fatalf("First sync was different: Report: %v | Errors: %v", report, errs)
+	}
+
+	if body(remote, "ideas/a.md") != "A" || body(current, "b.md") != "B" {
+		t.Fatalf("First sync didn't clone notes to both sides")
+	}
+
+	// Changed only on remote: should be pulled, without conflict.
+	remote.Edit(models.Note{Title: "b.md", Body: "B2"})
+
+	report, base, errs = services.Sync(current, remote, base, false)
+	if len(errs) > 0 || len(report.Pulled) != 1 || len(report.Conflicts) != 0 || body(current, "b.md") != "B2" {
+		t.Fatalf("Remote change wasn't pulled: Report: %v | Errors: %v", report, errs)
+	}
+
+	// Changed on both sides: should be reported as conflict.
+	current.Edit(models.Note{Title: "b.md", Body: "mine"})
+	remote.Edit(models.Note{Title: "b.md", Body: "theirs"})
+
+	report, base, errs = services.Sync(current, remote, base, false)
+	expectedBody := services.ConflictBody("mine", "theirs", "LOCAL", "LOCAL")
+	if len(errs) > 0 || len(report.Conflicts) != 1 || body(current, "b.md") != expectedBody || body(remote, "b.md") != expectedBody {
+		t.Fatalf("Conflict wasn't resolved via markers: Report: %v | Errors: %v", report, errs)
+	}
+
+	// Removed on current: should be removed on remote too.
+	current.Remove(models.Node{Title: "ideas/"})
+
+	report, _, errs = services.Sync(current, remote, base, false)
+	if len(errs) > 0 || len(report.Removed) != 2 || body(remote, "ideas/a.md") != "<missing>" {
+		t.Fatalf("Removal wasn't propagated: Report: %v | Errors: %v", report, errs)
+	}
+}
+
+func TestSyncConflictCopies(t *testing.T) {
+	current, remote := newTestLocalService(t), newTestLocalService(t)
+
+	current.Create(models.Note{Title: "a.md", Body: "mine"})
+	remote.Create(models.Note{Title: "a.md", Body: "theirs"})
+
+	report, base, errs := services.Sync(current, remote, map[string]string{}, true)
+	if len(errs) > 0 || len(report.Conflicts) != 1 {
+		t.Fatalf("Conflict wasn't reported: Report: %v | Errors: %v", report, errs)
+	}
+
+	copyTitle := services.ConflictTitle("a.md", remote.Type())
+	for _, s := range []services.ServiceRepo{current, remote} {
+		main, _ := s.View(models.Note{Title: "a.md"})
+		copy, err := s.View(models.Note{Title: copyTitle})
+		if err != nil || main.Body != "mine" || copy.Body != "theirs" {
+			t.Errorf("Conflict copies were different: Main: %v | Copy: %v | Error: %v", main, copy, err)
+		}
+	}
+
+	if _, ok := base[copyTitle]; !ok {
+		t.Errorf("Conflict copy should be saved at base snapshot")
+	}
+}
+
+func TestSyncAfterChangingS3Prefix(t *testing.T) {
+	current, remote := newTestLocalService(t), newTestS3Service(t)
+	snapshots := models.Snapshots{}
+
+	current.Create(models.Note{Title: "a.md", Body: "A"})
+
+	key := models.SnapshotKey(services.ServiceKey(current), services.ServiceKey(remote))
+	_, base, errs := services.Sync(current, remote, snapshots.Get(key), false)
+	if len(errs) > 0 {
+		t.Fatalf("Sync returned errors: %v", errs)
+	}
+	snapshots[key] = base
+
+	// Same bucket, but another prefix, that doesn't have notes of previous one.
+	settings := remote.StateConfig()
+	settings.S3Prefix = "other"
+
+	moved := services.NewS3Service(remote.Stdargs, remote.LS)
+	if err := moved.Init(&settings); err != nil {
+		t.Fatalf("Init returned an error: %v", err)
+	}
+
+	key = models.SnapshotKey(services.ServiceKey(current), services.ServiceKey(moved))
+	if len(snapshots.Get(key)) > 0 {
+		t.Fatalf("Another prefix shouldn't share the snapshot of previous one")
+	}
+
+	report, _, errs := services.Sync(current, moved, snapshots.Get(key), false)
+	if len(errs) > 0 || len(report.Removed) > 0 || len(report.Pushed) != 1 {
+		t.Errorf("Sync with another prefix should push notes, instead of removing them: Report: %v | Errors: %v", report, errs)
+	}
+
+	if exists, _ := current.IsNodeExists(models.Node{Title: "a.md"}); !exists {
+		t.Errorf("Notes missing on another prefix shouldn't be removed locally")
+	}
+}
@@ -0,0 +1,89 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services
+
+import (
+	"strings"
+
+	"github.com/insolite-dev/notya/assets"
+	"github.com/insolite-dev/notya/lib/models"
+)
+
+// TagQuerier is implemented by those services, that can query nodes by tag on server side.
+type TagQuerier interface {
+	NodesByTag(tag string) ([]models.Node, error)
+}
+
+// Mark [FirebaseService] as [TagQuerier].
+var _ TagQuerier = &FirebaseService{}
+
+// NodesByTag collects the notes of provided service, that include [tag] at their front matter.
+// If [folder] is provided, only notes under that folder are collected, with their full titles.
+// If service is a [TagQuerier] notes are queried on server side,
+// otherwise (or if query fails) front matter of each note is parsed.
+func NodesByTag(s ServiceRepo, tag, folder string) ([]models.Node, error) {
+	scope := strings.Trim(folder, "/")
+
+	if q, ok := s.(TagQuerier); ok {
+		if nodes, err := q.NodesByTag(tag); err == nil {
+			res := []models.Node{}
+			for _, n := range nodes {
+				if len(scope) == 0 || strings.HasPrefix(n.Title, scope+"/") {
+					res = append(res, n)
+				}
+			}
+
+			return res, nil
+		}
+	}
+
+	nodes, _, err := s.GetAll(scope, "file", models.NotyaIgnoreFiles)
+	if err != nil && err.Error() != assets.EmptyWorkingDirectory.Error() {
+		return nil, err
+	}
+
+	res := []models.Node{}
+	for _, n := range nodes {
+		n.Title = joinTitle(scope, n.Title)
+
+		note := n.ToNote()
+		if fm := note.FrontMatter(); fm != nil && fm.HasTag(tag) {
+			res = append(res, n)
+		}
+	}
+
+	return res, nil
+}
+
+// TagCounts collects all tags of notes at provided service, with the amount of notes including them.
+func TagCounts(s ServiceRepo) (map[string]int, error) {
+	nodes, _, err := s.GetAll("", "file", models.NotyaIgnoreFiles)
+	if err != nil && err.Error() != assets.EmptyWorkingDirectory.Error() {
+		return nil, err
+	}
+
+	counts := map[string]int{}
+	for _, n := range nodes {
+		note := n.ToNote()
+		fm := note.FrontMatter()
+		if fm == nil {
+			continue
+		}
+
+		// Count each tag once per note.
+		seen := map[string]bool{}
+		for _, t := range fm.Tags {
+			t = strings.TrimPrefix(t, "#")
+			if len(t) > 0 && !seen[t] {
+				seen[t] = true
+				counts[t]++
+			}
+		}
+	}
+
+	return counts, nil
+}
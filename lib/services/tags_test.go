@@ -0,0 +1,46 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/lib/services"
+)
+
+func TestTags(t *testing.T) {
+	local := newTestLocalService(t)
+
+	body := "---\ntags: [work, plan]\n---\nweekly plan\n"
+	local.Mkdir(models.Folder{Title: "ideas/"})
+	local.Create(models.Note{Title: "ideas/a.md", Body: body})
+	local.Create(models.Note{Title: "b.md", Body: "---\ntags: \"#work\"\n---\n"})
+	local.Create(models.Note{Title: "c.md", Body: "no tags"})
+
+	counts, err := services.TagCounts(local)
+	if err != nil || fmt.Sprint(counts) != "map[plan:1 work:2]" {
+		t.Errorf("TagCounts sum was different: Got: %v | Error: %v", counts, err)
+	}
+
+	nodes, err := services.NodesByTag(local, "plan", "")
+	if err != nil || len(nodes) != 1 || nodes[0].Title != "ideas/a.md" {
+		t.Fatalf("NodesByTag sum was different: Got: %v | Error: %v", nodes, err)
+	}
+
+	// Front matter should round-trip exactly, and be exposed as node's tags.
+	if nodes[0].Body != body || fmt.Sprint(nodes[0].Tags) != "[work plan]" {
+		t.Errorf("Note wasn't round-tripped: Body: %q | Tags: %v", nodes[0].Body, nodes[0].Tags)
+	}
+
+	// Notes of other folders aren't collected, if a folder is provided.
+	nodes, err = services.NodesByTag(local, "work", "ideas")
+	if err != nil || len(nodes) != 1 || nodes[0].Title != "ideas/a.md" {
+		t.Errorf("NodesByTag of folder sum was different: Got: %v | Error: %v", titles(nodes), err)
+	}
+}
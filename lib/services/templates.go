@@ -0,0 +1,65 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/insolite-dev/notya/assets"
+	"github.com/insolite-dev/notya/lib/models"
+)
+
+// Templates lists note templates of provided service.
+// Titles of templates are relative to the templates folder.
+func Templates(s ServiceRepo) ([]models.Node, error) {
+	if exists, _ := s.IsNodeExists(models.Node{Title: models.TemplatesName + "/"}); !exists {
+		return []models.Node{}, nil
+	}
+
+	nodes, _, err := s.GetAll(models.TemplatesName, "file", models.NotyaIgnoreFiles)
+	if err == assets.EmptyWorkingDirectory {
+		return []models.Node{}, nil
+	}
+
+	return nodes, err
+}
+
+// ViewTemplate reads the template of [name], which may be given without its extension.
+// Title of returned note is relative to the notes root, like: .templates/meeting.md
+func ViewTemplate(s ServiceRepo, name string) (*models.Note, error) {
+	templates, err := Templates(s)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range templates {
+		if t.Title != name && strings.TrimSuffix(t.Title, filepath.Ext(t.Title)) != name {
+			continue
+		}
+
+		return s.View(models.Note{Title: TemplateTitle(t.Title)})
+	}
+
+	return nil, assets.NotExists(name, "Template")
+}
+
+// NewTemplate creates the template of [name] with [body],
+// creating the templates folder at first, if it doesn't exist.
+func NewTemplate(s ServiceRepo, name, body string) (*models.Note, error) {
+	title := TemplateTitle(name)
+	if err := mkdirParents(s, title); err != nil {
+		return nil, err
+	}
+
+	return s.Create(models.Note{Title: title, Body: body})
+}
+
+// TemplateTitle generates the title of template of [name], relative to the notes root.
+func TemplateTitle(name string) string {
+	return models.TemplatesName + "/" + strings.TrimPrefix(name, "/")
+}
@@ -0,0 +1,49 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services_test
+
+import (
+	"testing"
+
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/lib/services"
+)
+
+func TestTemplates(t *testing.T) {
+	local := newTestLocalService(t)
+	local.Create(models.Note{Title: "a.md", Body: "a"})
+
+	templates, err := services.Templates(local)
+	if err != nil || len(templates) != 0 {
+		t.Fatalf("Templates should be empty at first, Got: %v (%v)", titles(templates), err)
+	}
+
+	if _, err := services.NewTemplate(local, "meeting.md", "# {{.Title}}"); err != nil {
+		t.Fatalf("NewTemplate returned an error: %v", err)
+	}
+
+	templates, err = services.Templates(local)
+	if err != nil || len(templates) != 1 || templates[0].Title != "meeting.md" {
+		t.Errorf("Templates sum was different: Got: %v (%v)", titles(templates), err)
+	}
+
+	nodes, _, _ := local.GetAll("", "", models.NotyaIgnoreFiles)
+	if len(nodes) != 1 || nodes[0].Title != "a.md" {
+		t.Errorf("Templates shouldn't be listed as notes, Got: %v", titles(nodes))
+	}
+
+	for _, name := range []string{"meeting", "meeting.md"} {
+		template, err := services.ViewTemplate(local, name)
+		if err != nil || template.Body != "# {{.Title}}" {
+			t.Errorf("ViewTemplate sum was different for %v: Got: %v (%v)", name, template, err)
+		}
+	}
+
+	if _, err := services.ViewTemplate(local, "missing"); err == nil {
+		t.Errorf("ViewTemplate should fail for missing templates")
+	}
+}
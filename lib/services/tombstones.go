@@ -0,0 +1,190 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/insolite-dev/notya/assets"
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/pkg"
+)
+
+// tombstoneChange is a pending change of tombstones, that's recorded during a batch.
+// [removed] is false for those nodes, that were created again.
+type tombstoneChange struct {
+	title   string
+	removed bool
+	at      time.Time
+}
+
+// tombstoneBatch collects the tombstone changes of a running operation.
+type tombstoneBatch struct {
+	depth   int
+	changes []tombstoneChange
+}
+
+// tombstoneBatches are the running batches, by the keys of their services.
+// Wrapper services share the key of wrapped service, since they share the tombstones note.
+var (
+	tombstoneMu      sync.Mutex
+	tombstoneBatches = map[string]*tombstoneBatch{}
+)
+
+// BatchTombstones starts a batch of tombstone changes at service [s], and returns its flush function.
+// Tombstones recorded (or cleared) until flushing are written to the service at once,
+// instead of rewriting the tombstones note for each node.
+//
+// Batches could be nested, in that case only the outermost flush writes the changes.
+func BatchTombstones(s ServiceRepo) func() error {
+	key := ServiceKey(s)
+
+	tombstoneMu.Lock()
+	batch, ok := tombstoneBatches[key]
+	if !ok {
+		batch = &tombstoneBatch{}
+		tombstoneBatches[key] = batch
+	}
+	batch.depth++
+	tombstoneMu.Unlock()
+
+	return func() error {
+		tombstoneMu.Lock()
+		batch.depth--
+		if batch.depth > 0 {
+			tombstoneMu.Unlock()
+			return nil
+		}
+
+		delete(tombstoneBatches, key)
+		tombstoneMu.Unlock()
+
+		return writeTombstones(s, batch.changes)
+	}
+}
+
+// Tombstones reads the tombstones of provided service.
+// Tombstones are kept as a hidden note of service itself, at [models.TombstonesName].
+// So, each service implementation gets them without any extra storage.
+func Tombstones(s ServiceRepo) (models.Tombstones, error) {
+	tombstones, _, err := readTombstones(s)
+	return tombstones, err
+}
+
+// readTombstones reads the tombstones of [s], and checks if tombstones note exists.
+func readTombstones(s ServiceRepo) (models.Tombstones, bool, error) {
+	node := models.Node{Title: models.TombstonesName}
+	if exists, err := s.IsNodeExists(node); err != nil || !exists {
+		return models.Tombstones{}, false, err
+	}
+
+	note, err := s.View(node.ToNote())
+	if err != nil {
+		return nil, true, err
+	}
+
+	return models.DecodeTombstones(note.Body), true, nil
+}
+
+// RecordTombstone saves a tombstone of removed node at provided service.
+func RecordTombstone(s ServiceRepo, title string) error {
+	return changeTombstones(s, tombstoneChange{title: title, removed: true, at: time.Now().UTC()})
+}
+
+// ClearTombstone drops the tombstone of node at [title], which was created again.
+func ClearTombstone(s ServiceRepo, title string) error {
+	return changeTombstones(s, tombstoneChange{title: title})
+}
+
+// MoveTombstone records the tombstone of renamed node at [from], and clears the tombstone of [to].
+func MoveTombstone(s ServiceRepo, from, to string) error {
+	return batched(s, func() error {
+		if err := RecordTombstone(s, from); err != nil {
+			return err
+		}
+
+		return ClearTombstone(s, to)
+	})
+}
+
+// changeTombstones appends [change] to the running batch of [s], or writes it directly.
+func changeTombstones(s ServiceRepo, change tombstoneChange) error {
+	if pkg.IsIgnorable(strings.TrimSuffix(change.title, "/"), models.NotyaIgnoreFiles) {
+		return nil
+	}
+
+	tombstoneMu.Lock()
+	batch, ok := tombstoneBatches[ServiceKey(s)]
+	if ok {
+		batch.changes = append(batch.changes, change)
+	}
+	tombstoneMu.Unlock()
+
+	if ok {
+		return nil
+	}
+
+	return writeTombstones(s, []tombstoneChange{change})
+}
+
+// writeTombstones applies [changes] to the tombstones of [s], and writes them back once.
+// If none of changes modifies the tombstones, nothing is written.
+func writeTombstones(s ServiceRepo, changes []tombstoneChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	tombstones, exists, err := readTombstones(s)
+	if err != nil {
+		return assets.CannotDoSth("record tombstone of", changes[0].title, err)
+	}
+
+	modified := false
+	for _, c := range changes {
+		if c.removed {
+			tombstones, modified = tombstones.Add(c.title, s.Type(), c.at), true
+		} else if cleared := tombstones.Remove(c.title); len(cleared) != len(tombstones) {
+			tombstones, modified = cleared, true
+		}
+	}
+
+	if !modified {
+		return nil
+	}
+
+	note := models.Note{Title: models.TombstonesName, Body: tombstones.ToString()}
+	if err := put(s, note, exists); err != nil {
+		return assets.CannotDoSth("record tombstone of", changes[0].title, err)
+	}
+
+	return nil
+}
+
+// Prune removes those nodes from [to] service, that were removed from [from] service.
+// A node is pruned only if it's covered by tombstones of [from], doesn't exist on [from] anymore,
+// and wasn't modified on [to] after its removal. So, re-created and edited nodes would never be pruned.
+func Prune(from, to ServiceRepo) ([]models.Node, []error) {
+	fromNodes, err := syncNodes(from)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	toNodes, err := syncNodes(to)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	deletes, err := planPrune(from, fromNodes, toNodes)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	plan := models.Plan{Act: "prune", From: from.Type(), To: to.Type(), Changes: deletes}
+	return ApplyPlan(plan, to)
+}
@@ -0,0 +1,104 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/lib/services"
+)
+
+func TestRecordTombstone(t *testing.T) {
+	local := newTestLocalService(t)
+
+	local.Mkdir(models.Folder{Title: "ideas/"})
+	local.Create(models.Note{Title: "ideas/a.md"})
+	local.Create(models.Note{Title: "b.md"})
+
+	local.Remove(models.Node{Title: "ideas"})
+	local.Rename(models.EditNode{Current: models.Node{Title: "b.md"}, New: models.Node{Title: "c.md"}})
+
+	tombstones, err := services.Tombstones(local)
+	if err != nil {
+		t.Fatalf("Tombstones returned an error: %v", err)
+	}
+
+	for _, title := range []string{"ideas/a.md", "ideas/", "b.md"} {
+		if !tombstones.Covers(title) {
+			t.Errorf("Tombstone of %v wasn't recorded, Got: %v", title, tombstones)
+		}
+	}
+
+	if tombstones.Covers("c.md") {
+		t.Errorf("Renamed note shouldn't be covered by tombstones")
+	}
+
+	// Tombstones of re-created nodes are cleared.
+	local.Mkdir(models.Folder{Title: "ideas/"})
+	local.Rename(models.EditNode{Current: models.Node{Title: "c.md"}, New: models.Node{Title: "b.md"}})
+
+	tombstones, _ = services.Tombstones(local)
+	if tombstones.Covers("ideas/") || tombstones.Covers("b.md") || !tombstones.Covers("c.md") {
+		t.Errorf("Tombstones of re-created nodes should be cleared, Got: %v", tombstones)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	local, remote := newTestLocalService(t), newTestLocalService(t)
+
+	for _, s := range []*services.LocalService{local, remote} {
+		s.Mkdir(models.Folder{Title: "ideas/"})
+		s.Create(models.Note{Title: "ideas/a.md"})
+		s.Create(models.Note{Title: "b.md"})
+		s.Create(models.Note{Title: "c.md"})
+	}
+
+	local.Remove(models.Node{Title: "ideas/"})
+	local.Remove(models.Node{Title: "b.md"})
+	local.Create(models.Note{Title: "b.md"}) // re-created notes shouldn't be pruned.
+
+	pruned, errs := services.Prune(local, remote)
+	if len(errs) > 0 || len(pruned) != 2 {
+		t.Fatalf("Prune sum was different: Pruned: %v | Errors: %v", pruned, errs)
+	}
+
+	for title, expected := range map[string]bool{"ideas/": false, "ideas/a.md": false, "b.md": true, "c.md": true} {
+		if got, _ := remote.IsNodeExists(models.Node{Title: title}); got != expected {
+			t.Errorf("Existence of %v was different after prune: Want: %v | Got: %v", title, expected, got)
+		}
+	}
+}
+
+func TestPruneModified(t *testing.T) {
+	local, remote := newTestLocalService(t), newTestLocalService(t)
+
+	for _, s := range []*services.LocalService{local, remote} {
+		s.Mkdir(models.Folder{Title: "ideas/"})
+		s.Create(models.Note{Title: "ideas/a.md"})
+		s.Create(models.Note{Title: "ideas/b.md"})
+	}
+
+	local.Remove(models.Node{Title: "ideas/"})
+
+	// Note was edited on remote, after it was removed on local.
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(remote.Config.NotesPath+"ideas/b.md", later, later)
+
+	pruned, errs := services.Prune(local, remote)
+	if len(errs) > 0 || len(pruned) != 1 || pruned[0].Title != "ideas/a.md" {
+		t.Fatalf("Prune sum was different: Pruned: %v | Errors: %v", titles(pruned), errs)
+	}
+
+	for title, expected := range map[string]bool{"ideas/": true, "ideas/a.md": false, "ideas/b.md": true} {
+		if got, _ := remote.IsNodeExists(models.Node{Title: title}); got != expected {
+			t.Errorf("Existence of %v was different after prune: Want: %v | Got: %v", title, expected, got)
+		}
+	}
+}
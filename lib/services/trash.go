@@ -0,0 +1,333 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/insolite-dev/notya/assets"
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/pkg"
+)
+
+// Trasher is implemented by those services, that keep removed nodes at a trash area.
+type Trasher interface {
+	// PutTrash saves [entry] at the trash area.
+	PutTrash(entry models.TrashEntry) error
+
+	// TrashEntries reads all entries of the trash area.
+	TrashEntries() (models.Trash, error)
+
+	// DropTrash permanently removes the entry of [id] from the trash area.
+	DropTrash(id string) error
+}
+
+// Mark [LocalService] and [FirebaseService] as [Trasher].
+var (
+	_ Trasher = &LocalService{}
+	_ Trasher = &FirebaseService{}
+)
+
+// MoveToTrash removes [node] from provided service, and keeps it (with its sub nodes) at trash.
+// If service doesn't keep a trash, [assets.TrashNotAvailable] is returned and nothing is removed.
+func MoveToTrash(s ServiceRepo, node models.Node) error {
+	return trashed(s, node, func() error { return s.Remove(node) })
+}
+
+// CutToTrash cuts [note] via provided service, and keeps it at trash.
+func CutToTrash(s ServiceRepo, note models.Note) (*models.Note, error) {
+	var cut *models.Note
+
+	err := trashed(s, note.ToNode(), func() (err error) {
+		cut, err = s.Cut(note)
+		return err
+	})
+
+	return cut, err
+}
+
+// TrashAll moves all top-level nodes of provided service to trash.
+func TrashAll(s ServiceRepo) ([]models.Node, []error) {
+	nodes, err := syncNodes(s)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	var res []models.Node
+	var errs []error
+
+	for _, title := range sortedTitles(keys(nodes)) {
+		if strings.Contains(strings.TrimSuffix(title, "/"), "/") {
+			continue
+		}
+
+		if err := MoveToTrash(s, nodes[title]); err == assets.TrashNotAvailable {
+			return nil, []error{err}
+		} else if err != nil {
+			errs = append(errs, assets.CannotDoSth("remove", title, err))
+			continue
+		}
+
+		res = append(res, nodes[title])
+	}
+
+	return res, errs
+}
+
+// TrashList returns entries of trash, from the latest removed to the earliest.
+func TrashList(s ServiceRepo) (models.Trash, error) {
+	trasher, ok := s.(Trasher)
+	if !ok {
+		return nil, assets.TrashNotAvailable
+	}
+
+	trash, err := trasher.TrashEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	return trash.Sort(), nil
+}
+
+// RestoreTrash re-creates the latest removed entry of [title] at its original place,
+// and drops it from trash. Missing parent folders of entry are re-created too.
+func RestoreTrash(s ServiceRepo, title string) (*models.TrashEntry, error) {
+	trasher, ok := s.(Trasher)
+	if !ok {
+		return nil, assets.TrashNotAvailable
+	}
+
+	trash, err := trasher.TrashEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	entry := trash.Find(title)
+	if entry == nil {
+		return nil, assets.NotInTrash(title)
+	}
+
+	if exists, _ := s.IsNodeExists(models.Node{Title: entry.Title}); exists {
+		return nil, assets.AlreadyExists(entry.Title, "file or folder")
+	}
+
+	if err := mkdirParents(s, entry.Title); err != nil {
+		return nil, err
+	}
+
+	nodes := entry.Nodes
+	sort.SliceStable(nodes, func(i, j int) bool { return len(nodes[i].Title) < len(nodes[j].Title) })
+
+	for _, n := range nodes {
+		if n.IsFolder() {
+			if exists, _ := s.IsNodeExists(n); exists {
+				continue
+			}
+
+			if _, err := s.Mkdir(n.ToFolder()); err != nil {
+				return nil, assets.CannotDoSth("restore", n.Title, err)
+			}
+
+			continue
+		}
+
+		if _, err := s.Create(n.ToNote()); err != nil {
+			return nil, assets.CannotDoSth("restore", n.Title, err)
+		}
+	}
+
+	return entry, trasher.DropTrash(entry.ID)
+}
+
+// EmptyTrash permanently removes those entries of trash, that were removed more than [olderThan] ago.
+// Zero [olderThan] empties the whole trash.
+func EmptyTrash(s ServiceRepo, olderThan time.Duration) (models.Trash, []error) {
+	trasher, ok := s.(Trasher)
+	if !ok {
+		return nil, []error{assets.TrashNotAvailable}
+	}
+
+	trash, err := trasher.TrashEntries()
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	res := models.Trash{}
+	errs := []error{}
+
+	for _, e := range trash.OlderThan(olderThan, time.Now().UTC()) {
+		if err := trasher.DropTrash(e.ID); err != nil {
+			errs = append(errs, assets.CannotDoSth("empty", e.Title, err))
+			continue
+		}
+
+		res = append(res, e)
+	}
+
+	return res, errs
+}
+
+// trashed keeps [node] at trash, and then removes it via [remove].
+// If removing fails, the kept entry is dropped back.
+func trashed(s ServiceRepo, node models.Node, remove func() error) error {
+	trasher, ok := s.(Trasher)
+	if !ok {
+		return assets.TrashNotAvailable
+	}
+
+	entry, err := trashEntry(s, node)
+	if err != nil {
+		return err
+	}
+
+	// Wrapper services are trashers, even if the wrapped service doesn't keep a trash.
+	if err := trasher.PutTrash(*entry); err == assets.TrashNotAvailable {
+		return err
+	} else if err != nil {
+		return assets.CannotDoSth("move to trash", node.Title, err)
+	}
+
+	if err := remove(); err != nil {
+		_ = trasher.DropTrash(entry.ID)
+		return err
+	}
+
+	return nil
+}
+
+// trashEntry collects [node] and its sub nodes (if it's a folder) to a trash entry.
+func trashEntry(s ServiceRepo, node models.Node) (*models.TrashEntry, error) {
+	title, nodes, err := nodesAt(s, node)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := models.NewTrashEntry(title, s.Type(), nodes, time.Now().UTC())
+	return &entry, nil
+}
+
+// nodesAt collects the note at [node], or the folder at [node] with its sub nodes, sorted by their titles.
+// Normalized title of [node] is returned too, which ends with a slash for folders.
+//
+// Notes are read directly. Folders, and notes that can't be viewed (like notes of locked vaults)
+// are looked up at their parent folder, so all nodes of service aren't listed.
+func nodesAt(s ServiceRepo, node models.Node) (string, []models.Node, error) {
+	if !strings.HasSuffix(node.Title, "/") {
+		if note, err := s.View(node.ToNote()); err == nil {
+			n := note.ToNode()
+			n.Title = node.ToNote().Title
+
+			return n.Title, []models.Node{n}, nil
+		}
+	}
+
+	nodes, err := folderNodes(s, parentTitle(node.ToNote().Title))
+	if err != nil {
+		return "", nil, err
+	}
+
+	n, ok := nodes[node.ToNote().Title]
+	if !ok {
+		n, ok = nodes[node.ToFolder().Title]
+	}
+
+	if !ok {
+		return "", nil, assets.NotExists(node.Title, "File or Directory")
+	}
+
+	title := n.ToNote().Title
+	if n.IsFolder() {
+		title = n.ToFolder().Title
+	}
+
+	res := []models.Node{}
+	for t, sub := range nodes {
+		if t == title || (n.IsFolder() && strings.HasPrefix(t, title)) {
+			sub.Title = t
+			res = append(res, sub)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Title < res[j].Title })
+	return title, res, nil
+}
+
+// mkdirParents creates missing parent folders of [title].
+func mkdirParents(s ServiceRepo, title string) error {
+	parts := strings.Split(strings.Trim(title, "/"), "/")
+
+	parent := ""
+	for _, p := range parts[:len(parts)-1] {
+		parent += p + "/"
+
+		if exists, _ := s.IsNodeExists(models.Node{Title: parent}); exists {
+			continue
+		}
+
+		if _, err := s.Mkdir(models.Folder{Title: parent}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// TrashPath returns the path of hidden trash folder.
+func (l *LocalService) TrashPath() string {
+	return l.NotyaPath + models.TrashName + "/"
+}
+
+// PutTrash saves [entry] as a JSON file at trash folder.
+func (l *LocalService) PutTrash(entry models.TrashEntry) error {
+	if !pkg.FileExists(l.TrashPath()) {
+		if err := pkg.NewFolder(l.TrashPath()); err != nil {
+			return err
+		}
+	}
+
+	return pkg.WriteNote(l.TrashPath()+entry.ID+".json", entry.ToString())
+}
+
+// TrashEntries reads all entries of trash folder.
+// Files that cannot be decoded as entries are skipped.
+func (l *LocalService) TrashEntries() (models.Trash, error) {
+	trash := models.Trash{}
+	if !pkg.FileExists(l.TrashPath()) {
+		return trash, nil
+	}
+
+	files, err := os.ReadDir(l.TrashPath())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range files {
+		if f.IsDir() || filepath.Ext(f.Name()) != ".json" {
+			continue
+		}
+
+		body, err := pkg.ReadBody(l.TrashPath() + f.Name())
+		if err != nil {
+			continue
+		}
+
+		if entry, err := models.DecodeTrashEntry(*body); err == nil {
+			trash = append(trash, *entry)
+		}
+	}
+
+	return trash, nil
+}
+
+// DropTrash deletes the entry file of [id] from trash folder.
+func (l *LocalService) DropTrash(id string) error {
+	return pkg.Delete(l.TrashPath() + id + ".json")
+}
@@ -0,0 +1,191 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/insolite-dev/notya/assets"
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/lib/services"
+)
+
+func TestMoveToTrash(t *testing.T) {
+	local := newTestLocalService(t)
+
+	local.Mkdir(models.Folder{Title: "ideas/"})
+	local.Create(models.Note{Title: "ideas/todo.md", Body: "todo"})
+	local.Create(models.Note{Title: "b.md", Body: "b"})
+
+	for _, title := range []string{"ideas", "b.md"} {
+		if err := services.MoveToTrash(local, models.Node{Title: title}); err != nil {
+			t.Fatalf("MoveToTrash returned an error for %v: %v", title, err)
+		}
+	}
+
+	if err := services.MoveToTrash(local, models.Node{Title: "missing.md"}); err == nil {
+		t.Errorf("MoveToTrash should fail for missing nodes")
+	}
+
+	nodes, _, _ := local.GetAll("", "", models.NotyaIgnoreFiles)
+	if len(nodes) != 0 {
+		t.Errorf("Trashed nodes should be removed from notes, Got: %v", titles(nodes))
+	}
+
+	trash, err := services.TrashList(local)
+	if err != nil || len(trash) != 2 {
+		t.Fatalf("TrashList sum was different: Got: %v (%v)", trash, err)
+	}
+
+	ideas := trash.Find("ideas")
+	if ideas == nil || ideas.Title != "ideas/" || len(ideas.Nodes) != 2 || ideas.Nodes[1].Body != "todo" {
+		t.Errorf("Trashed folder should keep its sub nodes, Got: %v", ideas)
+	}
+
+	tombstones, _ := services.Tombstones(local)
+	if !tombstones.Covers("ideas/todo.md") || !tombstones.Covers("b.md") {
+		t.Errorf("Trashed nodes should still be recorded as removed, Got: %v", tombstones)
+	}
+}
+
+func TestMoveToTrashNested(t *testing.T) {
+	local := newTestLocalService(t)
+
+	local.Mkdir(models.Folder{Title: "ideas/"})
+	local.Mkdir(models.Folder{Title: "ideas/sub/"})
+	local.Create(models.Note{Title: "ideas/sub/a.md", Body: "a"})
+	local.Create(models.Note{Title: "ideas/b.md", Body: "b"})
+
+	if err := services.MoveToTrash(local, models.Node{Title: "ideas/sub"}); err != nil {
+		t.Fatalf("MoveToTrash returned an error: %v", err)
+	}
+
+	trash, _ := services.TrashList(local)
+	sub := trash.Find("ideas/sub")
+	if sub == nil || sub.Title != "ideas/sub/" || len(sub.Nodes) != 2 || sub.Nodes[1].Title != "ideas/sub/a.md" {
+		t.Errorf("Trashed nested folder should keep its full titles, Got: %v", sub)
+	}
+
+	if exists, _ := local.IsNodeExists(models.Node{Title: "ideas/b.md"}); !exists {
+		t.Errorf("Other nodes of parent folder should be left untouched")
+	}
+}
+
+func TestMoveToTrashNotAvailable(t *testing.T) {
+	served := newTestLocalService(t)
+	served.Create(models.Note{Title: "a.md", Body: "a"})
+
+	// HTTP service doesn't keep a trash, but its wrappers are trashers.
+	wrapped, _ := newTestEncryptedService(t)
+	wrapped.ServiceRepo = newTestHTTPService(t, served)
+
+	if err := services.MoveToTrash(wrapped, models.Node{Title: "a.md"}); err != assets.TrashNotAvailable {
+		t.Errorf("MoveToTrash should fail with TrashNotAvailable, Got: %v", err)
+	}
+
+	if exists, _ := served.IsNodeExists(models.Node{Title: "a.md"}); !exists {
+		t.Errorf("Node shouldn't be removed, if it can't be kept at trash")
+	}
+
+	if _, errs := services.TrashAll(wrapped); len(errs) != 1 || errs[0] != assets.TrashNotAvailable {
+		t.Errorf("TrashAll should fail with TrashNotAvailable, Got: %v", errs)
+	}
+}
+
+func TestCutToTrash(t *testing.T) {
+	local := newTestLocalService(t)
+	local.Create(models.Note{Title: "a.md", Body: "a"})
+
+	// Clipboard might be unavailable at test machine, so cutting may fail.
+	// Then, nothing should be left at trash.
+	_, err := services.CutToTrash(local, models.Note{Title: "a.md"})
+
+	trash, _ := services.TrashList(local)
+	if (err == nil) != (len(trash) == 1) {
+		t.Errorf("CutToTrash sum was different: Trash: %v | Error: %v", trash, err)
+	}
+}
+
+func TestTrashAll(t *testing.T) {
+	local := newTestLocalService(t)
+
+	local.Mkdir(models.Folder{Title: "ideas/"})
+	local.Create(models.Note{Title: "ideas/todo.md"})
+	local.Create(models.Note{Title: "b.md"})
+
+	trashed, errs := services.TrashAll(local)
+	if len(errs) > 0 || len(trashed) != 2 {
+		t.Fatalf("TrashAll sum was different: Trashed: %v | Errors: %v", titles(trashed), errs)
+	}
+
+	trash, _ := services.TrashList(local)
+	if len(trash) != 2 {
+		t.Errorf("TrashAll should keep each top-level node as an entry, Got: %v", trash)
+	}
+}
+
+func TestRestoreTrash(t *testing.T) {
+	local := newTestLocalService(t)
+
+	local.Mkdir(models.Folder{Title: "ideas/"})
+	local.Mkdir(models.Folder{Title: "ideas/old/"})
+	local.Create(models.Note{Title: "ideas/old/todo.md", Body: "todo"})
+	local.Create(models.Note{Title: "ideas/b.md", Body: "b"})
+
+	services.MoveToTrash(local, models.Node{Title: "ideas/old/todo.md"})
+	services.MoveToTrash(local, models.Node{Title: "ideas"})
+
+	// Parent folders of restored note, have to be re-created.
+	entry, err := services.RestoreTrash(local, "ideas/old/todo.md")
+	if err != nil || entry.Title != "ideas/old/todo.md" {
+		t.Fatalf("RestoreTrash sum was different: Got: %v (%v)", entry, err)
+	}
+
+	note, err := local.View(models.Note{Title: "ideas/old/todo.md"})
+	if err != nil || note.Body != "todo" {
+		t.Errorf("Restored note was different: Got: %v (%v)", note, err)
+	}
+
+	// Restoring the folder, must keep the already restored note.
+	if _, err := services.RestoreTrash(local, "ideas/"); err == nil {
+		t.Errorf("RestoreTrash should fail, if the original title already exists")
+	}
+
+	local.Remove(models.Node{Title: "ideas"})
+	if _, err := services.RestoreTrash(local, "ideas"); err != nil {
+		t.Fatalf("RestoreTrash returned an error: %v", err)
+	}
+
+	for _, title := range []string{"ideas/", "ideas/b.md"} {
+		if exists, _ := local.IsNodeExists(models.Node{Title: title}); !exists {
+			t.Errorf("%v should be restored", title)
+		}
+	}
+
+	if _, err := services.RestoreTrash(local, "ideas"); err == nil {
+		t.Errorf("Restored entries should be dropped from trash")
+	}
+}
+
+func TestEmptyTrash(t *testing.T) {
+	local := newTestLocalService(t)
+
+	now := time.Now().UTC()
+	local.PutTrash(models.NewTrashEntry("old.md", local.Type(), nil, now.Add(-40*24*time.Hour)))
+	local.PutTrash(models.NewTrashEntry("new.md", local.Type(), nil, now.Add(-time.Hour)))
+
+	emptied, errs := services.EmptyTrash(local, 30*24*time.Hour)
+	if len(errs) > 0 || len(emptied) != 1 || emptied[0].Title != "old.md" {
+		t.Errorf("EmptyTrash sum was different: Emptied: %v | Errors: %v", emptied, errs)
+	}
+
+	emptied, errs = services.EmptyTrash(local, 0)
+	if len(errs) > 0 || len(emptied) != 1 || emptied[0].Title != "new.md" {
+		t.Errorf("EmptyTrash sum was different: Emptied: %v | Errors: %v", emptied, errs)
+	}
+}
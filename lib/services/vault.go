@@ -0,0 +1,652 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+	"github.com/insolite-dev/notya/assets"
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/pkg"
+)
+
+// VaultService is a wrapper of service repo, that encrypts notes of vault folders
+// by their own keys. Keys are provided by [Key], only while vaults are unlocked.
+//
+//	╭─────────────────────╮  plain  ╭───────────────╮  sealed (in vaults)  ╭─────────────────╮
+//	│ Interface Commands  │ ──────▶ │ Vault Service │ ───────────────────▶ │ Wrapped Service │
+//	╰─────────────────────╯         ╰───────────────╯                      ╰─────────────────╯
+//
+// While a vault is locked, its notes can't be viewed, edited or copied, and they're
+// listed as locked (with their sealed bodies), so they still can be removed, moved to
+// trash or transferred to other services.
+type VaultService struct {
+	ServiceRepo
+
+	Stdargs models.StdArgs
+
+	// Key returns the cipher of unlocked [vault], or [assets.VaultLocked] if it's locked.
+	Key func(vault models.Vault) (*pkg.Cipher, error)
+}
+
+// Mark [VaultService] as [ServiceRepo], [Trasher], [Versioner], [Committer], [Indexer] and [FullTextSearcher].
+var (
+	_ ServiceRepo      = &VaultService{}
+	_ Trasher          = &VaultService{}
+	_ Versioner        = &VaultService{}
+	_ Committer        = &VaultService{}
+	_ Indexer          = &VaultService{}
+	_ FullTextSearcher = &VaultService{}
+)
+
+// NewVaultService wraps [s] by vault folders, which keys are provided by [key].
+func NewVaultService(s ServiceRepo, stdargs models.StdArgs, key func(vault models.Vault) (*pkg.Cipher, error)) *VaultService {
+	return &VaultService{ServiceRepo: s, Stdargs: stdargs, Key: key}
+}
+
+// unlocked returns the cipher of vault, that node at [title] is inside of.
+// Nil cipher is returned for nodes out of vaults, and error for nodes of locked vaults.
+func (v *VaultService) unlocked(title string) (*pkg.Cipher, error) {
+	settings := v.StateConfig()
+
+	vault := settings.VaultOf(title)
+	if vault == nil {
+		return nil, nil
+	}
+
+	return v.Key(*vault)
+}
+
+// Seal encrypts [body] of note at [title] by the key of its vault.
+// Bodies of notes out of vaults are returned as they are.
+func (v *VaultService) Seal(title, body string) (string, error) {
+	settings := v.StateConfig()
+	if settings.VaultOf(title) == nil {
+		return body, nil
+	}
+
+	c, err := v.unlocked(title)
+	if err != nil {
+		return "", err
+	}
+
+	return c.Encrypt(title, body)
+}
+
+// sealNode seals body of [n] via [Seal], unless it's a locked node, which body is already sealed.
+func (v *VaultService) sealNode(n models.Node) (string, error) {
+	if n.Locked {
+		return n.Body, nil
+	}
+
+	return v.Seal(n.Title, n.Body)
+}
+
+// Unseal decrypts [body] of note at [title] by the key of its vault.
+func (v *VaultService) Unseal(title, body string) (string, error) {
+	c, err := v.unlocked(title)
+	if err != nil {
+		return "", err
+	}
+
+	if !strings.HasPrefix(body, pkg.VaultPrefix) {
+		return body, nil
+	}
+
+	// Sealed by a vault, which doesn't exist anymore.
+	if c == nil {
+		return "", assets.CannotDecrypt
+	}
+
+	return c.Decrypt(title, body)
+}
+
+// Open opens notes of vaults via a decrypted temporary file. Other nodes are opened as they are.
+func (v *VaultService) Open(node models.Node) error {
+	if node.IsFolder() || strings.HasSuffix(node.Title, "/") {
+		return v.ServiceRepo.Open(node)
+	}
+
+	c, err := v.unlocked(node.Title)
+	if err != nil {
+		return err
+	}
+
+	if c == nil {
+		return v.ServiceRepo.Open(node)
+	}
+
+	return openViaTemp(v, v.Stdargs, node)
+}
+
+// Create seals body of [note] (if it's inside a vault), and creates it at wrapped service.
+// Locked notes (like the ones transferred from other services) are created with their sealed bodies.
+func (v *VaultService) Create(note models.Note) (*models.Note, error) {
+	body := note.Body
+
+	sealed, err := v.sealNode(note.ToNode())
+	if err != nil {
+		return nil, err
+	}
+
+	note.Body = sealed
+	created, err := v.ServiceRepo.Create(note)
+	if err != nil {
+		return nil, err
+	}
+
+	created.Body = body
+	return created, nil
+}
+
+// View reads the note from wrapped service, and decrypts its body, if it's inside a vault.
+func (v *VaultService) View(note models.Note) (*models.Note, error) {
+	if _, err := v.unlocked(note.Title); err != nil {
+		return nil, err
+	}
+
+	n, err := v.ServiceRepo.View(note)
+	if err != nil {
+		return nil, err
+	}
+
+	if n.Body, err = v.Unseal(note.Title, n.Body); err != nil {
+		return nil, assets.CannotDoSth("view", note.Title, err)
+	}
+
+	return n, nil
+}
+
+// Edit seals body of [note] (if it's inside a vault), and overwrites it at wrapped service.
+// Sealed body of unchanged notes is re-used, like at [EncryptedService.Edit].
+func (v *VaultService) Edit(note models.Note) (*models.Note, error) {
+	body := note.Body
+
+	sealed, err := v.sealNode(note.ToNode())
+	if err != nil {
+		return nil, err
+	}
+
+	if current, err := v.ServiceRepo.View(note); err == nil && !note.Locked && strings.HasPrefix(current.Body, pkg.VaultPrefix) {
+		if plain, err := v.Unseal(note.Title, current.Body); err == nil && plain == body {
+			sealed = current.Body
+		}
+	}
+
+	note.Body = sealed
+	edited, err := v.ServiceRepo.Edit(note)
+	if err != nil {
+		return nil, err
+	}
+
+	edited.Body = body
+	return edited, nil
+}
+
+// Copy writes decrypted body of note to machine's clipboard.
+func (v *VaultService) Copy(note models.Note) error {
+	n, err := v.View(note)
+	if err != nil {
+		return err
+	}
+
+	return clipboard.WriteAll(n.Body)
+}
+
+// Cut writes decrypted body of note to machine's clipboard, and removes the note.
+func (v *VaultService) Cut(note models.Note) (*models.Note, error) {
+	n, err := v.View(note)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := clipboard.WriteAll(n.Body); err != nil {
+		return nil, err
+	}
+
+	return n, v.ServiceRepo.Remove(note.ToNode())
+}
+
+// Rename renames the node at wrapped service, and re-seals its notes by their new titles
+// (and the key of their new vault), but folders can't be moved across vault boundaries.
+func (v *VaultService) Rename(editNode models.EditNode) error {
+	settings := v.StateConfig()
+
+	title, nodes, err := nodesAt(v.ServiceRepo, editNode.Current)
+	if err != nil {
+		return err
+	}
+
+	from, to := settings.VaultOf(editNode.Current.Title), settings.VaultOf(editNode.New.Title)
+	if from != to && strings.HasSuffix(title, "/") {
+		return assets.CannotMoveVault
+	}
+
+	return renameSealed(v, v.ServiceRepo, editNode, nodes, v.Unseal, v.Seal)
+}
+
+// GetAll gets all nodes of wrapped service, with decrypted bodies of unlocked vaults.
+// Nodes of locked vaults are marked as locked, and their bodies are kept sealed.
+func (v *VaultService) GetAll(additional, typ string, ignore []string) ([]models.Node, []string, error) {
+	nodes, titles, err := v.ServiceRepo.GetAll(additional, typ, ignore)
+	if err != nil {
+		return nodes, titles, err
+	}
+
+	for i, n := range nodes {
+		title := joinTitle(additional, n.Title)
+
+		c, err := v.unlocked(title)
+		if err != nil {
+			nodes[i].Locked = true
+			continue
+		}
+
+		if c == nil || !strings.HasPrefix(n.Body, pkg.VaultPrefix) {
+			continue
+		}
+
+		body, err := c.Decrypt(title, n.Body)
+		if err != nil {
+			return nil, nil, assets.CannotDoSth("decrypt", title, err)
+		}
+
+		nodes[i].Body = body
+		nodes[i].Size, nodes[i].Hash = int64(len(body)), pkg.HashBody(body)
+		nodes[i].FillTags()
+	}
+
+	return nodes, titles, nil
+}
+
+// SealFolder re-writes each plain note of [vault] as sealed, together with its history.
+func (v *VaultService) SealFolder(vault models.Vault) ([]models.Node, []error) {
+	nodes, _, err := v.ServiceRepo.GetAll("", "file", models.NotyaIgnoreFiles)
+	if err != nil && err != assets.EmptyWorkingDirectory {
+		return nil, []error{err}
+	}
+
+	var res []models.Node
+	var errs []error
+
+	for _, n := range nodes {
+		if !vault.Contains(n.Title) || strings.HasPrefix(n.Body, pkg.VaultPrefix) {
+			continue
+		}
+
+		history, _ := v.Versions(n.Title)
+
+		note, err := v.ServiceRepo.View(n.ToNote())
+		if err == nil {
+			_, err = v.Edit(*note)
+		}
+
+		if err != nil {
+			errs = append(errs, assets.CannotDoSth("seal", n.Title, err))
+			continue
+		}
+
+		res = append(res, n)
+
+		// Re-write the history, so plain versions (including the one kept by edit above) are sealed too.
+		if err := rewriteHistory(v, n.Title, history); err != nil && err != assets.HistoryNotAvailable {
+			errs = append(errs, assets.CannotDoSth("seal history of", n.Title, err))
+		}
+	}
+
+	return res, errs
+}
+
+// PutTrash seals bodies of [entry]'s vault notes, and keeps it at trash of wrapped service.
+func (v *VaultService) PutTrash(entry models.TrashEntry) error {
+	trasher, ok := v.ServiceRepo.(Trasher)
+	if !ok {
+		return assets.TrashNotAvailable
+	}
+
+	nodes := make([]models.Node, len(entry.Nodes))
+	for i, n := range entry.Nodes {
+		if !n.IsFolder() {
+			sealed, err := v.sealNode(n)
+			if err != nil {
+				return err
+			}
+
+			if sealed != n.Body {
+				n.Body, n.Hash, n.Tags = sealed, "", nil
+			}
+		}
+
+		n.Locked = false
+		nodes[i] = n
+	}
+
+	entry.Nodes = nodes
+	return trasher.PutTrash(entry)
+}
+
+// TrashEntries reads entries of wrapped service's trash, with decrypted bodies of unlocked vaults.
+// Nodes of locked vaults are marked as locked, and their bodies are kept sealed.
+func (v *VaultService) TrashEntries() (models.Trash, error) {
+	trasher, ok := v.ServiceRepo.(Trasher)
+	if !ok {
+		return nil, assets.TrashNotAvailable
+	}
+
+	trash, err := trasher.TrashEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range trash {
+		for j, n := range trash[i].Nodes {
+			if _, err := v.unlocked(n.Title); err != nil {
+				trash[i].Nodes[j].Locked = true
+				continue
+			}
+
+			if trash[i].Nodes[j].Body, err = v.Unseal(n.Title, n.Body); err != nil {
+				return nil, assets.CannotDoSth("decrypt", n.Title, err)
+			}
+		}
+	}
+
+	return trash, nil
+}
+
+// DropTrash removes the entry of [id] from wrapped service's trash.
+func (v *VaultService) DropTrash(id string) error {
+	trasher, ok := v.ServiceRepo.(Trasher)
+	if !ok {
+		return assets.TrashNotAvailable
+	}
+
+	return trasher.DropTrash(id)
+}
+
+// SaveVersion seals body of [version] (if it's inside a vault), and saves it at wrapped service's history.
+func (v *VaultService) SaveVersion(version models.Version) error {
+	versioner, ok := v.ServiceRepo.(Versioner)
+	if !ok {
+		return assets.HistoryNotAvailable
+	}
+
+	sealed, err := v.Seal(version.Title, version.Body)
+	if err != nil {
+		return err
+	}
+
+	if sealed != version.Body {
+		version.Body, version.Hash = sealed, ""
+	}
+
+	return versioner.SaveVersion(version)
+}
+
+// DropVersions removes the whole history of note at [title] from wrapped service.
+func (v *VaultService) DropVersions(title string) error {
+	versioner, ok := v.ServiceRepo.(Versioner)
+	if !ok {
+		return assets.HistoryNotAvailable
+	}
+
+	return versioner.DropVersions(title)
+}
+
+// Versions reads history of note at [title] from wrapped service, with decrypted bodies.
+// History of notes of locked vaults can't be read.
+func (v *VaultService) Versions(title string) (models.History, error) {
+	versioner, ok := v.ServiceRepo.(Versioner)
+	if !ok {
+		return nil, assets.HistoryNotAvailable
+	}
+
+	if _, err := v.unlocked(title); err != nil {
+		return nil, err
+	}
+
+	history, err := versioner.Versions(title)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, version := range history {
+		if !strings.HasPrefix(version.Body, pkg.VaultPrefix) {
+			continue
+		}
+
+		body, err := v.Unseal(version.Title, version.Body)
+		if err != nil {
+			return nil, assets.CannotDoSth("decrypt", title, err)
+		}
+
+		history[i].Body, history[i].Size, history[i].Hash = body, int64(len(body)), pkg.HashBody(body)
+	}
+
+	return history, nil
+}
+
+// CommitPlan seals bodies of notes of [plan] (that are inside vaults), and commits it via wrapped service.
+func (v *VaultService) CommitPlan(plan models.Plan) ([]models.Node, error) {
+	return commitSealed(v.ServiceRepo, plan, v.sealNode)
+}
+
+// RebuildIndex re-indexes all notes of wrapped service from scratch.
+func (v *VaultService) RebuildIndex() (*models.Index, error) {
+	indexer, ok := v.ServiceRepo.(Indexer)
+	if !ok {
+		return nil, assets.IndexNotAvailable
+	}
+
+	return indexer.RebuildIndex()
+}
+
+// RefreshIndex re-indexes changed notes of wrapped service.
+func (v *VaultService) RefreshIndex() (*models.Index, error) {
+	indexer, ok := v.ServiceRepo.(Indexer)
+	if !ok {
+		return nil, assets.IndexNotAvailable
+	}
+
+	return indexer.RefreshIndex()
+}
+
+// FullTextSearch ranks notes of [query] via search engine of wrapped service.
+func (v *VaultService) FullTextSearch(query string) ([]models.IndexHit, error) {
+	searcher, ok := v.ServiceRepo.(FullTextSearcher)
+	if !ok {
+		return nil, assets.IndexNotAvailable
+	}
+
+	return searcher.FullTextSearch(query)
+}
+
+// CreateVault adds a vault at [folder] to [settings], with a key derived from [passphrase].
+// Vaults can't be nested into each other.
+func CreateVault(settings models.Settings, folder, passphrase string) (models.Settings, models.Vault, error) {
+	vault := models.Vault{Folder: models.VaultFolder(folder)}
+
+	for _, v := range settings.Vaults {
+		if v.Contains(vault.Folder) || vault.Contains(v.Folder) {
+			return settings, vault, assets.VaultOverlaps(vault.Folder, v.Folder)
+		}
+	}
+
+	if len(passphrase) == 0 {
+		return settings, vault, assets.EmptyPassphrase
+	}
+
+	salt, err := pkg.NewSalt()
+	if err != nil {
+		return settings, vault, err
+	}
+
+	key, err := pkg.DeriveKey(passphrase, salt)
+	if err != nil {
+		return settings, vault, err
+	}
+
+	c, err := pkg.NewVaultCipher(key)
+	if err != nil {
+		return settings, vault, err
+	}
+
+	check, err := c.Encrypt(models.SettingsName, EncryptionCheckValue)
+	if err != nil {
+		return settings, vault, err
+	}
+
+	vault.Salt, vault.Check = salt, check
+
+	settings.Vaults = append(append([]models.Vault{}, settings.Vaults...), vault)
+	return settings, vault, nil
+}
+
+// VaultKey derives the key of [vault] from [passphrase], and verifies it by the sealed check value of vault.
+func VaultKey(vault models.Vault, passphrase string) ([]byte, error) {
+	key, err := pkg.DeriveKey(passphrase, vault.Salt)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := pkg.NewVaultCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if check, err := c.Decrypt(models.SettingsName, vault.Check); err != nil || check != EncryptionCheckValue {
+		return nil, assets.WrongPassphrase
+	}
+
+	return key, nil
+}
+
+// VaultSessionsPath returns path of the file, that caches keys of unlocked vaults of [settings].
+// It's kept at the runtime directory of user ($XDG_RUNTIME_DIR), or at the cache directory of user,
+// so it's private to user, and never synced to other services (as it's out of notes folder).
+func VaultSessionsPath(settings models.Settings) string {
+	dir := os.Getenv("XDG_RUNTIME_DIR")
+	if len(dir) == 0 {
+		if cache, err := os.UserCacheDir(); err == nil {
+			dir = cache
+		} else {
+			dir = os.TempDir()
+		}
+	}
+
+	return filepath.Join(dir, "notya", "vaults-"+pkg.HashBody(settings.NotesPath)[:12]+".json")
+}
+
+// ReadVaultSessions reads active sessions of unlocked vaults from [path].
+// Files which aren't private to current user are ignored, and expired sessions are removed from the file.
+func ReadVaultSessions(path string) models.VaultSessions {
+	if info, err := os.Lstat(path); err != nil || !info.Mode().IsRegular() || !pkg.IsPrivate(info) {
+		return models.VaultSessions{}
+	}
+
+	data, err := pkg.ReadBody(path)
+	if err != nil {
+		return models.VaultSessions{}
+	}
+
+	sessions := models.DecodeVaultSessions(*data)
+
+	active := sessions.Active(time.Now())
+	if len(active) != len(sessions) {
+		_ = WriteVaultSessions(path, active)
+	}
+
+	return active
+}
+
+// WriteVaultSessions writes active [sessions] to [path], readable only by current user.
+// The file is removed, if there's no active session left.
+//
+// Sessions are written to a newly (exclusively) created file, which then replaces the one at [path].
+// So an existing file, that could be created by someone else, is never written.
+func WriteVaultSessions(path string, sessions models.VaultSessions) error {
+	sessions = sessions.Active(time.Now())
+	if len(sessions) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		return nil
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+
+	if info, err := os.Lstat(dir); err != nil || !info.IsDir() || !pkg.IsPrivate(info) {
+		return assets.InsecureVaultSessions(dir)
+	}
+
+	// Created via O_CREATE|O_EXCL, with 0600 permissions.
+	file, err := os.CreateTemp(dir, filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(file.Name())
+
+	if _, err := file.WriteString(sessions.ToString()); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), path)
+}
+
+// UnlockVault caches the [key] of [vault] at sessions of [path], for [ttl].
+func UnlockVault(path string, vault models.Vault, key []byte, ttl time.Duration) error {
+	session := models.VaultSession{
+		Folder:  vault.Folder,
+		Key:     base64.StdEncoding.EncodeToString(key),
+		Expires: time.Now().Add(ttl),
+	}
+
+	sessions := ReadVaultSessions(path).Without(vault.Folder)
+	return WriteVaultSessions(path, append(sessions, session))
+}
+
+// LockVault removes the cached key of vault at [folder] from sessions of [path].
+// Empty [folder] locks all vaults.
+func LockVault(path, folder string) error {
+	if len(folder) == 0 {
+		return WriteVaultSessions(path, models.VaultSessions{})
+	}
+
+	return WriteVaultSessions(path, ReadVaultSessions(path).Without(models.VaultFolder(folder)))
+}
+
+// SessionKey returns a key provider of [VaultService], that reads keys of unlocked vaults from sessions of [path].
+func SessionKey(path string) func(vault models.Vault) (*pkg.Cipher, error) {
+	return func(vault models.Vault) (*pkg.Cipher, error) {
+		session := ReadVaultSessions(path).Find(vault.Folder, time.Now())
+		if session == nil {
+			return nil, assets.VaultLocked(vault.Folder)
+		}
+
+		key, err := base64.StdEncoding.DecodeString(session.Key)
+		if err != nil {
+			return nil, assets.VaultLocked(vault.Folder)
+		}
+
+		return pkg.NewVaultCipher(key)
+	}
+}
@@ -0,0 +1,259 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/insolite-dev/notya/assets"
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/lib/services"
+	"github.com/insolite-dev/notya/pkg"
+)
+
+// newTestVaultService creates a vault service of a local service, with an unlocked vault at "secrets/".
+func newTestVaultService(t *testing.T) (*services.VaultService, *services.LocalService, string) {
+	local := newTestLocalService(t)
+
+	settings, vault, err := services.CreateVault(local.Config, "secrets", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	local.Config = settings
+
+	sessions := filepath.Join(t.TempDir(), "notya", "sessions.json")
+	key, _ := services.VaultKey(vault, "secret")
+	if err := services.UnlockVault(sessions, vault, key, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	return services.NewVaultService(local, models.StdArgs{}, services.SessionKey(sessions)), local, sessions
+}
+
+func TestCreateVault(t *testing.T) {
+	settings, vault, err := services.CreateVault(models.Settings{}, "/secrets", "secret")
+	if err != nil || vault.Folder != "secrets/" || len(settings.Vaults) != 1 {
+		t.Fatalf("CreateVault sum was different: Got: %v (%v)", settings.Vaults, err)
+	}
+
+	for _, folder := range []string{"secrets", "secrets/keys"} {
+		if _, _, err := services.CreateVault(settings, folder, "other"); err == nil {
+			t.Errorf("CreateVault should fail on overlapping vault at %v", folder)
+		}
+	}
+
+	if _, _, err := services.CreateVault(settings, "diary", ""); err != assets.EmptyPassphrase {
+		t.Errorf("CreateVault error was different: Want: %v | Got: %v", assets.EmptyPassphrase, err)
+	}
+
+	if _, err := services.VaultKey(vault, "wrong"); err != assets.WrongPassphrase {
+		t.Errorf("VaultKey error was different: Want: %v | Got: %v", assets.WrongPassphrase, err)
+	}
+}
+
+func TestVaultSessionsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notya", "sessions.json")
+
+	sessions := models.VaultSessions{
+		{Folder: "secrets/", Key: "a2V5", Expires: time.Now().Add(time.Minute)},
+		{Folder: "diary/", Key: "a2V5", Expires: time.Now().Add(-time.Minute)},
+	}
+
+	if err := services.WriteVaultSessions(path, sessions); err != nil {
+		t.Fatal(err)
+	}
+
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("Sessions should be readable only by current user, Got: %v (%v)", info, err)
+	}
+
+	// Expired sessions are removed from the file, once it's read.
+	os.WriteFile(path, []byte(sessions.ToString()), 0o600)
+	if got := services.ReadVaultSessions(path); len(got) != 1 || got[0].Folder != "secrets/" {
+		t.Errorf("ReadVaultSessions sum was different: Got: %v", got)
+	}
+
+	if data, _ := os.ReadFile(path); strings.Contains(string(data), "diary/") {
+		t.Errorf("ReadVaultSessions should remove expired sessions, Got: %v", string(data))
+	}
+
+	if runtime.GOOS == "windows" {
+		return
+	}
+
+	// Files accessible by other users are ignored.
+	os.Chmod(path, 0o644)
+	if got := services.ReadVaultSessions(path); len(got) != 0 {
+		t.Errorf("ReadVaultSessions should ignore public files, Got: %v", got)
+	}
+}
+
+func TestVaultService(t *testing.T) {
+	vaulted, local, sessions := newTestVaultService(t)
+
+	vaulted.Mkdir(models.Folder{Title: "secrets/"})
+	vaulted.Create(models.Note{Title: "secrets/keys.md", Body: "key #private\n"})
+	vaulted.Create(models.Note{Title: "todo.md", Body: "todo\n"})
+
+	stored, _ := local.View(models.Note{Title: "secrets/keys.md"})
+	if !strings.HasPrefix(stored.Body, pkg.VaultPrefix) {
+		t.Errorf("Create should seal notes of vault, Got: %v", stored.Body)
+	}
+
+	if plain, _ := local.View(models.Note{Title: "todo.md"}); plain.Body != "todo\n" {
+		t.Errorf("Create should keep notes out of vaults plain, Got: %v", plain.Body)
+	}
+
+	viewed, err := vaulted.View(models.Note{Title: "secrets/keys.md"})
+	if err != nil || viewed.Body != "key #private\n" {
+		t.Errorf("View sum was different: Want: %v | Got: %v (%v)", "key #private\n", viewed, err)
+	}
+
+	nodes, _, _ := vaulted.GetAll("secrets", "file", models.NotyaIgnoreFiles)
+	if len(nodes) != 1 || nodes[0].Body != "key #private\n" || nodes[0].Locked {
+		t.Errorf("GetAll should decrypt notes of unlocked vault, Got: %v", nodes)
+	}
+
+	vaulted.Edit(models.Note{Title: "secrets/keys.md", Body: "new key\n"})
+	if history, err := services.History(vaulted, "secrets/keys.md"); err != nil || len(history) != 1 || history[0].Body != "key #private\n" {
+		t.Errorf("History sum was different: Got: %v (%v)", history, err)
+	}
+
+	if err := services.LockVault(sessions, "secrets"); err != nil {
+		t.Fatal(err)
+	}
+
+	locked := assets.VaultLocked("secrets/").Error()
+
+	if _, err := vaulted.View(models.Note{Title: "secrets/keys.md"}); err == nil || err.Error() != locked {
+		t.Errorf("View error was different: Want: %v | Got: %v", locked, err)
+	}
+
+	if _, err := vaulted.Edit(models.Note{Title: "secrets/keys.md", Body: "plain"}); err == nil || err.Error() != locked {
+		t.Errorf("Edit error was different: Want: %v | Got: %v", locked, err)
+	}
+
+	if err := vaulted.Copy(models.Note{Title: "secrets/keys.md"}); err == nil || err.Error() != locked {
+		t.Errorf("Copy error was different: Want: %v | Got: %v", locked, err)
+	}
+
+	if _, err := services.History(vaulted, "secrets/keys.md"); err == nil || err.Error() != locked {
+		t.Errorf("History error was different: Want: %v | Got: %v", locked, err)
+	}
+
+	nodes, _, _ = vaulted.GetAll("", "", models.NotyaIgnoreFiles)
+	for _, n := range nodes {
+		inVault := strings.HasPrefix(n.Title, "secrets")
+		if n.Locked != inVault || (inVault && n.Body != "" && !strings.HasPrefix(n.Body, pkg.VaultPrefix)) {
+			t.Errorf("GetAll should mark nodes of locked vault as locked, Got: %v", n)
+		}
+	}
+
+	// Locked vault notes still can be moved to trash and restored, since they're kept sealed.
+	if err := services.MoveToTrash(vaulted, models.Node{Title: "secrets/keys.md"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := services.RestoreTrash(vaulted, "secrets/keys.md"); err != nil {
+		t.Fatal(err)
+	}
+
+	if restored, _ := local.View(models.Note{Title: "secrets/keys.md"}); !strings.HasPrefix(restored.Body, pkg.VaultPrefix) {
+		t.Errorf("RestoreTrash should keep the note sealed, Got: %v", restored.Body)
+	}
+}
+
+func TestVaultRename(t *testing.T) {
+	vaulted, local, _ := newTestVaultService(t)
+
+	vaulted.Mkdir(models.Folder{Title: "secrets/"})
+	vaulted.Mkdir(models.Folder{Title: "ideas/"})
+	vaulted.Create(models.Note{Title: "ideas/key.md", Body: "key\n"})
+
+	if err := vaulted.Rename(models.EditNode{Current: models.Node{Title: "ideas/key.md"}, New: models.Node{Title: "secrets/key.md"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if stored, _ := local.View(models.Note{Title: "secrets/key.md"}); !strings.HasPrefix(stored.Body, pkg.VaultPrefix) {
+		t.Errorf("Rename should seal notes moved into vault, Got: %v", stored.Body)
+	}
+
+	if viewed, _ := vaulted.View(models.Note{Title: "secrets/key.md"}); viewed.Body != "key\n" {
+		t.Errorf("View sum was different: Want: %v | Got: %v", "key\n", viewed.Body)
+	}
+
+	err := vaulted.Rename(models.EditNode{Current: models.Node{Title: "ideas"}, New: models.Node{Title: "secrets/ideas"}})
+	if err != assets.CannotMoveVault {
+		t.Errorf("Rename error was different: Want: %v | Got: %v", assets.CannotMoveVault, err)
+	}
+}
+
+func TestSealFolder(t *testing.T) {
+	vaulted, local, _ := newTestVaultService(t)
+
+	local.Mkdir(models.Folder{Title: "secrets/"})
+	local.Create(models.Note{Title: "secrets/keys.md", Body: "first\n"})
+	local.Edit(models.Note{Title: "secrets/keys.md", Body: "second\n"})
+	local.Create(models.Note{Title: "todo.md", Body: "todo\n"})
+
+	sealed, errs := vaulted.SealFolder(local.Config.Vaults[0])
+	if len(errs) != 0 || len(sealed) != 1 || sealed[0].Title != "secrets/keys.md" {
+		t.Fatalf("SealFolder sum was different: Got: %v (%v)", sealed, errs)
+	}
+
+	if stored, _ := local.View(models.Note{Title: "secrets/keys.md"}); !strings.HasPrefix(stored.Body, pkg.VaultPrefix) {
+		t.Errorf("SealFolder should seal notes of vault, Got: %v", stored.Body)
+	}
+
+	if raw, _ := local.Versions("secrets/keys.md"); len(raw) != 1 || !strings.HasPrefix(raw[0].Body, pkg.VaultPrefix) {
+		t.Errorf("SealFolder should seal history of notes, Got: %v", raw)
+	}
+
+	if plain, _ := local.View(models.Note{Title: "todo.md"}); plain.Body != "todo\n" {
+		t.Errorf("SealFolder should keep notes out of vault plain, Got: %v", plain.Body)
+	}
+}
+
+func TestVaultWithEncryption(t *testing.T) {
+	vaulted, local, _ := newTestVaultService(t)
+
+	encrypted, _ := newTestEncryptedService(t)
+	encrypted.ServiceRepo = local
+	vaulted.ServiceRepo = encrypted
+
+	vaulted.Mkdir(models.Folder{Title: "secrets/"})
+	vaulted.Create(models.Note{Title: "secrets/keys.md", Body: "key\n"})
+	vaulted.Create(models.Note{Title: "todo.md", Body: "todo\n"})
+
+	tests := []struct {
+		title, body, prefix string
+	}{
+		{title: "secrets/keys.md", body: "key\n", prefix: pkg.EncryptedPrefix},
+		{title: "todo.md", body: "todo\n", prefix: pkg.EncryptedPrefix},
+	}
+
+	// Sealed bodies of vaults are encrypted once more, by the encrypted service.
+	if sealed, _ := encrypted.View(models.Note{Title: "secrets/keys.md"}); !strings.HasPrefix(sealed.Body, pkg.VaultPrefix) {
+		t.Errorf("Decrypted body of secrets/keys.md should be sealed by vault, Got: %v", sealed.Body)
+	}
+
+	for _, td := range tests {
+		if stored, _ := local.View(models.Note{Title: td.title}); !strings.HasPrefix(stored.Body, td.prefix) {
+			t.Errorf("Stored body of %v was different: Want prefix: %v | Got: %v", td.title, td.prefix, stored.Body)
+		}
+
+		if viewed, err := vaulted.View(models.Note{Title: td.title}); err != nil || viewed.Body != td.body {
+			t.Errorf("View sum was different: Want: %v | Got: %v (%v)", td.body, viewed, err)
+		}
+	}
+}
@@ -0,0 +1,794 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+	"github.com/insolite-dev/notya/assets"
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/pkg"
+)
+
+// webdavPropfind is the body of PROPFIND requests, that asks only for the used properties.
+const webdavPropfind = `<?xml version="1.0" encoding="utf-8"?>
+<d:propfind xmlns:d="DAV:"><d:prop><d:resourcetype/><d:getlastmodified/></d:prop></d:propfind>`
+
+// WebDAVService is a class implementation of service repo.
+// Which keeps nodes as resources of a WebDAV server, like Nextcloud.
+//
+//	╭────────────────╮     ╭──────────────────────────────────────╮
+//	│ WebDAV Service │ ──▶ │ https://cloud.example.com/.../notya/ │
+//	╰────────────────╯     │  ideas/          (collection)        │
+//	                       │  ideas/note.md   (resource)          │
+//	                       ╰──────────────────────────────────────╯
+//
+// Notes are read and written via GET/PUT, folders are created via MKCOL,
+// listed via PROPFIND, and renamed via MOVE.
+type WebDAVService struct {
+	LS      ServiceRepo // embedded local service.
+	Stdargs models.StdArgs
+	Config  models.Settings
+
+	// WebDAV related.
+	Root   *url.URL
+	Client *http.Client
+}
+
+// Mark [WebDAVService] as [ServiceRepo], [Trasher] and [Versioner].
+var (
+	_ ServiceRepo = &WebDAVService{}
+	_ Trasher     = &WebDAVService{}
+	_ Versioner   = &WebDAVService{}
+)
+
+// webdavEntry is a resource of PROPFIND response.
+type webdavEntry struct {
+	Title    string
+	Folder   bool
+	Modified time.Time
+}
+
+// webdavMultistatus is the body of PROPFIND response.
+type webdavMultistatus struct {
+	Responses []struct {
+		Href     string `xml:"href"`
+		Propstat []struct {
+			Prop struct {
+				ResourceType struct {
+					Collection *struct{} `xml:"collection"`
+				} `xml:"resourcetype"`
+				LastModified string `xml:"getlastmodified"`
+			} `xml:"prop"`
+		} `xml:"propstat"`
+	} `xml:"response"`
+}
+
+// NewWebDAVService creates new WebDAV service by given arguments.
+func NewWebDAVService(stdargs models.StdArgs, ls ServiceRepo) *WebDAVService {
+	return &WebDAVService{
+		LS:      ls,
+		Stdargs: stdargs,
+		Client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Type returns type of WebDAVService - WEBDAV.
+func (s *WebDAVService) Type() string {
+	return WEBDAV.ToStr()
+}
+
+// Path returns the URL of notes collection, which is both main and notes "folder" of service.
+func (s *WebDAVService) Path() (string, string) {
+	return s.Config.WebDAVURL, s.Config.WebDAVURL
+}
+
+// StateConfig returns current configuration of state i.e [s.Config].
+func (s *WebDAVService) StateConfig() models.Settings {
+	return s.Config
+}
+
+// Init checks the collection of notes (creating it, if it doesn't exist),
+// and reads (or writes) settings of it.
+func (s *WebDAVService) Init(settings *models.Settings) error {
+	if settings != nil {
+		s.Config = *settings
+	} else {
+		localConfig, err := s.LS.Settings(nil)
+		if err != nil {
+			return err
+		}
+
+		s.Config = *localConfig // should be re-written later.
+	}
+
+	root, err := url.Parse(s.Config.WebDAVURL)
+	if err != nil || (root.Scheme != "http" && root.Scheme != "https") || len(root.Host) == 0 {
+		return assets.InvalidWebDAVURL
+	}
+
+	root.Path = strings.TrimSuffix(root.Path, "/") + "/"
+	s.Root = root
+
+	if _, exists, err := s.nodeType(""); err != nil {
+		return err
+	} else if !exists {
+		if err := s.mkcol(""); err != nil {
+			return err
+		}
+	}
+
+	config, err := s.Settings(nil)
+	if err == errWebDAVNotFound {
+		return s.WriteSettings(s.Config)
+	} else if err != nil {
+		return err
+	}
+
+	// Connection fields are kept only at this side, since the password isn't written to server.
+	config.WebDAVURL, config.WebDAVUsername, config.WebDAVPassword = s.Config.WebDAVURL, s.Config.WebDAVUsername, s.Config.WebDAVPassword
+
+	s.Config = *config // set remote settings data instead of local.
+	return nil
+}
+
+// errWebDAVNotFound is returned by requests of missing resources.
+var errWebDAVNotFound = errors.New("Not Found")
+
+// URL generates the URL of node at [title], under the collection of notes.
+func (s *WebDAVService) URL(title string, folder bool) string {
+	u := *s.Root
+	u.Path += strings.Join(splitTitle(title), "/")
+	if folder && !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+	}
+
+	return u.String()
+}
+
+// request sends a request of [method] to node at [title], and reads its response.
+func (s *WebDAVService) request(method, title string, folder bool, body io.Reader, header http.Header) (int, []byte, error) {
+	req, err := http.NewRequest(method, s.URL(title, folder), body)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	if len(s.Config.WebDAVUsername) > 0 {
+		password := s.Config.WebDAVPassword
+		if len(password) == 0 {
+			password = os.Getenv(models.WebDAVPasswordEnv)
+		}
+
+		req.SetBasicAuth(s.Config.WebDAVUsername, password)
+	}
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	return resp.StatusCode, data, err
+}
+
+// statusError converts unexpected [status] of [act] on node at [title] to an error.
+func statusError(act, title string, status int) error {
+	if status == http.StatusNotFound {
+		return errWebDAVNotFound
+	}
+
+	return assets.CannotDoSth(act, title, fmt.Errorf("%v %v", status, http.StatusText(status)))
+}
+
+// isSuccess checks if [status] is a 2xx status.
+func isSuccess(status int) bool {
+	return status >= 200 && status < 300
+}
+
+// get reads the body of resource at [title].
+func (s *WebDAVService) get(title string) (string, error) {
+	status, body, err := s.request(http.MethodGet, title, false, nil, nil)
+	if err != nil {
+		return "", err
+	} else if !isSuccess(status) {
+		return "", statusError("read", title, status)
+	}
+
+	return string(body), nil
+}
+
+// put overwrites (or creates) the resource at [title] with [body].
+func (s *WebDAVService) put(title, body string) error {
+	status, _, err := s.request(http.MethodPut, title, false, strings.NewReader(body), nil)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case status == http.StatusConflict:
+		return assets.NotExists(parentTitle(title), "Directory")
+	case !isSuccess(status):
+		return statusError("write", title, status)
+	}
+
+	return nil
+}
+
+// remove deletes the resource (or collection, with its members) at [title].
+func (s *WebDAVService) remove(title string, folder bool) error {
+	status, _, err := s.request(http.MethodDelete, title, folder, nil, nil)
+	if err != nil {
+		return err
+	} else if !isSuccess(status) {
+		return statusError("remove", title, status)
+	}
+
+	return nil
+}
+
+// mkcol creates the collection at [title].
+func (s *WebDAVService) mkcol(title string) error {
+	status, _, err := s.request("MKCOL", title, true, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case status == http.StatusMethodNotAllowed:
+		return assets.AlreadyExists(title, "folder")
+	case status == http.StatusConflict:
+		return assets.NotExists(parentTitle(title), "Directory")
+	case !isSuccess(status):
+		return statusError("create", title, status)
+	}
+
+	return nil
+}
+
+// mkcolAll creates the collection at [title], with its missing parents.
+func (s *WebDAVService) mkcolAll(title string) error {
+	names := splitTitle(title)
+	for i := range names {
+		if exists, _ := s.IsNodeExists(models.Node{Title: strings.Join(names[:i+1], "/") + "/"}); exists {
+			continue
+		}
+
+		if err := s.mkcol(strings.Join(names[:i+1], "/")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// move moves the resource (or collection) at [from] to [to], without overwriting.
+func (s *WebDAVService) move(from, to string, folder bool) error {
+	header := http.Header{"Destination": {s.URL(to, folder)}, "Overwrite": {"F"}}
+
+	status, _, err := s.request("MOVE", from, folder, nil, header)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case status == http.StatusPreconditionFailed:
+		return assets.AlreadyExists(to, "File or Directory")
+	case status == http.StatusConflict:
+		return assets.NotExists(parentTitle(to), "Directory")
+	case !isSuccess(status):
+		return statusError("move", from, status)
+	}
+
+	return nil
+}
+
+// propfind lists the resource at [title] (and its members for "1" [depth]).
+// Titles of entries are relative to the collection of notes.
+func (s *WebDAVService) propfind(title, depth string) ([]webdavEntry, error) {
+	header := http.Header{"Depth": {depth}, "Content-Type": {"application/xml; charset=utf-8"}}
+
+	status, body, err := s.request("PROPFIND", title, false, strings.NewReader(webdavPropfind), header)
+	if err != nil {
+		return nil, err
+	} else if status != http.StatusMultiStatus {
+		return nil, statusError("list", title, status)
+	}
+
+	var ms webdavMultistatus
+	if err := xml.Unmarshal(body, &ms); err != nil {
+		return nil, err
+	}
+
+	entries := []webdavEntry{}
+	for _, r := range ms.Responses {
+		href, err := url.Parse(r.Href)
+		if err != nil {
+			return nil, err
+		}
+
+		entry := webdavEntry{Title: strings.Join(splitTitle(strings.TrimPrefix(href.Path, s.Root.Path)), "/")}
+		for _, p := range r.Propstat {
+			entry.Folder = entry.Folder || p.Prop.ResourceType.Collection != nil
+			if modified, err := http.ParseTime(p.Prop.LastModified); err == nil {
+				entry.Modified = modified.UTC()
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// walk lists all members of collection at [title] recursively, via depth-1 PROPFIND requests.
+// Ignored collections are not walked into.
+func (s *WebDAVService) walk(title string, ignore []string) ([]webdavEntry, error) {
+	self := strings.Join(splitTitle(title), "/")
+
+	entries, err := s.propfind(title+"/", "1")
+	if err != nil {
+		return nil, err
+	}
+
+	res := []webdavEntry{}
+	for _, e := range entries {
+		names := splitTitle(e.Title)
+		if e.Title == self || len(names) == 0 || pkg.IsIgnorable(names[len(names)-1], ignore) {
+			continue
+		}
+
+		res = append(res, e)
+		if !e.Folder {
+			continue
+		}
+
+		sub, err := s.walk(e.Title, ignore)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, sub...)
+	}
+
+	return res, nil
+}
+
+// nodeType finds type of node at [title].
+func (s *WebDAVService) nodeType(title string) (models.NodeType, bool, error) {
+	entries, err := s.propfind(title, "0")
+	if err == errWebDAVNotFound {
+		return "", false, nil
+	} else if err != nil {
+		return "", false, err
+	}
+
+	if len(entries) > 0 && entries[0].Folder {
+		return models.FOLDER, true, nil
+	}
+
+	return models.FILE, true, nil
+}
+
+// Settings reads settings resource, which is stored by [p] (or [models.SettingsName]) at collection of notes.
+func (s *WebDAVService) Settings(p *string) (*models.Settings, error) {
+	name := models.SettingsName
+	if p != nil && len(*p) != 0 {
+		name = *p
+	}
+
+	body, err := s.get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := models.DecodeSettings(body)
+	return &settings, nil
+}
+
+// WriteSettings overwrites settings resource by given settings model, without its secrets.
+func (s *WebDAVService) WriteSettings(settings models.Settings) error {
+	if !settings.IsValid() {
+		return assets.InvalidSettingsData
+	}
+
+	settings = settings.WithoutSecrets()
+	return s.put(models.SettingsName, settings.ToString())
+}
+
+// OpenSettings opens settings resource via editor,
+// and overwrites settings resource, if they were updated.
+func (s *WebDAVService) OpenSettings(settings models.Settings) error {
+	return openSettingsViaTemp(s, s.Stdargs)
+}
+
+// IsNodeExists checks if a resource (or collection) exists for node at [node.Title].
+func (s *WebDAVService) IsNodeExists(node models.Node) (bool, error) {
+	_, exists, err := s.nodeType(node.Title)
+	return exists, err
+}
+
+// Open opens the note resource via editor (at a temporary file),
+// and overwrites the resource, after editing.
+func (s *WebDAVService) Open(node models.Node) error {
+	return openViaTemp(s, s.Stdargs, node)
+}
+
+// Remove deletes the resource of note, or the collection of folder with its members.
+func (s *WebDAVService) Remove(node models.Node) error {
+	typ, exists, err := s.nodeType(node.Title)
+	if err != nil {
+		return err
+	} else if !exists {
+		return assets.NotExists(node.Title, "File or Directory")
+	}
+
+	if err := s.remove(node.Title, typ == models.FOLDER); err != nil {
+		return err
+	}
+
+	return RecordTombstone(s, nodeTitle(node.Title, typ))
+}
+
+// Rename moves the resource (or collection) of node via a single MOVE request.
+// History of renamed node is moved along.
+func (s *WebDAVService) Rename(editNode models.EditNode) error {
+	if editNode.Current.Title == editNode.New.Title {
+		return assets.SameTitles
+	}
+
+	typ, exists, err := s.nodeType(editNode.Current.Title)
+	if err != nil {
+		return err
+	} else if !exists {
+		return assets.NotExists(editNode.Current.Title, "File or Directory")
+	}
+
+	from := strings.Join(splitTitle(editNode.Current.Title), "/")
+	to := strings.Join(splitTitle(editNode.New.Title), "/")
+
+	// Folders cannot be moved into themselves.
+	if strings.HasPrefix(to, from+"/") {
+		return assets.InvalidPathForAct
+	}
+
+	folder := typ == models.FOLDER
+	if err := s.move(from, to, folder); err != nil {
+		return err
+	}
+
+	renameHistory(s, from, to, folder, s.mkcolAll, func(src, dst string) error { return s.move(src, dst, folder) })
+
+	// Old title doesn't exist anymore, so it's recorded as removed.
+	return MoveTombstone(s, nodeTitle(from, typ), nodeTitle(to, typ))
+}
+
+// ClearNodes removes all top-level resources and collections, with their members.
+func (s *WebDAVService) ClearNodes() ([]models.Node, []error) {
+	nodes, _, err := s.GetAll("", "", models.NotyaIgnoreFiles)
+	if err != nil && err != assets.EmptyWorkingDirectory {
+		return nil, []error{err}
+	}
+
+	tombstones, err := Tombstones(s)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	var res []models.Node
+	var errs []error
+
+	now := time.Now().UTC()
+	for _, n := range nodes {
+		// Members of collections are deleted along with top-level collections.
+		if strings.Contains(strings.TrimSuffix(n.Title, "/"), "/") {
+			res = append(res, n)
+			tombstones = tombstones.Add(n.Title, s.Type(), now)
+			continue
+		}
+
+		if err := s.remove(n.Title, n.IsFolder()); err != nil {
+			errs = append(errs, assets.CannotDoSth("remove", n.Title, err))
+			continue
+		}
+
+		res = append(res, n)
+		tombstones = tombstones.Add(n.Title, s.Type(), now)
+	}
+
+	if err := s.put(models.TombstonesName, tombstones.ToString()); err != nil {
+		errs = append(errs, err)
+	}
+
+	return res, errs
+}
+
+// GetAll walks through the collection of notes, or the collection of [additional] folder.
+// Titles of nodes are relative to [additional] folder.
+func (s *WebDAVService) GetAll(additional, typ string, ignore []string) ([]models.Node, []string, error) {
+	entries, err := s.walk(additional, ignore)
+	if err == errWebDAVNotFound {
+		return nil, nil, assets.NotExists(additional, "Directory")
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	scope := strings.Join(splitTitle(additional), "/")
+
+	nodes := []models.Node{}
+	for _, e := range entries {
+		if !pkg.IsType(typ, e.Folder) {
+			continue
+		}
+
+		title := strings.TrimPrefix(strings.TrimPrefix(e.Title, scope), "/")
+		if e.Folder {
+			nodes = append(nodes, models.Node{Title: nodeTitle(title, models.FOLDER), Type: models.FOLDER, Updated: e.Modified})
+			continue
+		}
+
+		body, err := s.get(e.Title)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		node := models.Node{Title: title, Type: models.FILE, Body: body}
+		stamp(&node, e.Modified)
+
+		nodes = append(nodes, node)
+	}
+
+	if len(nodes) == 0 {
+		return nil, nil, assets.EmptyWorkingDirectory
+	}
+
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Title < nodes[j].Title })
+
+	titles := []string{}
+	for i, n := range nodes {
+		names := splitTitle(n.Title)
+
+		nodes[i].Path = map[string]string{s.Type(): s.URL(scope+"/"+n.Title, n.IsFolder())}
+		nodes[i].Pretty = []string{strings.Repeat("  ", len(names)-1) + n.GenPretty(), names[len(names)-1]}
+		titles = append(titles, n.Title)
+	}
+
+	return nodes, titles, nil
+}
+
+// Create uploads a new note resource, into the collection of its parent folder.
+func (s *WebDAVService) Create(note models.Note) (*models.Note, error) {
+	if exists, err := s.IsNodeExists(note.ToNode()); err != nil {
+		return nil, err
+	} else if exists {
+		return nil, assets.AlreadyExists(note.Title, "file")
+	}
+
+	if err := s.put(note.Title, note.Body); err != nil {
+		return nil, err
+	}
+
+	return &models.Note{Title: note.Title, Path: map[string]string{s.Type(): s.URL(note.Title, false)}, Body: note.Body}, ClearTombstone(s, note.Title)
+}
+
+// View downloads the note resource at [note.Title].
+func (s *WebDAVService) View(note models.Note) (*models.Note, error) {
+	body, err := s.get(note.Title)
+	if err == errWebDAVNotFound {
+		return nil, assets.NotExists(note.Title, "File")
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &models.Note{Title: note.Title, Path: map[string]string{s.Type(): s.URL(note.Title, false)}, Body: body}, nil
+}
+
+// Edit overwrites the existing note resource, and keeps its previous body at history.
+func (s *WebDAVService) Edit(note models.Note) (*models.Note, error) {
+	entries, err := s.propfind(note.Title, "0")
+	if err == errWebDAVNotFound || (err == nil && entries[0].Folder) {
+		return nil, assets.NotExists(note.Title, "File")
+	} else if err != nil {
+		return nil, err
+	}
+
+	previous, err := s.get(note.Title)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.put(note.Title, note.Body); err != nil {
+		return nil, err
+	}
+
+	keepVersion(s, NewVersion(note.Title, previous, entries[0].Modified), note.Body)
+
+	return &models.Note{Title: note.Title, Path: map[string]string{s.Type(): s.URL(note.Title, false)}, Body: note.Body}, nil
+}
+
+// Copy writes body of note to machine's clipboard.
+func (s *WebDAVService) Copy(note models.Note) error {
+	data, err := s.View(note)
+	if err != nil {
+		return err
+	}
+
+	return clipboard.WriteAll(data.Body)
+}
+
+// Cut, copies note data to machine's clipboard and removes it instantly.
+func (s *WebDAVService) Cut(note models.Note) (*models.Note, error) {
+	n, err := s.View(note)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := clipboard.WriteAll(n.Body); err != nil {
+		return nil, err
+	}
+
+	if err := s.Remove(note.ToNode()); err != nil {
+		return nil, err
+	}
+
+	return n, nil
+}
+
+// Mkdir creates a new collection via MKCOL request.
+func (s *WebDAVService) Mkdir(dir models.Folder) (*models.Folder, error) {
+	if exists, err := s.IsNodeExists(dir.ToNode()); err != nil {
+		return nil, err
+	} else if exists {
+		return nil, assets.AlreadyExists(dir.Title, "folder")
+	}
+
+	if err := s.mkcol(dir.Title); err != nil {
+		return nil, err
+	}
+
+	title := nodeTitle(dir.Title, models.FOLDER)
+	return &models.Folder{Title: title, Path: map[string]string{s.Type(): s.URL(title, true)}}, ClearTombstone(s, title)
+}
+
+// MoveNotes moves the whole collection of notes to the URL of [settings], via a single MOVE request.
+// So, the new URL has to be at the same WebDAV server.
+func (s *WebDAVService) MoveNotes(settings models.Settings) error {
+	if len(settings.WebDAVURL) == 0 || strings.TrimSuffix(settings.WebDAVURL, "/") == strings.TrimSuffix(s.Config.WebDAVURL, "/") {
+		return nil
+	}
+
+	root, err := url.Parse(settings.WebDAVURL)
+	if err != nil || (root.Scheme != "http" && root.Scheme != "https") || len(root.Host) == 0 {
+		return assets.InvalidWebDAVURL
+	}
+
+	root.Path = strings.TrimSuffix(root.Path, "/") + "/"
+
+	header := http.Header{"Destination": {root.String()}, "Overwrite": {"F"}}
+	status, _, err := s.request("MOVE", "", true, nil, header)
+	if err != nil {
+		return err
+	} else if !isSuccess(status) {
+		return statusError("move", s.Config.WebDAVURL, status)
+	}
+
+	s.Config.WebDAVURL, s.Root = settings.WebDAVURL, root
+	return nil
+}
+
+// Fetch creates a clone of nodes(that doesn't exists on [s]) from given [remote] service.
+func (s *WebDAVService) Fetch(remote ServiceRepo) ([]models.Node, []error) {
+	return transfer("fetch", remote, s)
+}
+
+// Push uploads nodes(that doesn't exists on given remote) from [s] to given [remote].
+func (s *WebDAVService) Push(remote ServiceRepo) ([]models.Node, []error) {
+	return transfer("push", s, remote)
+}
+
+// Migrate overwrites all notes of given [remote] service with [s].
+// If migration fails halfway, [remote] is restored back to its previous state.
+func (s *WebDAVService) Migrate(remote ServiceRepo) ([]models.Node, []error) {
+	return Migrate(s, remote)
+}
+
+// readArchive reads the hidden resource at [title] (see [archiveStore]).
+func (s *WebDAVService) readArchive(title string) (string, error) {
+	body, err := s.get(title)
+	if err == errWebDAVNotFound {
+		return "", errArchiveNotFound
+	}
+
+	return body, err
+}
+
+// readArchives reads all resources of hidden collection at [folder] (see [archiveStore]).
+// Resources that cannot be read are skipped.
+func (s *WebDAVService) readArchives(folder string) ([]string, error) {
+	bodies := []string{}
+
+	entries, err := s.propfind(folder+"/", "1")
+	if err == errWebDAVNotFound {
+		return bodies, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	for _, e := range entries {
+		if e.Folder {
+			continue
+		}
+
+		if body, err := s.get(e.Title); err == nil {
+			bodies = append(bodies, body)
+		}
+	}
+
+	return bodies, nil
+}
+
+// writeArchive overwrites the hidden resource at [title], creating its parent collections (see [archiveStore]).
+func (s *WebDAVService) writeArchive(title, body string) error {
+	if err := s.mkcolAll(parentTitle(title)); err != nil {
+		return err
+	}
+
+	return s.put(title, body)
+}
+
+// removeArchive removes the hidden resource at [title] (see [archiveStore]).
+func (s *WebDAVService) removeArchive(title string) error {
+	if err := s.remove(title, false); err != nil && err != errWebDAVNotFound {
+		return err
+	}
+
+	return nil
+}
+
+// PutTrash saves [entry] as a JSON resource at hidden trash collection.
+func (s *WebDAVService) PutTrash(entry models.TrashEntry) error {
+	return archive{s}.PutTrash(entry)
+}
+
+// TrashEntries reads all entry resources of hidden trash collection.
+func (s *WebDAVService) TrashEntries() (models.Trash, error) {
+	return archive{s}.TrashEntries()
+}
+
+// DropTrash deletes the entry resource of [id] from hidden trash collection.
+func (s *WebDAVService) DropTrash(id string) error {
+	return archive{s}.DropTrash(id)
+}
+
+// Versions reads the history resource of note at [title].
+func (s *WebDAVService) Versions(title string) (models.History, error) {
+	return archive{s}.Versions(title)
+}
+
+// DropVersions removes the history resource of note at [title].
+func (s *WebDAVService) DropVersions(title string) error {
+	return archive{s}.DropVersions(title)
+}
+
+// SaveVersion appends [version] to the history resource of its note.
+func (s *WebDAVService) SaveVersion(version models.Version) error {
+	return archive{s}.SaveVersion(version)
+}
@@ -0,0 +1,140 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package services_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/insolite-dev/notya/lib/models"
+	"github.com/insolite-dev/notya/lib/services"
+	"golang.org/x/net/webdav"
+)
+
+// newTestWebDAVService creates a WebDAV service, that's connected to an in-process in-memory WebDAV server.
+// Server requires basic auth of "notya:secret".
+func newTestWebDAVService(t *testing.T) *services.WebDAVService {
+	handler := &webdav.Handler{FileSystem: webdav.NewMemFS(), LockSystem: webdav.NewMemLS()}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, ok := r.BasicAuth(); !ok || username != "notya" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	local := newTestLocalService(t)
+
+	settings := local.Config
+	settings.WebDAVURL = server.URL + "/remote.php/dav/notya"
+	settings.WebDAVUsername = "notya"
+	settings.WebDAVPassword = "secret"
+
+	s := services.NewWebDAVService(local.Stdargs, local)
+	if err := s.Init(&settings); err == nil {
+		t.Fatalf("WebDAVService.Init should fail, if parent collection doesn't exist")
+	}
+
+	settings.WebDAVURL = server.URL + "/notya/"
+	if err := s.Init(&settings); err != nil {
+		t.Fatalf("WebDAVService.Init returned an error: %v", err)
+	}
+
+	return s
+}
+
+func TestWebDAVServiceInit(t *testing.T) {
+	s := newTestWebDAVService(t)
+
+	tests := []struct {
+		settings models.Settings
+	}{
+		{settings: models.Settings{}},
+		{settings: models.Settings{WebDAVURL: "ftp://example.com/notya"}},
+		{settings: models.Settings{WebDAVURL: s.Config.WebDAVURL, WebDAVUsername: "notya", WebDAVPassword: "wrong"}},
+	}
+
+	for _, td := range tests {
+		if err := services.NewWebDAVService(s.Stdargs, s.LS).Init(&td.settings); err == nil {
+			t.Errorf("Init should fail for invalid settings: %v", td.settings)
+		}
+	}
+
+	// Password isn't kept at collection, so it's taken from given settings, when collection has settings already.
+	connected := services.NewWebDAVService(s.Stdargs, s.LS)
+	if err := connected.Init(&models.Settings{WebDAVURL: s.Config.WebDAVURL, WebDAVUsername: "notya", WebDAVPassword: "secret"}); err != nil {
+		t.Fatalf("Init returned an error: %v", err)
+	}
+
+	if _, err := connected.Create(models.Note{Title: "a.md"}); err != nil {
+		t.Errorf("Init should keep connection fields of given settings, Got: %v", err)
+	}
+
+	// Password is taken from environment, if it's not kept at settings.
+	t.Setenv(models.WebDAVPasswordEnv, "secret")
+	settings := models.Settings{WebDAVURL: s.Config.WebDAVURL, WebDAVUsername: "notya"}
+	if err := services.NewWebDAVService(s.Stdargs, s.LS).Init(&settings); err != nil {
+		t.Errorf("Init should use password of environment, Got: %v", err)
+	}
+
+	config, err := s.Settings(nil)
+	if err != nil || config.WebDAVUsername != "notya" {
+		t.Errorf("Init should write settings to collection, Got: %v (%v)", config, err)
+	}
+}
+
+func TestWebDAVServicePropfind(t *testing.T) {
+	s := newTestWebDAVService(t)
+
+	// Resources created by other clients, with escaped names at their hrefs.
+	for _, r := range []struct{ method, path, body string }{
+		{method: "MKCOL", path: "new%20folder/"},
+		{method: http.MethodPut, path: "new%20folder/caf%C3%A9.md", body: "body"},
+		{method: http.MethodPut, path: "100%25.md", body: "body"},
+	} {
+		req, _ := http.NewRequest(r.method, s.Root.String()+r.path, strings.NewReader(r.body))
+		req.SetBasicAuth("notya", "secret")
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%v %v returned an error: %v", r.method, r.path, err)
+		}
+		resp.Body.Close()
+	}
+
+	nodes, _, err := s.GetAll("", "", models.NotyaIgnoreFiles)
+	expected := "[100%.md new folder/ new folder/café.md]"
+	if err != nil || fmt.Sprint(titles(nodes)) != expected {
+		t.Errorf("GetAll should decode names of resources: Want: %v | Got: %v (%v)", expected, titles(nodes), err)
+	}
+
+	if note, err := s.View(models.Note{Title: "new folder/café.md"}); err != nil || note.Body != "body" {
+		t.Errorf("View sum was different: Got: %v (%v)", note, err)
+	}
+}
+
+func TestWebDAVServiceMoveNotes(t *testing.T) {
+	s := newTestWebDAVService(t)
+	s.Create(models.Note{Title: "a.md", Body: "A"})
+
+	settings := s.StateConfig()
+	settings.WebDAVURL = s.Root.Scheme + "://" + s.Root.Host + "/work"
+
+	if err := s.MoveNotes(settings); err != nil {
+		t.Fatalf("MoveNotes returned an error: %v", err)
+	}
+
+	if a, err := s.View(models.Note{Title: "a.md"}); err != nil || a.Path[s.Type()] != settings.WebDAVURL+"/a.md" {
+		t.Errorf("MoveNotes should move notes to new collection, Got: %v (%v)", a, err)
+	}
+}
@@ -0,0 +1,25 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+//go:build darwin
+// +build darwin
+
+package pkg
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// BirthTime returns the creation time of file, by its [info].
+func BirthTime(info os.FileInfo) time.Time {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return time.Unix(stat.Birthtimespec.Unix())
+	}
+
+	return info.ModTime()
+}
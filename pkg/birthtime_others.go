@@ -0,0 +1,22 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+//go:build !darwin && !windows
+// +build !darwin,!windows
+
+package pkg
+
+import (
+	"os"
+	"time"
+)
+
+// BirthTime returns the creation time of file, by its [info].
+// Creation time isn't provided by stat on these platforms,
+// so the modification time is used instead.
+func BirthTime(info os.FileInfo) time.Time {
+	return info.ModTime()
+}
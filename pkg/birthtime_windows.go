@@ -0,0 +1,25 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+//go:build windows
+// +build windows
+
+package pkg
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// BirthTime returns the creation time of file, by its [info].
+func BirthTime(info os.FileInfo) time.Time {
+	if data, ok := info.Sys().(*syscall.Win32FileAttributeData); ok {
+		return time.Unix(0, data.CreationTime.Nanoseconds())
+	}
+
+	return info.ModTime()
+}
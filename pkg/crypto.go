@@ -0,0 +1,138 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package pkg
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+
+	"github.com/insolite-dev/notya/assets"
+	"golang.org/x/crypto/scrypt"
+)
+
+// EncryptedPrefix marks encrypted bodies, so they can be told apart from plain ones.
+//
+//	"notya:aes-gcm:" + base64(nonce + sealed body)
+const EncryptedPrefix = "notya:aes-gcm:"
+
+// VaultPrefix marks bodies encrypted by the key of a vault folder.
+// They're told apart from [EncryptedPrefix], since vaults have their own passphrases.
+//
+//	"notya:vault:" + base64(nonce + sealed body)
+const VaultPrefix = "notya:vault:"
+
+// Key derivation parameters of scrypt, as recommended for interactive logins.
+const (
+	scryptN   = 1 << 15
+	scryptR   = 8
+	scryptP   = 1
+	KeyLength = 32 // AES-256
+	SaltSize  = 16
+)
+
+// Cipher seals and opens note bodies via AES-GCM.
+type Cipher struct {
+	aead   cipher.AEAD
+	prefix string
+}
+
+// NewSalt generates a random salt of key derivation, encoded as base64.
+func NewSalt() (string, error) {
+	salt := make([]byte, SaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(salt), nil
+}
+
+// DeriveKey derives an AES-256 key from [passphrase] and base64 encoded [salt] via scrypt.
+func DeriveKey(passphrase, salt string) ([]byte, error) {
+	rawSalt, err := base64.StdEncoding.DecodeString(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	return scrypt.Key([]byte(passphrase), rawSalt, scryptN, scryptR, scryptP, KeyLength)
+}
+
+// NewCipher creates a new AES-GCM cipher by given [key].
+func NewCipher(key []byte) (*Cipher, error) {
+	return newCipher(key, EncryptedPrefix)
+}
+
+// NewVaultCipher creates a new AES-GCM cipher of vault folders by given [key].
+func NewVaultCipher(key []byte) (*Cipher, error) {
+	return newCipher(key, VaultPrefix)
+}
+
+// newCipher creates a new AES-GCM cipher, that marks sealed bodies with [prefix].
+func newCipher(key []byte, prefix string) (*Cipher, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Cipher{aead: aead, prefix: prefix}, nil
+}
+
+// IsEncrypted checks if [body] was encrypted by [Cipher] (including vault ciphers).
+func IsEncrypted(body string) bool {
+	return strings.HasPrefix(body, EncryptedPrefix) || strings.HasPrefix(body, VaultPrefix)
+}
+
+// Encrypt seals [body] of note at [title] with a random nonce.
+// Title is authenticated as additional data, so a sealed body can't be moved to another note.
+//
+// Body is always encrypted, even if it looks like an encrypted one.
+// Whether a body has to be encrypted is decided by the caller, not by its content.
+func (c *Cipher) Encrypt(title, body string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := c.aead.Seal(nonce, nonce, []byte(body), additionalData(title))
+	return c.prefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens the sealed [body] of note at [title].
+// Plain bodies (and bodies sealed by other kind of cipher) are returned as they are,
+// so notes written before enabling encryption stay readable.
+func (c *Cipher) Decrypt(title, body string) (string, error) {
+	if !strings.HasPrefix(body, c.prefix) {
+		return body, nil
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(body, c.prefix))
+	if err != nil || len(sealed) < c.aead.NonceSize() {
+		return "", assets.CannotDecrypt
+	}
+
+	nonce, sealed := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+
+	plain, err := c.aead.Open(nil, nonce, sealed, additionalData(title))
+	if err != nil {
+		return "", assets.CannotDecrypt
+	}
+
+	return string(plain), nil
+}
+
+// additionalData converts [title] to the additional data of AES-GCM.
+// Leading and trailing slashes are trimmed, so "/ideas/a.md" and "ideas/a.md" are the same note.
+func additionalData(title string) []byte {
+	return []byte(strings.Trim(title, "/"))
+}
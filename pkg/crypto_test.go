@@ -0,0 +1,116 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package pkg_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/insolite-dev/notya/assets"
+	"github.com/insolite-dev/notya/pkg"
+)
+
+func newTestCipher(t *testing.T, b byte) *pkg.Cipher {
+	c, err := pkg.NewCipher(bytes.Repeat([]byte{b}, pkg.KeyLength))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+func newTestVaultCipher(t *testing.T, b byte) *pkg.Cipher {
+	c, err := pkg.NewVaultCipher(bytes.Repeat([]byte{b}, pkg.KeyLength))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+func TestDeriveKey(t *testing.T) {
+	salt, err := pkg.NewSalt()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, _ := pkg.DeriveKey("secret", salt)
+	b, _ := pkg.DeriveKey("secret", salt)
+	c, _ := pkg.DeriveKey("other", salt)
+
+	if len(a) != pkg.KeyLength || !bytes.Equal(a, b) || bytes.Equal(a, c) {
+		t.Errorf("DeriveKey should derive the same key only for the same passphrase, Got: %x, %x, %x", a, b, c)
+	}
+
+	if _, err := pkg.DeriveKey("secret", "not base64!"); err == nil {
+		t.Errorf("DeriveKey should fail on invalid salt")
+	}
+}
+
+func TestCipher(t *testing.T) {
+	c := newTestCipher(t, 1)
+
+	tests := []string{"", "hello\n", "# Title\n\nSome multi-line\nbody."}
+	for _, body := range tests {
+		encrypted, err := c.Encrypt("a.md", body)
+		if err != nil || !pkg.IsEncrypted(encrypted) || (len(body) > 0 && strings.Contains(encrypted, body)) {
+			t.Errorf("Encrypt sum was different for %q: Got: %v (%v)", body, encrypted, err)
+		}
+
+		// Plain bodies, that look like encrypted ones, are encrypted too.
+		again, _ := c.Encrypt("a.md", encrypted)
+		if decrypted, err := c.Decrypt("a.md", again); again == encrypted || err != nil || decrypted != encrypted {
+			t.Errorf("Encrypt should encrypt encrypted-looking bodies: Want: %v | Got: %v (%v)", encrypted, decrypted, err)
+		}
+
+		decrypted, err := c.Decrypt("/a.md", encrypted)
+		if err != nil || decrypted != body {
+			t.Errorf("Decrypt sum was different: Want: %q | Got: %q (%v)", body, decrypted, err)
+		}
+	}
+
+	// Plain bodies are kept as they are.
+	if plain, err := c.Decrypt("a.md", "plain"); err != nil || plain != "plain" {
+		t.Errorf("Decrypt sum was different: Want: %v | Got: %v (%v)", "plain", plain, err)
+	}
+
+	encrypted, _ := c.Encrypt("a.md", "secret")
+	for _, body := range []string{encrypted, pkg.EncryptedPrefix + "broken", encrypted[:len(encrypted)-4]} {
+		if _, err := newTestCipher(t, 2).Decrypt("a.md", body); err != assets.CannotDecrypt {
+			t.Errorf("Decrypt error was different: Want: %v | Got: %v", assets.CannotDecrypt, err)
+		}
+	}
+
+	// Sealed bodies are bound to the title of their note.
+	if _, err := c.Decrypt("b.md", encrypted); err != assets.CannotDecrypt {
+		t.Errorf("Decrypt should fail for another title: Want: %v | Got: %v", assets.CannotDecrypt, err)
+	}
+}
+
+func TestVaultCipher(t *testing.T) {
+	store, vault := newTestCipher(t, 1), newTestVaultCipher(t, 1)
+
+	sealed, err := vault.Encrypt("a.md", "secret")
+	if err != nil || !strings.HasPrefix(sealed, pkg.VaultPrefix) || !pkg.IsEncrypted(sealed) {
+		t.Fatalf("Encrypt sum was different: Got: %v (%v)", sealed, err)
+	}
+
+	// Vault bodies are sealed again by ciphers of encryption.
+	got, _ := store.Encrypt("a.md", sealed)
+	if opened, err := store.Decrypt("a.md", got); !strings.HasPrefix(got, pkg.EncryptedPrefix) || err != nil || opened != sealed {
+		t.Errorf("Encrypt should seal vault bodies again: Want: %v | Got: %v (%v)", sealed, opened, err)
+	}
+
+	if got, err := store.Decrypt("a.md", sealed); err != nil || got != sealed {
+		t.Errorf("Decrypt should keep vault bodies as they are: Want: %v | Got: %v (%v)", sealed, got, err)
+	}
+
+	if got, err := vault.Decrypt("a.md", sealed); err != nil || got != "secret" {
+		t.Errorf("Decrypt sum was different: Want: %v | Got: %v (%v)", "secret", got, err)
+	}
+}
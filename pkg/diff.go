@@ -0,0 +1,258 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package pkg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DiffContext is the amount of unchanged lines, that surround changes at unified diff hunks.
+const DiffContext = 3
+
+// DiffOp is the operation of a single diff line.
+type DiffOp rune
+
+const (
+	DiffEqual  DiffOp = ' '
+	DiffDelete DiffOp = '-'
+	DiffInsert DiffOp = '+'
+)
+
+// DiffLine is a single line of line-level diff.
+// [Text] keeps the trailing newline of line, if it has one.
+type DiffLine struct {
+	Op   DiffOp
+	Text string
+}
+
+// SplitLines splits [s] to its lines, by keeping their trailing newlines.
+//
+//	SplitLines("a\nb") ─▶ ["a\n", "b"]
+func SplitLines(s string) []string {
+	if len(s) == 0 {
+		return []string{}
+	}
+
+	lines := strings.SplitAfter(s, "\n")
+	if len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// DiffLines generates the shortest line-level edit script, that converts [a] to [b].
+// Uses Myers' algorithm, after trimming the common prefix and suffix of both sides.
+func DiffLines(a, b string) []DiffLine {
+	x, y := SplitLines(a), SplitLines(b)
+
+	prefix := 0
+	for prefix < len(x) && prefix < len(y) && x[prefix] == y[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(x)-prefix && suffix < len(y)-prefix && x[len(x)-1-suffix] == y[len(y)-1-suffix] {
+		suffix++
+	}
+
+	res := []DiffLine{}
+	for _, l := range x[:prefix] {
+		res = append(res, DiffLine{Op: DiffEqual, Text: l})
+	}
+
+	res = append(res, myers(x[prefix:len(x)-suffix], y[prefix:len(y)-suffix])...)
+
+	for _, l := range x[len(x)-suffix:] {
+		res = append(res, DiffLine{Op: DiffEqual, Text: l})
+	}
+
+	return res
+}
+
+// myers finds the shortest edit script of [x] to [y].
+// Each step's furthest reaching paths are kept (only for diagonals of that step), to backtrack the script.
+func myers(x, y []string) []DiffLine {
+	n, m := len(x), len(y)
+	if n == 0 && m == 0 {
+		return []DiffLine{}
+	}
+
+	// v maps diagonal k to furthest x, shifted by [offset].
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+	trace := [][]int{}
+
+	get := func(vs []int, d, k int) int { return vs[k+d+1] }
+
+	for d := 0; d <= n+m; d++ {
+		// Snapshot of diagonals [-d-1, d+1], as they were before this step.
+		snapshot := make([]int, 2*d+3)
+		copy(snapshot, v[offset-d-1:offset+d+2])
+		trace = append(trace, snapshot)
+
+		for k := -d; k <= d; k += 2 {
+			var i int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				i = v[offset+k+1]
+			} else {
+				i = v[offset+k-1] + 1
+			}
+
+			j := i - k
+			for i < n && j < m && x[i] == y[j] {
+				i, j = i+1, j+1
+			}
+
+			v[offset+k] = i
+
+			if i >= n && j >= m {
+				return backtrack(x, y, trace, get)
+			}
+		}
+	}
+
+	return []DiffLine{}
+}
+
+// backtrack walks [trace] of [myers] from the end, to build the edit script.
+func backtrack(x, y []string, trace [][]int, get func(vs []int, d, k int) int) []DiffLine {
+	res := []DiffLine{}
+	i, j := len(x), len(y)
+
+	for d := len(trace) - 1; d >= 0; d-- {
+		vs, k := trace[d], i-j
+
+		prevK := k - 1
+		if k == -d || (k != d && get(vs, d, k-1) < get(vs, d, k+1)) {
+			prevK = k + 1
+		}
+
+		prevI := get(vs, d, prevK)
+		prevJ := prevI - prevK
+
+		for i > prevI && j > prevJ {
+			res = append(res, DiffLine{Op: DiffEqual, Text: x[i-1]})
+			i, j = i-1, j-1
+		}
+
+		if d > 0 {
+			if i == prevI {
+				res = append(res, DiffLine{Op: DiffInsert, Text: y[j-1]})
+			} else {
+				res = append(res, DiffLine{Op: DiffDelete, Text: x[i-1]})
+			}
+		}
+
+		i, j = prevI, prevJ
+	}
+
+	// Reverse, since the script was built from the end.
+	for l, r := 0, len(res)-1; l < r; l, r = l+1, r-1 {
+		res[l], res[r] = res[r], res[l]
+	}
+
+	return res
+}
+
+// UnifiedDiff generates a unified diff of [a] and [b], labeled with [from] and [to].
+// Empty string is returned, if there's no difference.
+//
+//	--- from
+//	+++ to
+//	@@ -1,3 +1,3 @@
+//	 unchanged
+//	-removed
+//	+added
+func UnifiedDiff(from, to, a, b string) string {
+	lines := DiffLines(a, b)
+
+	// Line numbers of each diff line at [a] and [b] sides, starting from 1.
+	aLines, bLines := make([]int, len(lines)+1), make([]int, len(lines)+1)
+	aLines[0], bLines[0] = 1, 1
+	for i, l := range lines {
+		aLines[i+1], bLines[i+1] = aLines[i], bLines[i]
+		if l.Op != DiffInsert {
+			aLines[i+1]++
+		}
+
+		if l.Op != DiffDelete {
+			bLines[i+1]++
+		}
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(lines); {
+		if lines[i].Op == DiffEqual {
+			i++
+			continue
+		}
+
+		start := i - DiffContext
+		if start < 0 {
+			start = 0
+		}
+
+		// Extend the hunk, until an unchanged run is long enough to split hunks.
+		end := i
+		for {
+			for end < len(lines) && lines[end].Op != DiffEqual {
+				end++
+			}
+
+			run := 0
+			for end+run < len(lines) && lines[end+run].Op == DiffEqual {
+				run++
+			}
+
+			if end+run == len(lines) || run > 2*DiffContext {
+				if run > DiffContext {
+					run = DiffContext
+				}
+
+				end += run
+				break
+			}
+
+			end += run
+		}
+
+		if sb.Len() == 0 {
+			sb.WriteString(fmt.Sprintf("--- %v\n+++ %v\n", from, to))
+		}
+
+		sb.WriteString(fmt.Sprintf(
+			"@@ -%v +%v @@\n",
+			hunkRange(aLines[start], aLines[end]-aLines[start]),
+			hunkRange(bLines[start], bLines[end]-bLines[start]),
+		))
+
+		for _, l := range lines[start:end] {
+			sb.WriteString(string(l.Op) + strings.TrimSuffix(l.Text, "\n") + "\n")
+			if !strings.HasSuffix(l.Text, "\n") {
+				sb.WriteString("\\ No newline at end of file\n")
+			}
+		}
+
+		i = end
+	}
+
+	return sb.String()
+}
+
+// hunkRange formats the [start, length] range of unified diff hunk header.
+func hunkRange(start, length int) string {
+	switch length {
+	case 0:
+		return fmt.Sprintf("%v,0", start-1)
+	case 1:
+		return fmt.Sprint(start)
+	}
+
+	return fmt.Sprintf("%v,%v", start, length)
+}
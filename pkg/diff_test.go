@@ -0,0 +1,91 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+package pkg_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/insolite-dev/notya/pkg"
+)
+
+func TestSplitLines(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected []string
+	}{
+		{s: "", expected: []string{}},
+		{s: "a", expected: []string{"a"}},
+		{s: "a\nb\n", expected: []string{"a\n", "b\n"}},
+		{s: "a\n\nb", expected: []string{"a\n", "\n", "b"}},
+	}
+
+	for _, td := range tests {
+		got := pkg.SplitLines(td.s)
+		if !reflect.DeepEqual(got, td.expected) {
+			t.Errorf("SplitLines sum was different: Want: %q | Got: %q", td.expected, got)
+		}
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected []pkg.DiffLine
+	}{
+		{a: "", b: "", expected: []pkg.DiffLine{}},
+		{
+			a: "a\nb\nc\n", b: "a\nc\nd\n",
+			expected: []pkg.DiffLine{
+				{Op: pkg.DiffEqual, Text: "a\n"},
+				{Op: pkg.DiffDelete, Text: "b\n"},
+				{Op: pkg.DiffEqual, Text: "c\n"},
+				{Op: pkg.DiffInsert, Text: "d\n"},
+			},
+		},
+		{
+			a: "a", b: "a\n",
+			expected: []pkg.DiffLine{
+				{Op: pkg.DiffDelete, Text: "a"},
+				{Op: pkg.DiffInsert, Text: "a\n"},
+			},
+		},
+	}
+
+	for _, td := range tests {
+		got := pkg.DiffLines(td.a, td.b)
+		if !reflect.DeepEqual(got, td.expected) {
+			t.Errorf("DiffLines sum was different: Want: %v | Got: %v", td.expected, got)
+		}
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected string
+	}{
+		{a: "same\n", b: "same\n", expected: ""},
+		{
+			a: "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n", b: "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n",
+			expected: "--- a\n+++ b\n" +
+				"@@ -1,6 +1,6 @@\n 1\n 2\n-3\n+three\n 4\n 5\n 6\n" +
+				"@@ -8,3 +8,4 @@\n 8\n 9\n 10\n+11\n",
+		},
+		{
+			a: "", b: "new",
+			expected: "--- a\n+++ b\n@@ -0,0 +1 @@\n+new\n\\ No newline at end of file\n",
+		},
+	}
+
+	for _, td := range tests {
+		got := pkg.UnifiedDiff("a", "b", td.a, td.b)
+		if got != td.expected {
+			t.Errorf("UnifiedDiff sum was different: Want: %q | Got: %q", td.expected, got)
+		}
+	}
+}
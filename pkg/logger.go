@@ -8,6 +8,8 @@ package pkg
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/briandowns/spinner"
@@ -149,6 +151,11 @@ func PrintNodes(list []models.Node) {
 			fmt.Sprintf("%s%s%s", YELLOW, value.Pretty[0], NOCOLOR),
 			fmt.Sprintf("%s%s%s", DARKYELLOW, value.Pretty[1], NOCOLOR),
 		)
+
+		if value.Locked {
+			note += fmt.Sprintf(" %s%s%s", GREY, "(locked)", NOCOLOR)
+		}
+
 		text.Println(note)
 	}
 }
@@ -175,6 +182,205 @@ func PrintErrors(act string, errs []error) {
 	}
 }
 
+// PrintPlan, logs given plan of changes, with byte sizes of each change.
+func PrintPlan(plan models.Plan) {
+	for _, c := range plan.Changes {
+		sign, clr := "+", GREEN
+		switch c.Action {
+		case models.UPDATE:
+			sign, clr = "~", YELLOW
+		case models.DELETE:
+			sign, clr = "-", RED
+		}
+
+		change := fmt.Sprintf(
+			" %v %v %v",
+			fmt.Sprintf("%s%s %-6s%s", clr, sign, c.Action, NOCOLOR),
+			c.Title,
+			fmt.Sprintf("%s(%v B)%s", GREY, c.Size, NOCOLOR),
+		)
+		text.Println(change)
+	}
+
+	summary := fmt.Sprintf(
+		"\n %v → %v | %v to create, %v to update, %v to delete",
+		plan.From, plan.To,
+		plan.Count(models.CREATE), plan.Count(models.UPDATE), plan.Count(models.DELETE),
+	)
+	text.Println(summary)
+}
+
+// Highlight wraps each [start, end] range of [s] with provided color.
+func Highlight(s string, ranges [][]int, c string) string {
+	res, last := "", 0
+	for _, r := range ranges {
+		res += s[last:r[0]] + fmt.Sprintf("%s%s%s", c, s[r[0]:r[1]], NOCOLOR)
+		last = r[1]
+	}
+
+	return res + s[last:]
+}
+
+// PrintSearchResults, logs given search results with highlighted matches.
+func PrintSearchResults(results []models.SearchResult) {
+	for _, r := range results {
+		title := fmt.Sprintf(
+			" %v %v",
+			fmt.Sprintf("%s%s%s", GREY, "•", NOCOLOR),
+			Highlight(r.Node.Title, r.TitleRanges, YELLOW),
+		)
+		if r.Score > 0 {
+			title += fmt.Sprintf(" %s(%.2f)%s", GREY, r.Score, NOCOLOR)
+		}
+		text.Println(title)
+
+		for _, m := range r.Matches {
+			line := fmt.Sprintf(
+				"   %v %v",
+				fmt.Sprintf("%s%4d │%s", GREY, m.Line, NOCOLOR),
+				Highlight(m.Text, m.Ranges, YELLOW),
+			)
+			text.Println(line)
+		}
+	}
+}
+
+// PrintLinks, logs given wiki-style links with their line numbers.
+// If [incoming] is enabled, sources of links are logged instead of targets.
+func PrintLinks(links []models.Link, incoming bool) {
+	for _, l := range links {
+		title, clr := l.Resolved, YELLOW
+		switch {
+		case incoming:
+			title = l.From
+		case len(l.Resolved) == 0:
+			title, clr = l.Target, RED
+		}
+
+		printable := fmt.Sprintf(
+			" • %v %v",
+			fmt.Sprintf("%s%s%s", clr, title, NOCOLOR),
+			fmt.Sprintf("%s(%v:%v)%s", GREY, l.From, l.Line, NOCOLOR),
+		)
+		text.Println(printable)
+	}
+}
+
+// PrintTags, logs given tags with amount of notes including them.
+// Tags are ordered by their counts, from most used to least used.
+func PrintTags(counts map[string]int) {
+	tags := []string{}
+	for t := range counts {
+		tags = append(tags, t)
+	}
+
+	sort.Slice(tags, func(i, j int) bool {
+		if counts[tags[i]] == counts[tags[j]] {
+			return tags[i] < tags[j]
+		}
+
+		return counts[tags[i]] > counts[tags[j]]
+	})
+
+	for _, t := range tags {
+		printable := fmt.Sprintf(
+			" • %v %v",
+			fmt.Sprintf("%s#%s%s", YELLOW, t, NOCOLOR),
+			fmt.Sprintf("%s(%v)%s", GREY, counts[t], NOCOLOR),
+		)
+		text.Println(printable)
+	}
+}
+
+// PrintTrash, logs given trash entries with their deletion times.
+// Removed folders are logged with amount of nodes they include.
+func PrintTrash(trash models.Trash) {
+	for _, e := range trash {
+		title := e.Title
+		if e.IsFolder() {
+			title = fmt.Sprintf("%v [%v nodes]", e.Title, len(e.Nodes))
+		}
+
+		printable := fmt.Sprintf(
+			" • %v %v",
+			fmt.Sprintf("%s%s%s", YELLOW, title, NOCOLOR),
+			fmt.Sprintf("%s(removed at %v)%s", GREY, e.DeletedAt.Local().Format("2006-01-02 15:04"), NOCOLOR),
+		)
+		text.Println(printable)
+	}
+}
+
+// PrintVaults, logs given vaults with their lock state, by active [sessions].
+func PrintVaults(vaults []models.Vault, sessions models.VaultSessions) {
+	now := time.Now()
+	for _, v := range vaults {
+		state := fmt.Sprintf("%s(locked)%s", GREY, NOCOLOR)
+		if session := sessions.Find(v.Folder, now); session != nil {
+			state = fmt.Sprintf("%s(unlocked until %v)%s", GREEN, session.Expires.Local().Format("2006-01-02 15:04"), NOCOLOR)
+		}
+
+		text.Println(fmt.Sprintf(" • %v %v", fmt.Sprintf("%s%s%s", YELLOW, v.Folder, NOCOLOR), state))
+	}
+}
+
+// PrintDiff, logs given unified diff by coloring its lines.
+// Removed lines are red, added lines are green, and hunk headers are cyan.
+func PrintDiff(diff string) {
+	for _, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+		clr := NOCOLOR
+		switch {
+		case strings.HasPrefix(line, "---"), strings.HasPrefix(line, "+++"):
+			clr = YELLOW
+		case strings.HasPrefix(line, "@@"):
+			clr = CYAN
+		case strings.HasPrefix(line, "-"):
+			clr = RED
+		case strings.HasPrefix(line, "+"):
+			clr = GREEN
+		case strings.HasPrefix(line, "\\"):
+			clr = GREY
+		}
+
+		text.Println(fmt.Sprintf("%s%s%s", clr, line, NOCOLOR))
+	}
+}
+
+// PrintComparison, logs differences of compared services.
+// Nodes of only one side are logged with their side, and changed notes with their diffs.
+func PrintComparison(c models.Comparison) {
+	for _, n := range c.Nodes {
+		switch n.Side {
+		case models.CURRENT:
+			text.Println(fmt.Sprintf(" %s+ %v%s %s(only at %v)%s", GREEN, n.Title, NOCOLOR, GREY, c.Current, NOCOLOR))
+		case models.AGAINST:
+			text.Println(fmt.Sprintf(" %s- %v%s %s(only at %v)%s", RED, n.Title, NOCOLOR, GREY, c.Against, NOCOLOR))
+		default:
+			text.Println(fmt.Sprintf(" %s~ %v%s", YELLOW, n.Title, NOCOLOR))
+			PrintDiff(n.Diff)
+		}
+	}
+
+	summary := fmt.Sprintf(
+		"\n %v ↔ %v | %v only at %v, %v only at %v, %v changed",
+		c.Current, c.Against,
+		c.Count(models.CURRENT), c.Current, c.Count(models.AGAINST), c.Against, c.Count(models.BOTH),
+	)
+	text.Println(summary)
+}
+
+// PrintHistory, logs given versions of note with their timestamps and sizes.
+func PrintHistory(history models.History) {
+	for _, v := range history {
+		printable := fmt.Sprintf(
+			" • %v %v %v",
+			fmt.Sprintf("%sr%v%s", YELLOW, v.Rev, NOCOLOR),
+			v.Updated.Local().Format("2006-01-02 15:04:05"),
+			fmt.Sprintf("%s(%v B)%s", GREY, v.Size, NOCOLOR),
+		)
+		text.Println(printable)
+	}
+}
+
 // Spinner generates static style notya spinner.
 func Spinner() *spinner.Spinner {
 	s := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
@@ -182,6 +388,27 @@ func Spinner() *spinner.Spinner {
 	return s
 }
 
+// Loader is a spin animator, that's never started while it's disabled.
+// Used to keep output clean at non-interactive mode.
+type Loader struct {
+	*spinner.Spinner
+
+	Disabled bool
+}
+
+// Start starts the spin animation, if loader isn't disabled.
+func (l *Loader) Start() {
+	if !l.Disabled {
+		l.Spinner.Start()
+	}
+}
+
+// LogToStderr redirects all logs to stderr, so stdout is kept for machine-readable output.
+func LogToStderr() {
+	ColorableStd.Stdout = ColorableStd.Stderr
+	color.Output = color.Error
+}
+
 // PrintServices logs given service names by provided color level.
 func PrintServices(c string, services []string) {
 	for _, s := range services {
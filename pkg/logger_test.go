@@ -9,7 +9,9 @@ package pkg_test
 import (
 	"errors"
 	"fmt"
+	"io"
 	"testing"
+	"time"
 
 	"github.com/briandowns/spinner"
 	"github.com/fatih/color"
@@ -148,6 +150,12 @@ func TestPrintNodes(t *testing.T) {
 				{Pretty: []string{"icon", "Test TITLE"}},
 			},
 		},
+		{
+			testName: "should mark locked folder",
+			list: []models.Node{
+				{Type: models.FOLDER, Title: "secrets", Locked: true, Pretty: []string{"icon", "secrets"}},
+			},
+		},
 	}
 
 	for _, td := range tests {
@@ -193,6 +201,104 @@ func TestPrintErrors(t *testing.T) {
 	}
 }
 
+func TestPrintPlan(t *testing.T) {
+	tests := []struct {
+		testName string
+		plan     models.Plan
+	}{
+		{
+			testName: "should show empty plan properly",
+			plan:     models.Plan{Act: "push", From: "LOCAL", To: "FIREBASE"},
+		},
+		{
+			testName: "should show plan properly",
+			plan: models.Plan{
+				Act: "push", From: "LOCAL", To: "FIREBASE",
+				Changes: []models.Change{
+					{Action: models.DELETE, Title: "old.md", Size: 7},
+					{Action: models.CREATE, Title: "ideas/"},
+					{Action: models.UPDATE, Title: "notes.md", Size: 120},
+				},
+			},
+		},
+	}
+
+	for _, td := range tests {
+		t.Run(td.testName, func(t *testing.T) {
+			pkg.PrintPlan(td.plan)
+		})
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		s        string
+		ranges   [][]int
+		expected string
+	}{
+		{s: "no match", expected: "no match"},
+		{s: "buy milk", ranges: [][]int{{4, 8}}, expected: "buy " + pkg.YELLOW + "milk" + pkg.NOCOLOR},
+		{s: "a-a", ranges: [][]int{{0, 1}, {2, 3}}, expected: pkg.YELLOW + "a" + pkg.NOCOLOR + "-" + pkg.YELLOW + "a" + pkg.NOCOLOR},
+	}
+
+	for _, td := range tests {
+		got := pkg.Highlight(td.s, td.ranges, pkg.YELLOW)
+		if got != td.expected {
+			t.Errorf("Highlight sum was different: Want: %q | Got: %q", td.expected, got)
+		}
+	}
+}
+
+func TestPrintSearchResults(t *testing.T) {
+	pkg.PrintSearchResults([]models.SearchResult{
+		{
+			Node:        models.Node{Title: "todo.md"},
+			TitleRanges: [][]int{{0, 4}},
+			Matches:     []models.Match{{Line: 1, Text: "buy milk", Ranges: [][]int{{4, 8}}}},
+		},
+	})
+}
+
+func TestPrintLinks(t *testing.T) {
+	links := []models.Link{
+		{From: "a.md", Target: "b", Resolved: "b.md", Line: 1},
+		{From: "a.md", Target: "missing", Line: 2},
+	}
+
+	pkg.PrintLinks(links, false)
+	pkg.PrintLinks(links, true)
+}
+
+func TestPrintTags(t *testing.T) {
+	pkg.PrintTags(map[string]int{"work": 2, "plan": 1, "home": 2})
+}
+
+func TestPrintTrash(t *testing.T) {
+	pkg.PrintTrash(models.Trash{
+		{ID: "1", Title: "ideas/", Nodes: []models.Node{{Title: "ideas/"}, {Title: "ideas/todo.md"}}},
+		{ID: "2", Title: "todo.md", DeletedAt: time.Now()},
+	})
+}
+
+func TestPrintDiff(t *testing.T) {
+	pkg.PrintDiff(pkg.UnifiedDiff("todo.md@r1", "todo.md", "a\nb\n", "a\nc"))
+}
+
+func TestPrintComparison(t *testing.T) {
+	pkg.PrintComparison(models.Comparison{
+		Current: "LOCAL", Against: "FIREBASE",
+		Nodes: []models.NodeDiff{
+			{Title: "ideas/", Side: models.CURRENT},
+			{Title: "old.md", Side: models.AGAINST},
+			{Title: "todo.md", Side: models.BOTH, Diff: pkg.UnifiedDiff("FIREBASE/todo.md", "LOCAL/todo.md", "a\n", "b\n")},
+		},
+	})
+}
+
+func TestPrintHistory(t *testing.T) {
+	pkg.PrintHistory(models.History{{Rev: 2, Size: 12, Updated: time.Now()}, {Rev: 1, Size: 4}})
+}
+
 func TestSpinner(t *testing.T) {
 	got := pkg.Spinner()
 
@@ -208,3 +314,15 @@ func TestSpinner(t *testing.T) {
 		}
 	}
 }
+
+func TestLoader(t *testing.T) {
+	loader := &pkg.Loader{Spinner: pkg.Spinner(), Disabled: true}
+	loader.Writer = io.Discard
+
+	loader.Start()
+	defer loader.Stop()
+
+	if loader.Active() {
+		t.Errorf("Disabled loader shouldn't be started")
+	}
+}
@@ -0,0 +1,26 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+//go:build !windows
+// +build !windows
+
+package pkg
+
+import (
+	"os"
+	"syscall"
+)
+
+// IsPrivate checks if the file of [info] is owned by current user,
+// and isn't accessible by other users.
+func IsPrivate(info os.FileInfo) bool {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return false
+	}
+
+	return int(stat.Uid) == os.Getuid() && info.Mode().Perm()&0o077 == 0
+}
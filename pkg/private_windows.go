@@ -0,0 +1,20 @@
+//
+// Copyright 2021-present Insolite. All rights reserved.
+// Use of this source code is governed by Apache 2.0 license
+// that can be found in the LICENSE file.
+//
+
+//go:build windows
+// +build windows
+
+package pkg
+
+import "os"
+
+// IsPrivate checks if the file of [info] is owned by current user,
+// and isn't accessible by other users.
+// Permission bits don't describe the access on windows, so files of
+// user directories (like the cache directory) are taken as private.
+func IsPrivate(info os.FileInfo) bool {
+	return true
+}
@@ -7,11 +7,18 @@
 package pkg
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
 	"os"
 	"os/exec"
+	"path"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/insolite-dev/notya/assets"
 	"github.com/insolite-dev/notya/lib/models"
 )
 
@@ -202,6 +209,22 @@ func IsDir(path string) bool {
 	return i.IsDir()
 }
 
+// IsPiped checks if [r] is a pipe (or a redirected file), instead of a terminal.
+// Readers those aren't files (like buffers of tests) are always piped.
+func IsPiped(r io.Reader) bool {
+	f, ok := r.(*os.File)
+	if !ok {
+		return r != nil
+	}
+
+	i, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return i.Mode()&os.ModeCharDevice == 0
+}
+
 // NormalizePath normalizes given path and returns a normalized path.
 func NormalizePath(path string) string {
 	// re-built [path].
@@ -237,6 +260,12 @@ func IsPathUpdated(old, current models.Settings, t string) bool {
 		return NormalizePath(old.NotesPath) != NormalizePath(current.NotesPath)
 	case "FIREBASE":
 		return old.FirebaseCollection != current.FirebaseCollection
+	case "S3":
+		return old.S3Bucket != current.S3Bucket || old.S3Path() != current.S3Path()
+	case "WEBDAV":
+		return strings.TrimSuffix(old.WebDAVURL, "/") != strings.TrimSuffix(current.WebDAVURL, "/")
+	case "SFTP":
+		return old.SFTPHost == current.SFTPHost && path.Clean(old.SFTPPath) != path.Clean(current.SFTPPath)
 	}
 
 	return false
@@ -253,5 +282,87 @@ func IsSettingsUpdated(old, current models.Settings) bool {
 		NormalizePath(old.NotesPath) != NormalizePath(current.NotesPath) ||
 		old.FirebaseProjectID != current.FirebaseProjectID ||
 		old.FirebaseAccountKey != current.FirebaseAccountKey ||
-		old.FirebaseCollection != current.FirebaseCollection
+		old.FirebaseCollection != current.FirebaseCollection ||
+		old.SFTPHost != current.SFTPHost ||
+		old.SFTPUser != current.SFTPUser ||
+		old.SFTPKeyFile != current.SFTPKeyFile ||
+		old.SFTPPath != current.SFTPPath ||
+		old.SFTPKnownHosts != current.SFTPKnownHosts ||
+		old.S3Endpoint != current.S3Endpoint ||
+		old.S3Bucket != current.S3Bucket ||
+		old.S3Region != current.S3Region ||
+		old.S3CredentialsFile != current.S3CredentialsFile ||
+		old.S3Prefix != current.S3Prefix ||
+		old.WebDAVURL != current.WebDAVURL ||
+		old.WebDAVUsername != current.WebDAVUsername ||
+		old.WebDAVPassword != current.WebDAVPassword ||
+		old.HTTPURL != current.HTTPURL ||
+		old.HTTPToken != current.HTTPToken ||
+		old.Encryption != current.Encryption
+}
+
+// HashBody generates a hex encoded sha256 hash of given note body.
+// Used to compare bodies of notes without keeping whole body in memory.
+func HashBody(body string) string {
+	sum := sha256.Sum256([]byte(body))
+	return hex.EncodeToString(sum[:])
+}
+
+// ParseAge converts a human readable age to duration.
+// Besides the [time.ParseDuration] units, it supports days(d) and weeks(w).
+//
+//	ParseAge("7d") ─▶ 168h0m0s
+//	ParseAge("2w") ─▶ 336h0m0s
+func ParseAge(age string) (time.Duration, error) {
+	units := map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+
+	if len(age) > 1 {
+		if unit, ok := units[age[len(age)-1:]]; ok {
+			n, err := strconv.ParseFloat(age[:len(age)-1], 64)
+			if err == nil && n >= 0 {
+				return time.Duration(n * float64(unit)), nil
+			}
+		}
+	}
+
+	d, err := time.ParseDuration(age)
+	if err != nil || d < 0 {
+		return 0, assets.InvalidAge(age)
+	}
+
+	return d, nil
+}
+
+// SortNodes sorts [nodes] by provided field: title, created, modified or size.
+// Titles are sorted alphabetically, times from newest to oldest, and sizes from largest to smallest.
+func SortNodes(nodes []models.Node, by string) error {
+	var less func(a, b models.Node) bool
+
+	switch strings.ToLower(by) {
+	case "title":
+		less = func(a, b models.Node) bool { return a.Title < b.Title }
+	case "created":
+		less = func(a, b models.Node) bool { return a.Created.After(b.Created) }
+	case "modified", "updated":
+		less = func(a, b models.Node) bool { return a.Updated.After(b.Updated) }
+	case "size":
+		less = func(a, b models.Node) bool { return a.Size > b.Size }
+	default:
+		return assets.InvalidSortField(by)
+	}
+
+	sort.SliceStable(nodes, func(i, j int) bool { return less(nodes[i], nodes[j]) })
+	return nil
+}
+
+// NodesSince filters [nodes], that were modified after [since].
+func NodesSince(nodes []models.Node, since time.Time) []models.Node {
+	res := []models.Node{}
+	for _, n := range nodes {
+		if n.Updated.After(since) {
+			res = append(res, n)
+		}
+	}
+
+	return res
 }
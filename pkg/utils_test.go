@@ -8,8 +8,12 @@ package pkg_test
 
 import (
 	"errors"
+	"fmt"
+	"io"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/insolite-dev/notya/lib/models"
 	"github.com/insolite-dev/notya/pkg"
@@ -320,6 +324,30 @@ func TestIsDir(t *testing.T) {
 	}
 }
 
+func TestIsPiped(t *testing.T) {
+	pipeReader, pipeWriter, _ := os.Pipe()
+	defer pipeReader.Close()
+	defer pipeWriter.Close()
+
+	tests := []struct {
+		testName string
+		reader   io.Reader
+		expected bool
+	}{
+		{"should not be piped, if reader is nil", nil, false},
+		{"should be piped, if reader is a buffer", strings.NewReader("body"), true},
+		{"should be piped, if reader is a pipe", pipeReader, true},
+	}
+
+	for _, td := range tests {
+		t.Run(td.testName, func(t *testing.T) {
+			if got := pkg.IsPiped(td.reader); got != td.expected {
+				t.Errorf("IsPiped sum was different: Want: %v | Got: %v", td.expected, got)
+			}
+		})
+	}
+}
+
 func TestNormalizePath(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -395,6 +423,42 @@ func TestIsPathUpdated(t *testing.T) {
 			current:     models.Settings{FirebaseCollection: "new/test/path"},
 			expected:    true,
 		},
+		{
+			serviceType: "S3",
+			old:         models.Settings{S3Bucket: "notes", S3Prefix: "test/path"},
+			current:     models.Settings{S3Bucket: "notes", S3Prefix: "new/test/path"},
+			expected:    true,
+		},
+		{
+			serviceType: "S3",
+			old:         models.Settings{S3Bucket: "notes", S3Prefix: "test/path/"},
+			current:     models.Settings{S3Bucket: "notes", S3Prefix: "test/path"},
+			expected:    false,
+		},
+		{
+			serviceType: "WEBDAV",
+			old:         models.Settings{WebDAVURL: "https://dav.example.com/notya/"},
+			current:     models.Settings{WebDAVURL: "https://dav.example.com/notes/"},
+			expected:    true,
+		},
+		{
+			serviceType: "WEBDAV",
+			old:         models.Settings{WebDAVURL: "https://dav.example.com/notya/"},
+			current:     models.Settings{WebDAVURL: "https://dav.example.com/notya"},
+			expected:    false,
+		},
+		{
+			serviceType: "SFTP",
+			old:         models.Settings{SFTPHost: "dev.example.com", SFTPPath: "/srv/notya"},
+			current:     models.Settings{SFTPHost: "dev.example.com", SFTPPath: "/srv/notes/"},
+			expected:    true,
+		},
+		{
+			serviceType: "SFTP",
+			old:         models.Settings{SFTPHost: "dev.example.com", SFTPPath: "/srv/notya"},
+			current:     models.Settings{SFTPHost: "box.example.com", SFTPPath: "/srv/notes"},
+			expected:    false,
+		},
 		{
 			serviceType: "undefined",
 			old:         models.Settings{FirebaseCollection: "test/path"},
@@ -442,3 +506,98 @@ func TestIsUpdated(t *testing.T) {
 		})
 	}
 }
+
+func TestHashBody(t *testing.T) {
+	tests := []struct {
+		body     string
+		expected string
+	}{
+		{body: "", expected: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+		{body: "notya", expected: "afda55a34ddf212c049a3ab861230f5ac6888546a9c628b507b54ad4a5992e51"},
+	}
+
+	for _, td := range tests {
+		got := pkg.HashBody(td.body)
+		if got != td.expected {
+			t.Errorf("HashBody sum was different: Got: %v | Want: %v", got, td.expected)
+		}
+	}
+}
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		age      string
+		expected time.Duration
+		err      bool
+	}{
+		{age: "7d", expected: 7 * 24 * time.Hour},
+		{age: "2w", expected: 14 * 24 * time.Hour},
+		{age: "1.5d", expected: 36 * time.Hour},
+		{age: "90m", expected: 90 * time.Minute},
+		{age: "d", err: true},
+		{age: "-2h", err: true},
+		{age: "soon", err: true},
+	}
+
+	for _, td := range tests {
+		got, err := pkg.ParseAge(td.age)
+		if (err != nil) != td.err || got != td.expected {
+			t.Errorf("ParseAge sum was different: Want: %v | Got: %v, %v", td.expected, got, err)
+		}
+	}
+}
+
+func TestSortNodes(t *testing.T) {
+	now := time.Now()
+	nodes := []models.Node{
+		{Title: "b.md", Created: now.Add(-3 * time.Hour), Updated: now.Add(-1 * time.Hour), Size: 10},
+		{Title: "a.md", Created: now.Add(-1 * time.Hour), Updated: now.Add(-2 * time.Hour), Size: 5},
+		{Title: "c.md", Created: now.Add(-2 * time.Hour), Updated: now, Size: 20},
+	}
+
+	tests := []struct {
+		by       string
+		expected string
+		err      bool
+	}{
+		{by: "title", expected: "[a.md b.md c.md]"},
+		{by: "created", expected: "[a.md c.md b.md]"},
+		{by: "modified", expected: "[c.md b.md a.md]"},
+		{by: "size", expected: "[c.md b.md a.md]"},
+		{by: "color", err: true},
+	}
+
+	for _, td := range tests {
+		err := pkg.SortNodes(nodes, td.by)
+		if (err != nil) != td.err {
+			t.Errorf("SortNodes error was different: Got: %v", err)
+			continue
+		}
+
+		if td.err {
+			continue
+		}
+
+		titles := []string{}
+		for _, n := range nodes {
+			titles = append(titles, n.Title)
+		}
+
+		if got := fmt.Sprint(titles); got != td.expected {
+			t.Errorf("SortNodes(%v) sum was different: Got: %v | Want: %v", td.by, got, td.expected)
+		}
+	}
+}
+
+func TestNodesSince(t *testing.T) {
+	now := time.Now()
+	nodes := []models.Node{
+		{Title: "old.md", Updated: now.Add(-10 * 24 * time.Hour)},
+		{Title: "new.md", Updated: now.Add(-time.Hour)},
+	}
+
+	got := pkg.NodesSince(nodes, now.Add(-7*24*time.Hour))
+	if len(got) != 1 || got[0].Title != "new.md" {
+		t.Errorf("NodesSince sum was different: Got: %v", got)
+	}
+}